@@ -0,0 +1,274 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Ryanair/gofrlib/errorUtils"
+	"github.com/Ryanair/gofrlib/log"
+	"go.uber.org/zap"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names of the sources a setting can be loaded from, as logged in log.ConfigSources.
+const (
+	DefaultSource        = "default"
+	EnvSource            = "env"
+	ParameterStoreSource = "ssm"
+	SecretsManagerSource = "secretsmanager"
+
+	defaultTTL = 5 * time.Minute
+)
+
+// Source looks up the value of key, e.g. the name of an SSM parameter or the id of a secret.
+type Source interface {
+	Lookup(ctx context.Context, key string) (value string, found bool, err error)
+}
+
+// SourceFunc adapts a function to Source, e.g. a call to GetParameter of the SSM client.
+type SourceFunc func(ctx context.Context, key string) (string, bool, error)
+
+func (f SourceFunc) Lookup(ctx context.Context, key string) (string, bool, error) {
+	return f(ctx, key)
+}
+
+// Loader loads typed settings into the exported fields of a struct from their tags, each one overriding the previous:
+//
+//	default:"5s"                  the value when no source has one
+//	env:"TABLE_NAME"              the environment variable
+//	ssm:"/bookings/table"         the SSM parameter, decrypted
+//	secret:"bookings/db#password" the secret, or the password key of its json object
+//	required:"true"               fails the load when no source has a value
+//
+// Strings, including log.Secret, booleans, numbers, time.Duration and comma separated []string are supported.
+// Parameters and secrets are cached for the TTL of the loader. Every load logs a "Configuration loaded" record
+// with the source of every setting, log.ConfigSources, never their values.
+type Loader struct {
+	parameters Source
+	secrets    Source
+	ttl        time.Duration
+
+	mutex sync.Mutex
+	cache map[string]cachedValue
+}
+
+type cachedValue struct {
+	value   string
+	found   bool
+	expires time.Time
+}
+
+// NewLoader returns a Loader reading parameters and secrets through the AWS Parameters and Secrets Lambda Extension,
+// see ExtensionParameterSource and ExtensionSecretSource, caching them for 5 minutes.
+func NewLoader() *Loader {
+	return &Loader{
+		parameters: ExtensionParameterSource(),
+		secrets:    ExtensionSecretSource(),
+		ttl:        defaultTTL,
+		cache:      map[string]cachedValue{},
+	}
+}
+
+func (l *Loader) WithParameterSource(source Source) *Loader {
+	l.parameters = source
+	return l
+}
+
+func (l *Loader) WithSecretSource(source Source) *Loader {
+	l.secrets = source
+	return l
+}
+
+// WithTTL sets how long parameters and secrets are cached, 0 disables the cache.
+func (l *Loader) WithTTL(ttl time.Duration) *Loader {
+	l.ttl = ttl
+	return l
+}
+
+var defaultLoader = NewLoader()
+
+// Load loads the settings of target, a pointer to a struct, with the default Loader, see NewLoader.
+func Load(ctx context.Context, target interface{}) error {
+	return defaultLoader.Load(ctx, target)
+}
+
+// Load loads the settings of target, a pointer to a struct. Malformed values, missing required settings
+// and failed lookups are reported together in the returned error, the other settings are loaded anyway.
+func (l *Loader) Load(ctx context.Context, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unable to load configuration into %T: it's not a pointer to a struct", target)
+	}
+	value = value.Elem()
+	sources := map[string]string{}
+	var errs []error
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		raw, source, err := l.lookup(ctx, field.Tag)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to load %s: %v", field.Name, err))
+			continue
+		}
+		if source == "" {
+			if field.Tag.Get("required") == "true" {
+				errs = append(errs, fmt.Errorf("%s is required", field.Name))
+			}
+			continue
+		}
+		if err := decode(value.Field(i), raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s from %s: %v", field.Name, source, err))
+			continue
+		}
+		sources[field.Name] = source
+	}
+	log.FromContext(ctx).Desugar().WithOptions(zap.AddCallerSkip(1)).Sugar().
+		Infow("Configuration loaded", log.ConfigSources, sources)
+	return errorUtils.MergeErrors(errs)
+}
+
+// lookup returns the value of the last source of tag holding one and its name, no name when none does.
+func (l *Loader) lookup(ctx context.Context, tag reflect.StructTag) (string, string, error) {
+	var raw, source string
+	if value, ok := tag.Lookup("default"); ok {
+		raw, source = value, DefaultSource
+	}
+	if name := tag.Get("env"); name != "" {
+		if value, ok := os.LookupEnv(name); ok {
+			raw, source = value, EnvSource
+		}
+	}
+	if name := tag.Get("ssm"); name != "" && l.parameters != nil {
+		value, found, err := l.cached(ctx, ParameterStoreSource, l.parameters, name)
+		if err != nil {
+			return "", "", err
+		}
+		if found {
+			raw, source = value, ParameterStoreSource
+		}
+	}
+	if id := tag.Get("secret"); id != "" && l.secrets != nil {
+		value, found, err := l.secret(ctx, id)
+		if err != nil {
+			return "", "", err
+		}
+		if found {
+			raw, source = value, SecretsManagerSource
+		}
+	}
+	return raw, source, nil
+}
+
+// secret looks up id, picking the key after # of the json object of the secret when there is one.
+func (l *Loader) secret(ctx context.Context, id string) (string, bool, error) {
+	secretId, key := id, ""
+	if i := strings.LastIndex(id, "#"); i >= 0 {
+		secretId, key = id[:i], id[i+1:]
+	}
+	value, found, err := l.cached(ctx, SecretsManagerSource, l.secrets, secretId)
+	if err != nil || !found || key == "" {
+		return value, found, err
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &document); err != nil {
+		return "", false, fmt.Errorf("secret %s is not a json object", secretId)
+	}
+	keyValue, found := document[key]
+	if !found {
+		return "", false, nil
+	}
+	if text, ok := keyValue.(string); ok {
+		return text, true, nil
+	}
+	return fmt.Sprint(keyValue), true, nil
+}
+
+func (l *Loader) cached(ctx context.Context, sourceName string, source Source, key string) (string, bool, error) {
+	cacheKey := sourceName + ":" + key
+	l.mutex.Lock()
+	entry, ok := l.cache[cacheKey]
+	l.mutex.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.value, entry.found, nil
+	}
+	value, found, err := source.Lookup(ctx, key)
+	if err != nil {
+		return "", false, err
+	}
+	if l.ttl > 0 {
+		l.mutex.Lock()
+		l.cache[cacheKey] = cachedValue{value: value, found: found, expires: time.Now().Add(l.ttl)}
+		l.mutex.Unlock()
+	}
+	return value, found, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// decode sets field to raw, the errors never hold raw, it may be a secret.
+func decode(field reflect.Value, raw string) error {
+	if !set(field, raw) {
+		return fmt.Errorf("not a valid %s", field.Type())
+	}
+	return nil
+}
+
+func set(field reflect.Value, raw string) bool {
+	if field.Type() == durationType {
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return false
+		}
+		field.SetInt(int64(duration))
+		return true
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return false
+		}
+		field.SetBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return false
+		}
+		field.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return false
+		}
+		field.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return false
+		}
+		field.SetFloat(value)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return false
+		}
+		values := reflect.MakeSlice(field.Type(), 0, 0)
+		for _, part := range strings.Split(raw, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = reflect.Append(values, reflect.ValueOf(part).Convert(field.Type().Elem()))
+			}
+		}
+		field.Set(values)
+	default:
+		return false
+	}
+	return true
+}
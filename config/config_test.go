@@ -0,0 +1,127 @@
+package config_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/config"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func setEnv(t *testing.T, values map[string]string) {
+	for name, value := range values {
+		assert.NoError(t, os.Setenv(name, value))
+	}
+	t.Cleanup(func() {
+		for name := range values {
+			_ = os.Unsetenv(name)
+		}
+	})
+}
+
+func mapSource(values map[string]string, lookups *int) config.Source {
+	return config.SourceFunc(func(ctx context.Context, key string) (string, bool, error) {
+		*lookups++
+		value, found := values[key]
+		return value, found, nil
+	})
+}
+
+type settings struct {
+	TableName string        `env:"TABLE_NAME" ssm:"/bookings/table"`
+	Timeout   time.Duration `env:"TIMEOUT" default:"5s"`
+	Retries   int           `env:"RETRIES" default:"3"`
+	Regions   []string      `env:"REGIONS"`
+	Password  log.Secret    `secret:"bookings/db#password" required:"true"`
+	ApiKey    string        `secret:"bookings/api-key"`
+}
+
+func TestLoad(t *testing.T) {
+	recorder := logtest.Capture(t)
+	setEnv(t, map[string]string{
+		"TABLE_NAME": "env-table",
+		"TIMEOUT":    "2s",
+		"REGIONS":    "eu-west-1, eu-central-1",
+	})
+	var parameterLookups, secretLookups int
+	loader := config.NewLoader().
+		WithParameterSource(mapSource(map[string]string{"/bookings/table": "ssm-table"}, &parameterLookups)).
+		WithSecretSource(mapSource(map[string]string{"bookings/db": `{"password":"s3cr3t"}`}, &secretLookups))
+
+	var loaded settings
+	err := loader.Load(context.Background(), &loaded)
+
+	assert.NoError(t, err)
+	assert.Equal(t, settings{
+		TableName: "ssm-table",
+		Timeout:   2 * time.Second,
+		Retries:   3,
+		Regions:   []string{"eu-west-1", "eu-central-1"},
+		Password:  "s3cr3t",
+	}, loaded)
+	recorder.AssertField(log.ConfigSources, map[string]string{
+		"TableName": config.ParameterStoreSource,
+		"Timeout":   config.EnvSource,
+		"Retries":   config.DefaultSource,
+		"Regions":   config.EnvSource,
+		"Password":  config.SecretsManagerSource,
+	})
+
+	assert.NoError(t, loader.Load(context.Background(), &loaded))
+	assert.Equal(t, 1, parameterLookups)
+	assert.Equal(t, 2, secretLookups)
+}
+
+func TestLoadReportsErrors(t *testing.T) {
+	logtest.Capture(t)
+	setEnv(t, map[string]string{"RETRIES": "many"})
+	loader := config.NewLoader().
+		WithParameterSource(nil).
+		WithSecretSource(config.SourceFunc(func(ctx context.Context, key string) (string, bool, error) {
+			return "", false, nil
+		}))
+
+	var loaded settings
+	err := loader.Load(context.Background(), &loaded)
+
+	assert.EqualError(t, err, "Retries from env: not a valid int\nPassword is required")
+	assert.Equal(t, 5*time.Second, loaded.Timeout)
+	assert.Error(t, loader.Load(context.Background(), loaded))
+}
+
+func TestExtensionSources(t *testing.T) {
+	logtest.Capture(t)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "session-token", request.Header.Get("X-Aws-Parameters-Secrets-Token"))
+		switch {
+		case request.URL.Path == "/systemsmanager/parameters/get" && request.URL.Query().Get("name") == "/bookings/table":
+			assert.Equal(t, "true", request.URL.Query().Get("withDecryption"))
+			_, _ = writer.Write([]byte(`{"Parameter":{"Name":"/bookings/table","Value":"ssm-table"}}`))
+		case request.URL.Path == "/secretsmanager/get" && request.URL.Query().Get("secretId") == "bookings/db":
+			_, _ = writer.Write([]byte(`{"SecretString":"{\"password\":\"s3cr3t\"}"}`))
+		default:
+			writer.WriteHeader(http.StatusBadRequest)
+			_, _ = writer.Write([]byte(`{"__type":"ResourceNotFoundException"}`))
+		}
+	}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	setEnv(t, map[string]string{
+		"PARAMETERS_SECRETS_EXTENSION_HTTP_PORT": port,
+		"AWS_SESSION_TOKEN":                      "session-token",
+	})
+
+	var loaded settings
+	err := config.NewLoader().Load(context.Background(), &loaded)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ssm-table", loaded.TableName)
+	assert.Equal(t, log.Secret("s3cr3t"), loaded.Password)
+	assert.Empty(t, loaded.ApiKey)
+}
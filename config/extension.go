@@ -0,0 +1,83 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	extensionPortEnv     = "PARAMETERS_SECRETS_EXTENSION_HTTP_PORT"
+	extensionDefaultPort = "2773"
+	extensionTokenHeader = "X-Aws-Parameters-Secrets-Token"
+	sessionTokenEnv      = "AWS_SESSION_TOKEN"
+	extensionTimeout     = 3 * time.Second
+)
+
+var extensionClient = &http.Client{Timeout: extensionTimeout}
+
+// ExtensionParameterSource looks up decrypted SSM parameters through the AWS Parameters and Secrets Lambda Extension,
+// which must be added as a layer of the function, so this module doesn't depend on the SSM client.
+func ExtensionParameterSource() Source {
+	return SourceFunc(func(ctx context.Context, name string) (string, bool, error) {
+		var response struct {
+			Parameter struct {
+				Value string `json:"Value"`
+			} `json:"Parameter"`
+		}
+		query := url.Values{"name": {name}, "withDecryption": {"true"}}
+		found, err := getExtension(ctx, "/systemsmanager/parameters/get", query, &response)
+		return response.Parameter.Value, found, err
+	})
+}
+
+// ExtensionSecretSource looks up the secret strings of Secrets Manager through the AWS Parameters and Secrets
+// Lambda Extension, see ExtensionParameterSource.
+func ExtensionSecretSource() Source {
+	return SourceFunc(func(ctx context.Context, id string) (string, bool, error) {
+		var response struct {
+			SecretString string `json:"SecretString"`
+		}
+		found, err := getExtension(ctx, "/secretsmanager/get", url.Values{"secretId": {id}}, &response)
+		return response.SecretString, found, err
+	})
+}
+
+// getExtension decodes the response of the extension into response, the errors never hold its body.
+func getExtension(ctx context.Context, path string, query url.Values, response interface{}) (bool, error) {
+	port := os.Getenv(extensionPortEnv)
+	if port == "" {
+		port = extensionDefaultPort
+	}
+	request, err := http.NewRequest(http.MethodGet, "http://localhost:"+port+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set(extensionTokenHeader, os.Getenv(sessionTokenEnv))
+	httpResponse, err := extensionClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("parameters and secrets extension unavailable: %v", err)
+	}
+	defer httpResponse.Body.Close()
+	body, err := ioutil.ReadAll(httpResponse.Body)
+	if err != nil {
+		return false, err
+	}
+	if httpResponse.StatusCode != http.StatusOK {
+		if strings.Contains(string(body), "NotFound") {
+			return false, nil
+		}
+		return false, fmt.Errorf("parameters and secrets extension responded %d", httpResponse.StatusCode)
+	}
+	if err := json.Unmarshal(body, response); err != nil {
+		return false, errors.New("malformed response of the parameters and secrets extension")
+	}
+	return true, nil
+}
@@ -0,0 +1,169 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/metrics"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.uber.org/zap"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Message attributes describing why and from where a record was dead lettered.
+const (
+	ErrorAttribute    = "DeadLetterError"
+	SourceAttribute   = "DeadLetterSource"
+	RecordIdAttribute = "DeadLetterRecordId"
+	EncodingAttribute = "DeadLetterEncoding"
+
+	DeadLettersMetric = "DeadLetters"
+
+	maxErrorLength = 1024
+)
+
+type SQSAPI interface {
+	SendMessage(ctx context.Context, input *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+var client SQSAPI
+
+// SetClient sets the client LogAndDeadLetter publishes with, it should be called once at start up.
+func SetClient(c SQSAPI) {
+	client = c
+}
+
+// deadLetter is a failed record as sent to the dead-letter queue.
+type deadLetter struct {
+	ctx    context.Context
+	source string
+	arn    string
+	id     string
+	body   []byte
+	fields []interface{}
+}
+
+// LogAndDeadLetter logs the failure of record, an events.SQSMessage or events.KinesisEventRecord, at ERROR
+// with its ids, receive count, error chain and body, redacted like every record, and sends it to the queue of dlqURL
+// with the client of SetClient. The message carries the trace and correlation ids of the record, or of ctx,
+// and the DeadLetter* attributes; binary Kinesis data is sent base64 encoded. A DeadLetters metric is emitted,
+// dimensioned by EventSource. The error returned is the one of publishing, the record is logged anyway.
+func LogAndDeadLetter(ctx context.Context, record interface{}, err error, dlqURL string) error {
+	letter, ok := newDeadLetter(ctx, record)
+	if !ok {
+		return fmt.Errorf("unable to dead letter %T: only SQS messages and Kinesis records are supported", record)
+	}
+	log.FromContext(letter.ctx).Desugar().WithOptions(zap.AddCallerSkip(1)).Sugar().
+		Errorw("Dead lettering record", append(letter.fields, log.ErrorFields(err)...)...)
+	metrics.NewRecordFromContext(letter.ctx, metrics.Dim("EventSource", letter.source)).
+		Count(DeadLettersMetric, 1).
+		Emit()
+
+	if client == nil {
+		return errors.New("unable to dead letter record: the client is not set, see SetClient")
+	}
+	input := letter.message(dlqURL, err)
+	log.InjectTraceSqs(letter.ctx, input)
+	if _, sendErr := client.SendMessage(ctx, input); sendErr != nil {
+		return fmt.Errorf("unable to dead letter record %s: %v", letter.id, sendErr)
+	}
+	return nil
+}
+
+func newDeadLetter(ctx context.Context, record interface{}) (deadLetter, bool) {
+	switch r := record.(type) {
+	case events.SQSMessage:
+		return sqsDeadLetter(ctx, r), true
+	case *events.SQSMessage:
+		return sqsDeadLetter(ctx, *r), true
+	case events.KinesisEventRecord:
+		return kinesisDeadLetter(ctx, r), true
+	case *events.KinesisEventRecord:
+		return kinesisDeadLetter(ctx, *r), true
+	}
+	return deadLetter{}, false
+}
+
+func sqsDeadLetter(ctx context.Context, message events.SQSMessage) deadLetter {
+	if traceContext, ok := log.TraceContextFromSqs(message); ok {
+		if _, exists := log.TraceContextFromContext(ctx); !exists {
+			ctx = log.ContextWithTraceContext(ctx, traceContext)
+		}
+	}
+	if log.CorrelationIdFromContext(ctx) == "" {
+		if correlationId := log.SqsCorrelationSource(message)(); correlationId != "" {
+			ctx = log.ContextWithCorrelationId(ctx, correlationId)
+		}
+	}
+	fields := []interface{}{
+		log.EventSource, message.EventSource,
+		log.MessageId, message.MessageId,
+		log.QueueArn, message.EventSourceARN,
+	}
+	if receiveCount, err := strconv.Atoi(message.Attributes["ApproximateReceiveCount"]); err == nil {
+		fields = append(fields, log.ReceiveCount, receiveCount)
+	}
+	return deadLetter{
+		ctx:    ctx,
+		source: message.EventSource,
+		arn:    message.EventSourceARN,
+		id:     message.MessageId,
+		body:   []byte(message.Body),
+		fields: append(fields, log.EventBody, message.Body),
+	}
+}
+
+func kinesisDeadLetter(ctx context.Context, record events.KinesisEventRecord) deadLetter {
+	return deadLetter{
+		ctx:    ctx,
+		source: record.EventSource,
+		arn:    record.EventSourceArn,
+		id:     record.Kinesis.SequenceNumber,
+		body:   record.Kinesis.Data,
+		fields: []interface{}{
+			log.EventSource, record.EventSource,
+			log.PartitionKey, record.Kinesis.PartitionKey,
+			log.SequenceNumber, record.Kinesis.SequenceNumber,
+			log.EventBody, string(record.Kinesis.Data),
+		},
+	}
+}
+
+func (l deadLetter) message(dlqURL string, err error) *sqs.SendMessageInput {
+	body := string(l.body)
+	attributes := map[string]types.MessageAttributeValue{}
+	setAttribute(attributes, SourceAttribute, l.arn)
+	setAttribute(attributes, RecordIdAttribute, l.id)
+	if !utf8.Valid(l.body) {
+		body = base64.StdEncoding.EncodeToString(l.body)
+		setAttribute(attributes, EncodingAttribute, "base64")
+	}
+	if err != nil {
+		message := err.Error()
+		if len(message) > maxErrorLength {
+			message = message[:maxErrorLength]
+			for !utf8.ValidString(message) {
+				message = message[:len(message)-1]
+			}
+		}
+		setAttribute(attributes, ErrorAttribute, message)
+	}
+	return &sqs.SendMessageInput{
+		QueueUrl:          aws.String(dlqURL),
+		MessageBody:       aws.String(body),
+		MessageAttributes: attributes,
+	}
+}
+
+// setAttribute sets a string attribute, SQS rejects empty values.
+func setAttribute(attributes map[string]types.MessageAttributeValue, name, value string) {
+	if value != "" {
+		attributes[name] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+}
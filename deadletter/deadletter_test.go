@@ -0,0 +1,105 @@
+package deadletter_test
+
+import (
+	"context"
+	"errors"
+	"github.com/Ryanair/gofrlib/deadletter"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+type recordingClient struct {
+	inputs []*sqs.SendMessageInput
+	err    error
+}
+
+func (c *recordingClient) SendMessage(ctx context.Context, input *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	c.inputs = append(c.inputs, input)
+	return &sqs.SendMessageOutput{}, c.err
+}
+
+func withClient(t *testing.T, client deadletter.SQSAPI) {
+	deadletter.SetClient(client)
+	t.Cleanup(func() {
+		deadletter.SetClient(nil)
+	})
+}
+
+func stringValue(input *sqs.SendMessageInput, name string) string {
+	if attribute, ok := input.MessageAttributes[name]; ok {
+		return *attribute.StringValue
+	}
+	return ""
+}
+
+func TestLogAndDeadLetterSqsMessage(t *testing.T) {
+	recorder := logtest.Capture(t)
+	client := &recordingClient{}
+	withClient(t, client)
+	message := events.SQSMessage{
+		MessageId:      "test-message-id",
+		Body:           `{"orderId":"1"}`,
+		EventSource:    "aws:sqs",
+		EventSourceARN: "arn:aws:sqs:eu-west-1:123456789012:orders",
+		Attributes:     map[string]string{"ApproximateReceiveCount": "3"},
+		MessageAttributes: map[string]events.SQSMessageAttribute{
+			log.CorrelationIdAttribute: {DataType: "String", StringValue: aws.String("test-correlation-id")},
+		},
+	}
+
+	err := deadletter.LogAndDeadLetter(context.Background(), message, errors.New("malformed order"), "https://sqs.eu-west-1.amazonaws.com/123456789012/orders-dlq")
+
+	assert.NoError(t, err)
+	recorder.AssertLogged(zapcore.ErrorLevel, "Dead lettering record")
+	recorder.AssertField(log.MessageId, "test-message-id")
+	recorder.AssertField(log.ReceiveCount, 3)
+	recorder.AssertField(log.ErrorMessage, "malformed order")
+	recorder.AssertField(log.CorrelationId, "test-correlation-id")
+	recorder.AssertField(deadletter.DeadLettersMetric, 1.0)
+
+	assert.Len(t, client.inputs, 1)
+	input := client.inputs[0]
+	assert.Equal(t, "https://sqs.eu-west-1.amazonaws.com/123456789012/orders-dlq", *input.QueueUrl)
+	assert.Equal(t, `{"orderId":"1"}`, *input.MessageBody)
+	assert.Equal(t, "malformed order", stringValue(input, deadletter.ErrorAttribute))
+	assert.Equal(t, "arn:aws:sqs:eu-west-1:123456789012:orders", stringValue(input, deadletter.SourceAttribute))
+	assert.Equal(t, "test-message-id", stringValue(input, deadletter.RecordIdAttribute))
+	assert.Equal(t, "test-correlation-id", stringValue(input, log.CorrelationIdAttribute))
+}
+
+func TestLogAndDeadLetterKinesisRecord(t *testing.T) {
+	logtest.Capture(t)
+	client := &recordingClient{}
+	withClient(t, client)
+	record := events.KinesisEventRecord{EventSource: "aws:kinesis"}
+	record.Kinesis.SequenceNumber = "49590338271490256608559692538361571095921575989136588898"
+	record.Kinesis.Data = []byte{0xff, 0xfe}
+
+	err := deadletter.LogAndDeadLetter(context.Background(), &record, errors.New("malformed record"), "dlq")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "//4=", *client.inputs[0].MessageBody)
+	assert.Equal(t, "base64", stringValue(client.inputs[0], deadletter.EncodingAttribute))
+	assert.NotContains(t, client.inputs[0].MessageAttributes, deadletter.SourceAttribute)
+}
+
+func TestLogAndDeadLetterFailures(t *testing.T) {
+	recorder := logtest.Capture(t)
+
+	err := deadletter.LogAndDeadLetter(context.Background(), events.SQSMessage{MessageId: "1"}, errors.New("malformed order"), "dlq")
+	assert.EqualError(t, err, "unable to dead letter record: the client is not set, see SetClient")
+	recorder.AssertLogged(zapcore.ErrorLevel, "Dead lettering record")
+
+	withClient(t, &recordingClient{err: errors.New("access denied")})
+	err = deadletter.LogAndDeadLetter(context.Background(), events.SQSMessage{MessageId: "1"}, errors.New("malformed order"), "dlq")
+	assert.EqualError(t, err, "unable to dead letter record 1: access denied")
+
+	err = deadletter.LogAndDeadLetter(context.Background(), events.SNSEventRecord{}, errors.New("malformed order"), "dlq")
+	assert.EqualError(t, err, "unable to dead letter events.SNSEventRecord: only SQS messages and Kinesis records are supported")
+}
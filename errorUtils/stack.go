@@ -0,0 +1,66 @@
+package errorUtils
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+type stackError struct {
+	err   error
+	msg   string
+	stack []uintptr
+}
+
+// Wrap annotates err with msg and records the stack trace of the caller, nil errors stay nil.
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return &stackError{err: err, msg: msg, stack: callers()}
+}
+
+func Wrapf(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &stackError{err: err, msg: fmt.Sprintf(format, args...), stack: callers()}
+}
+
+// WithStack records the stack trace of the caller without changing the message of err.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &stackError{err: err, stack: callers()}
+}
+
+func (e *stackError) Error() string {
+	if e.msg == "" {
+		return e.err.Error()
+	}
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+func (e *stackError) StackTrace() string {
+	var builder strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&builder, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return builder.String()
+}
+
+func callers() []uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
@@ -0,0 +1,23 @@
+package errorUtils_test
+
+import (
+	"errors"
+	"github.com/Ryanair/gofrlib/errorUtils"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_ShouldWrapWithStack(t *testing.T) {
+	cause := errors.New("cause")
+
+	err := errorUtils.Wrap(cause, "wrapped")
+
+	assert.Equal(t, "wrapped: cause", err.Error())
+	assert.True(t, errors.Is(err, cause))
+	assert.Contains(t, err.(interface{ StackTrace() string }).StackTrace(), "Test_ShouldWrapWithStack")
+}
+
+func Test_ShouldKeepNilWhenWrapping(t *testing.T) {
+	assert.Nil(t, errorUtils.Wrap(nil, "wrapped"))
+	assert.Nil(t, errorUtils.WithStack(nil))
+}
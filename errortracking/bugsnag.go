@@ -0,0 +1,58 @@
+package errortracking
+
+import "time"
+
+// DefaultBugsnagEndpoint is the notify endpoint of Bugsnag.
+const DefaultBugsnagEndpoint = "https://notify.bugsnag.com/"
+
+type bugsnagReporter struct {
+	apiKey   string
+	endpoint string
+}
+
+// NewBugsnagReporter reports the events to the Bugsnag project of apiKey through endpoint, DefaultBugsnagEndpoint
+// when empty, so this module doesn't depend on the Bugsnag notifier. The trace id, custom attributes, stack
+// and fields of the record are metadata tabs.
+func NewBugsnagReporter(apiKey string, endpoint string) Reporter {
+	if endpoint == "" {
+		endpoint = DefaultBugsnagEndpoint
+	}
+	return &bugsnagReporter{apiKey: apiKey, endpoint: endpoint}
+}
+
+func (r *bugsnagReporter) Report(event Event) error {
+	errorClass, message := event.ErrorKind, event.ErrorMessage
+	if errorClass == "" {
+		errorClass, message = event.Message, event.Message
+	}
+	payload := map[string]interface{}{
+		"apiKey":         r.apiKey,
+		"payloadVersion": "5",
+		"notifier": map[string]string{
+			"name":    "gofrlib",
+			"version": "1.0",
+			"url":     "https://github.com/Ryanair/gofrlib",
+		},
+		"events": []map[string]interface{}{{
+			"exceptions": []map[string]interface{}{{
+				"errorClass": errorClass,
+				"message":    message,
+				"stacktrace": []interface{}{},
+			}},
+			"context":   event.Message,
+			"severity":  "error",
+			"unhandled": false,
+			"app":       map[string]string{"version": event.Release},
+			"metaData": map[string]interface{}{
+				"trace":      map[string]string{"traceId": event.TraceId, "caller": event.Caller, "stack": event.Stack},
+				"attributes": event.Attributes,
+				"record":     event.Fields,
+			},
+		}},
+	}
+	return post(r.endpoint, map[string]string{
+		"Bugsnag-Api-Key":         r.apiKey,
+		"Bugsnag-Payload-Version": "5",
+		"Bugsnag-Sent-At":         time.Now().UTC().Format(time.RFC3339),
+	}, payload)
+}
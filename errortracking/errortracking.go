@@ -0,0 +1,126 @@
+package errortracking
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const reportTimeout = 2 * time.Second
+
+var reportClient = &http.Client{Timeout: reportTimeout}
+
+// Event is an ERROR or higher record as reported to an error tracker.
+type Event struct {
+	Level   zapcore.Level
+	Time    time.Time
+	Message string
+	Logger  string
+	Caller  string
+	// ErrorKind and ErrorMessage are the log.ErrorKind and log.ErrorMessage of log.ErrorFields, empty without error.
+	ErrorKind    string
+	ErrorMessage string
+	// Stack is the log.ErrorStack of the error, or the stack trace of the record.
+	Stack   string
+	TraceId string
+	// Release is the version of the configuration of the logger.
+	Release string
+	// Attributes are the custom attributes of the record, see log.WithCustomAttr and log.PutAttr, without their prefix.
+	Attributes map[string]interface{}
+	// Fields are all the fields of the record, already redacted.
+	Fields map[string]interface{}
+}
+
+// Reporter sends events to an error tracker, e.g. NewSentryReporter or NewBugsnagReporter.
+type Reporter interface {
+	Report(event Event) error
+}
+
+// ReporterFunc adapts a function to Reporter, e.g. one capturing the events with the client of an SDK.
+type ReporterFunc func(event Event) error
+
+func (f ReporterFunc) Report(event Event) error {
+	return f(event)
+}
+
+// Register forwards the ERROR and higher records of every logger of this module to reporter, with their stack,
+// trace id, custom attributes and release, so handlers report exceptions by logging them. Reports are sent
+// synchronously, as Lambda freezes background work, their errors are reported on stderr like the ones of every
+// hook, see log.RegisterHook. The returned function unregisters reporter.
+func Register(reporter Reporter) (unregister func()) {
+	return log.RegisterHook(func(entry zapcore.Entry, fields []zapcore.Field) error {
+		if entry.Level < zapcore.ErrorLevel {
+			return nil
+		}
+		return reporter.Report(newEvent(entry, fields))
+	})
+}
+
+func newEvent(entry zapcore.Entry, fields []zapcore.Field) Event {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	config := log.GetConfiguration()
+	event := Event{
+		Level:        entry.Level,
+		Time:         entry.Time,
+		Message:      entry.Message,
+		Logger:       entry.LoggerName,
+		Caller:       entry.Caller.TrimmedPath(),
+		ErrorKind:    stringField(encoder.Fields, log.ErrorKind),
+		ErrorMessage: stringField(encoder.Fields, log.ErrorMessage),
+		Stack:        stringField(encoder.Fields, log.ErrorStack),
+		TraceId:      stringField(encoder.Fields, log.TraceId),
+		Release:      config.Version(),
+		Attributes:   map[string]interface{}{},
+		Fields:       encoder.Fields,
+	}
+	if event.Stack == "" {
+		event.Stack = entry.Stack
+	}
+	if prefix := config.CustomAttributesPrefix(); prefix != "" {
+		attributePrefix := "Body." + prefix + "."
+		for key, value := range encoder.Fields {
+			if strings.HasPrefix(key, attributePrefix) {
+				event.Attributes[strings.TrimPrefix(key, attributePrefix)] = value
+			}
+		}
+	}
+	return event
+}
+
+func stringField(fields map[string]interface{}, key string) string {
+	value, _ := fields[key].(string)
+	return value
+}
+
+// post sends payload as json to url, the errors never hold the response, it may echo the payload.
+func post(url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	response, err := reportClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("error tracker responded %d", response.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package errortracking_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Ryanair/gofrlib/errortracking"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func initLog(t *testing.T) {
+	log.Init(log.NewConfiguration("DEBUG", "TEST-APPLICATION", "", "", "1.2.3", "testPrefix"))
+	logtest.Capture(t)
+}
+
+func TestRegisterReportsErrors(t *testing.T) {
+	initLog(t)
+	var events []errortracking.Event
+	unregister := errortracking.Register(errortracking.ReporterFunc(func(event errortracking.Event) error {
+		events = append(events, event)
+		return nil
+	}))
+	defer unregister()
+
+	defer log.ResetInvocation()
+	log.PutAttr(context.Background(), "channel", "web")
+	log.Warn("Not reported")
+	log.ErrorW("Booking failed", append([]interface{}{log.TraceId, "trace-1"}, log.ErrorFields(errors.New("timeout"))...)...)
+	unregister()
+	log.Error("Not reported after unregister")
+
+	require.Len(t, events, 1)
+	event := events[0]
+	assert.Equal(t, zapcore.ErrorLevel, event.Level)
+	assert.Equal(t, "Booking failed", event.Message)
+	assert.Equal(t, "timeout", event.ErrorMessage)
+	assert.NotEmpty(t, event.ErrorKind)
+	assert.Equal(t, "trace-1", event.TraceId)
+	assert.Equal(t, "1.2.3", event.Release)
+	assert.Equal(t, "web", event.Attributes["channel"])
+}
+
+func TestSentryReporter(t *testing.T) {
+	var auth string
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/42/store/", r.URL.Path)
+		auth = r.Header.Get("X-Sentry-Auth")
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &payload))
+	}))
+	defer server.Close()
+
+	reporter, err := errortracking.NewSentryReporter(strings.Replace(server.URL, "://", "://public@", 1) + "/42")
+	require.NoError(t, err)
+	err = reporter.Report(errortracking.Event{
+		Level:        zapcore.ErrorLevel,
+		Message:      "Booking failed",
+		ErrorKind:    "*net.OpError",
+		ErrorMessage: "timeout",
+		TraceId:      "trace-1",
+		Release:      "1.2.3",
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, auth, "sentry_key=public")
+	assert.Equal(t, "error", payload["level"])
+	assert.Equal(t, "1.2.3", payload["release"])
+	assert.Equal(t, map[string]interface{}{"trace_id": "trace-1"}, payload["tags"])
+	assert.Contains(t, payload, "exception")
+
+	_, err = errortracking.NewSentryReporter("https://o1.ingest.sentry.io/42")
+	assert.Error(t, err)
+}
+
+func TestBugsnagReporter(t *testing.T) {
+	var apiKey string
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("Bugsnag-Api-Key")
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &payload))
+	}))
+	defer server.Close()
+
+	err := errortracking.NewBugsnagReporter("key", server.URL).Report(errortracking.Event{
+		Level:   zapcore.ErrorLevel,
+		Message: "Booking failed",
+		Release: "1.2.3",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "key", apiKey)
+	event := payload["events"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"version": "1.2.3"}, event["app"])
+	exception := event["exceptions"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Booking failed", exception["errorClass"])
+}
+
+func TestReporterErrorsHoldStatusOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid api key", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := errortracking.NewBugsnagReporter("key", server.URL).Report(errortracking.Event{Message: "Booking failed"})
+
+	assert.EqualError(t, err, "error tracker responded 401")
+}
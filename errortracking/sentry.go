@@ -0,0 +1,79 @@
+package errortracking
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type sentryReporter struct {
+	storeUrl string
+	auth     string
+}
+
+// NewSentryReporter reports the events to the Sentry project of dsn, e.g. https://key@o1.ingest.sentry.io/42,
+// through its store endpoint, so this module doesn't depend on the Sentry SDK. The trace id is the trace_id tag,
+// the stack and the fields of the record are extra data.
+func NewSentryReporter(dsn string) (Reporter, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil || parsed.User == nil || parsed.User.Username() == "" {
+		return nil, errors.New("malformed Sentry DSN")
+	}
+	path := strings.Trim(parsed.Path, "/")
+	i := strings.LastIndex(path, "/")
+	projectId := path[i+1:]
+	if projectId == "" {
+		return nil, errors.New("malformed Sentry DSN: no project id")
+	}
+	prefix := ""
+	if i >= 0 {
+		prefix = "/" + path[:i]
+	}
+	return &sentryReporter{
+		storeUrl: fmt.Sprintf("%s://%s%s/api/%s/store/", parsed.Scheme, parsed.Host, prefix, projectId),
+		auth:     fmt.Sprintf("Sentry sentry_version=7, sentry_client=gofrlib/1.0, sentry_key=%s", parsed.User.Username()),
+	}, nil
+}
+
+func (r *sentryReporter) Report(event Event) error {
+	payload := map[string]interface{}{
+		"event_id":  sentryEventId(),
+		"timestamp": event.Time.UTC().Format(time.RFC3339),
+		"level":     sentryLevel(event.Level),
+		"logger":    event.Logger,
+		"platform":  "go",
+		"release":   event.Release,
+		"message":   event.Message,
+		"culprit":   event.Caller,
+		"tags":      map[string]string{"trace_id": event.TraceId},
+		"extra": map[string]interface{}{
+			"stack":      event.Stack,
+			"attributes": event.Attributes,
+			"fields":     event.Fields,
+		},
+	}
+	if event.ErrorKind != "" {
+		payload["exception"] = map[string]interface{}{
+			"values": []map[string]string{{"type": event.ErrorKind, "value": event.ErrorMessage}},
+		}
+	}
+	return post(r.storeUrl, map[string]string{"X-Sentry-Auth": r.auth}, payload)
+}
+
+func sentryEventId() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+func sentryLevel(level zapcore.Level) string {
+	if level >= zapcore.PanicLevel {
+		return "fatal"
+	}
+	return "error"
+}
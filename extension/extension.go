@@ -0,0 +1,112 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	RuntimeApiEnv = "AWS_LAMBDA_RUNTIME_API"
+
+	// DefaultName is the name the extension registers with when Register is given none.
+	DefaultName = "gofrlib"
+
+	nameHeader       = "Lambda-Extension-Name"
+	identifierHeader = "Lambda-Extension-Identifier"
+
+	// shutdownTimeout is below the 500ms Lambda waits after SIGTERM when internal extensions are registered.
+	shutdownTimeout = 400 * time.Millisecond
+)
+
+var (
+	hooksMutex sync.Mutex
+	hooks      []func(ctx context.Context)
+)
+
+// OnShutdown adds fn to the functions called, before the logger is flushed, when the execution environment shuts down,
+// e.g. to close connections or flush clients buffering their own records. ctx expires when Lambda is about to kill the process.
+func OnShutdown(fn func(ctx context.Context)) {
+	hooksMutex.Lock()
+	defer hooksMutex.Unlock()
+	hooks = append(hooks, fn)
+}
+
+// Register registers an internal extension named name through the Lambda Extensions API, so Lambda sends SIGTERM
+// to the function before shutting the execution environment down. On SIGTERM the OnShutdown functions are called,
+// the logger is flushed, which includes the buffered records and the metrics, and the process exits.
+// It must be called once at start up, before lambda.Start. The returned function stops listening for SIGTERM.
+func Register(name string) (stop func(), err error) {
+	runtimeApi := os.Getenv(RuntimeApiEnv)
+	if runtimeApi == "" {
+		return nil, fmt.Errorf("unable to register extension: %s is not set", RuntimeApiEnv)
+	}
+	if name == "" {
+		name = DefaultName
+	}
+	id, err := register(runtimeApi, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to register extension %s: %v", name, err)
+	}
+	log.DebugW("Extension registered", "extension", name, "extensionId", id)
+
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			shutdown()
+			os.Exit(0)
+		case <-done:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}, nil
+}
+
+// register registers for no event, internal extensions can't receive SHUTDOWN and don't need INVOKE.
+func register(runtimeApi, name string) (string, error) {
+	url := fmt.Sprintf("http://%s/2020-01-01/extension/register", runtimeApi)
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(`{"events":[]}`))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set(nameHeader, name)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(response.Body)
+		return "", fmt.Errorf("status %d: %s", response.StatusCode, body)
+	}
+	return response.Header.Get(identifierHeader), nil
+}
+
+func shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	log.Info("Execution environment shutting down")
+	hooksMutex.Lock()
+	shutdownHooks := append([]func(ctx context.Context){}, hooks...)
+	hooksMutex.Unlock()
+	for _, hook := range shutdownHooks {
+		hook(ctx)
+	}
+	_ = log.Flush()
+}
@@ -0,0 +1,89 @@
+package extension_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/extension"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRegisterFlushesOnShutdown(t *testing.T) {
+	if os.Getenv("GOFRLIB_EXTENSION") == "true" {
+		log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").WithBuffering(0, 0))
+		if _, err := extension.Register(""); err != nil {
+			os.Exit(2)
+		}
+		extension.OnShutdown(func(ctx context.Context) {
+			log.Info("Closing connections")
+		})
+		log.Info("Last record")
+		_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+		time.Sleep(5 * time.Second)
+		os.Exit(3)
+	}
+	var name, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/2020-01-01/extension/register" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		name = r.Header.Get("Lambda-Extension-Name")
+		bytes, _ := ioutil.ReadAll(r.Body)
+		body = string(bytes)
+		w.Header().Set("Lambda-Extension-Identifier", "test-id")
+	}))
+	defer server.Close()
+
+	command := exec.Command(os.Args[0], "-test.run=^TestRegisterFlushesOnShutdown$")
+	command.Env = append(os.Environ(),
+		"GOFRLIB_EXTENSION=true",
+		extension.RuntimeApiEnv+"="+strings.TrimPrefix(server.URL, "http://"))
+	output, err := command.CombinedOutput()
+
+	assert.NoError(t, err)
+	assert.Equal(t, extension.DefaultName, name)
+	assert.JSONEq(t, `{"events":[]}`, body)
+	assert.Contains(t, string(output), "Last record")
+	assert.Contains(t, string(output), "Execution environment shutting down")
+	assert.Contains(t, string(output), "Closing connections")
+}
+
+func TestRegisterWithoutRuntimeApi(t *testing.T) {
+	previous, set := os.LookupEnv(extension.RuntimeApiEnv)
+	_ = os.Unsetenv(extension.RuntimeApiEnv)
+	t.Cleanup(func() {
+		if set {
+			_ = os.Setenv(extension.RuntimeApiEnv, previous)
+		}
+	})
+
+	stop, err := extension.Register("test")
+
+	assert.EqualError(t, err, "unable to register extension: AWS_LAMBDA_RUNTIME_API is not set")
+	assert.Nil(t, stop)
+}
+
+func TestRegisterRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("denied"))
+	}))
+	defer server.Close()
+	assert.NoError(t, os.Setenv(extension.RuntimeApiEnv, strings.TrimPrefix(server.URL, "http://")))
+	t.Cleanup(func() {
+		_ = os.Unsetenv(extension.RuntimeApiEnv)
+	})
+
+	_, err := extension.Register("test")
+
+	assert.EqualError(t, err, "unable to register extension test: status 403: denied")
+}
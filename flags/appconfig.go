@@ -0,0 +1,83 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	appConfigPortEnv     = "AWS_APPCONFIG_EXTENSION_HTTP_PORT"
+	appConfigDefaultPort = "2772"
+	appConfigTimeout     = 3 * time.Second
+	defaultRefresh       = 45 * time.Second
+)
+
+var appConfigClient = &http.Client{Timeout: appConfigTimeout}
+
+type appConfigProvider struct {
+	path    string
+	refresh time.Duration
+
+	mutex   sync.Mutex
+	flags   map[string]Flag
+	fetched time.Time
+}
+
+// AppConfigProvider reads the feature flags of the configuration profile of application and environment through
+// the AWS AppConfig Lambda extension, which must be added as a layer of the function, so this module doesn't
+// depend on the AppConfig client. The document is fetched again every 45 seconds, the last one is kept when it fails.
+// The variant of multi-variant flags is their _variant attribute.
+func AppConfigProvider(application, environment, profile string) Provider {
+	return &appConfigProvider{
+		path: fmt.Sprintf("/applications/%s/environments/%s/configurations/%s",
+			url.PathEscape(application), url.PathEscape(environment), url.PathEscape(profile)),
+		refresh: defaultRefresh,
+	}
+}
+
+func (p *appConfigProvider) Lookup(ctx context.Context, name string) (Flag, bool, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.flags == nil || time.Since(p.fetched) >= p.refresh {
+		flags, err := p.fetch(ctx)
+		if err != nil && p.flags == nil {
+			return Flag{}, false, err
+		}
+		if err == nil {
+			p.flags = flags
+			p.fetched = time.Now()
+		}
+	}
+	flag, found := p.flags[name]
+	return flag, found, nil
+}
+
+func (p *appConfigProvider) fetch(ctx context.Context) (map[string]Flag, error) {
+	port := os.Getenv(appConfigPortEnv)
+	if port == "" {
+		port = appConfigDefaultPort
+	}
+	request, err := http.NewRequest(http.MethodGet, "http://localhost:"+port+p.path, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := appConfigClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("AppConfig extension unavailable: %v", err)
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("AppConfig extension responded %d", response.StatusCode)
+	}
+	return parseDocument(body)
+}
@@ -0,0 +1,189 @@
+package flags
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"go.uber.org/zap"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Reasons of the decisions, logged as log.FlagReason.
+const (
+	ReasonMatch    = "match"
+	ReasonDisabled = "disabled"
+	ReasonMissing  = "missing"
+	ReasonError    = "error"
+)
+
+// Flag is the state of a feature flag, Variant is empty for boolean flags.
+type Flag struct {
+	Enabled bool
+	Variant string
+}
+
+// Provider looks up the flag name, found is false when the provider doesn't know it.
+type Provider interface {
+	Lookup(ctx context.Context, name string) (flag Flag, found bool, err error)
+}
+
+// Client evaluates feature flags and logs every decision with the logger of the context, a "Flag evaluated" record
+// at INFO with the log.FlagName, the log.FlagVariant returned, the log.FlagReason and log.FlagDefaultUsed,
+// so the code path taken by an invocation can be explained. Provider errors are logged as warnings and evaluate
+// to the default.
+type Client struct {
+	provider Provider
+}
+
+func New(provider Provider) *Client {
+	return &Client{provider: provider}
+}
+
+// Enabled returns whether the flag name is enabled, defaultValue when the provider doesn't know it.
+func (c *Client) Enabled(ctx context.Context, name string, defaultValue bool) bool {
+	flag, reason, err := c.evaluate(ctx, name)
+	enabled := flag.Enabled
+	defaultUsed := reason == ReasonMissing || reason == ReasonError
+	if defaultUsed {
+		enabled = defaultValue
+	}
+	c.logDecision(ctx, name, strconv.FormatBool(enabled), reason, defaultUsed, err)
+	return enabled
+}
+
+// Variant returns the variant of the flag name, defaultVariant when the flag is disabled, has no variant
+// or the provider doesn't know it.
+func (c *Client) Variant(ctx context.Context, name string, defaultVariant string) string {
+	flag, reason, err := c.evaluate(ctx, name)
+	if reason == ReasonMatch && flag.Variant == "" {
+		reason = ReasonMissing
+	}
+	variant := defaultVariant
+	if reason == ReasonMatch {
+		variant = flag.Variant
+	}
+	c.logDecision(ctx, name, variant, reason, reason != ReasonMatch, err)
+	return variant
+}
+
+func (c *Client) evaluate(ctx context.Context, name string) (Flag, string, error) {
+	flag, found, err := c.provider.Lookup(ctx, name)
+	switch {
+	case err != nil:
+		return Flag{}, ReasonError, err
+	case !found:
+		return Flag{}, ReasonMissing, nil
+	case !flag.Enabled:
+		return flag, ReasonDisabled, nil
+	}
+	return flag, ReasonMatch, nil
+}
+
+// logDecision is called by the exported methods, the records are logged on behalf of their caller.
+func (c *Client) logDecision(ctx context.Context, name string, variant string, reason string, defaultUsed bool, err error) {
+	logger := log.FromContext(ctx).Desugar().WithOptions(zap.AddCallerSkip(2)).Sugar()
+	fields := []interface{}{
+		log.FlagName, name,
+		log.FlagVariant, variant,
+		log.FlagReason, reason,
+		log.FlagDefaultUsed, defaultUsed,
+	}
+	if err != nil {
+		logger.Warnw("Flag evaluated", append(fields, log.ErrorFields(err)...)...)
+		return
+	}
+	logger.Infow("Flag evaluated", fields...)
+}
+
+type envProvider struct {
+	prefix string
+}
+
+// EnvProvider reads the flag name from the environment variable prefix + name in upper case, non alphanumeric
+// characters replaced by _, e.g. FLAG_NEW_CHECKOUT for new-checkout with the FLAG_ prefix. "true" and "false"
+// enable and disable the flag, any other value enables it with that variant.
+func EnvProvider(prefix string) Provider {
+	return &envProvider{prefix: prefix}
+}
+
+func (p *envProvider) Lookup(ctx context.Context, name string) (Flag, bool, error) {
+	value, found := os.LookupEnv(p.prefix + envName(name))
+	if !found {
+		return Flag{}, false, nil
+	}
+	return parseValue(value), true, nil
+}
+
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+func parseValue(value string) Flag {
+	if enabled, err := strconv.ParseBool(value); err == nil {
+		return Flag{Enabled: enabled}
+	}
+	return Flag{Enabled: true, Variant: value}
+}
+
+type staticProvider struct {
+	flags map[string]Flag
+}
+
+// JSONProvider reads the flags of a json document, e.g. embedded in the binary or loaded with the config package.
+// Every key is a flag holding a boolean, a variant or an object like the AppConfig feature flags,
+// {"enabled": true, "variant": "b"}, see AppConfigProvider.
+func JSONProvider(document []byte) (Provider, error) {
+	flags, err := parseDocument(document)
+	if err != nil {
+		return nil, err
+	}
+	return &staticProvider{flags: flags}, nil
+}
+
+func (p *staticProvider) Lookup(ctx context.Context, name string) (Flag, bool, error) {
+	flag, found := p.flags[name]
+	return flag, found, nil
+}
+
+func parseDocument(document []byte) (map[string]Flag, error) {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(document, &values); err != nil {
+		return nil, fmt.Errorf("malformed flags document: %v", err)
+	}
+	flags := make(map[string]Flag, len(values))
+	for name, value := range values {
+		var enabled bool
+		var variant string
+		var object struct {
+			Enabled     *bool  `json:"enabled"`
+			Variant     string `json:"variant"`
+			VariantName string `json:"_variant"`
+		}
+		switch {
+		case json.Unmarshal(value, &enabled) == nil:
+			flags[name] = Flag{Enabled: enabled}
+		case json.Unmarshal(value, &variant) == nil:
+			flags[name] = parseValue(variant)
+		case json.Unmarshal(value, &object) == nil:
+			flag := Flag{Enabled: object.Enabled == nil || *object.Enabled, Variant: object.Variant}
+			if flag.Variant == "" {
+				flag.Variant = object.VariantName
+			}
+			flags[name] = flag
+		default:
+			return nil, fmt.Errorf("malformed flag %s", name)
+		}
+	}
+	return flags, nil
+}
@@ -0,0 +1,84 @@
+package flags_test
+
+import (
+	"context"
+	"errors"
+	"github.com/Ryanair/gofrlib/flags"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+type failingProvider struct{}
+
+func (failingProvider) Lookup(ctx context.Context, name string) (flags.Flag, bool, error) {
+	return flags.Flag{}, false, errors.New("unavailable")
+}
+
+func TestEnabled(t *testing.T) {
+	recorder := logtest.Capture(t)
+	provider, err := flags.JSONProvider([]byte(`{"new-checkout": true, "legacy-pricing": {"enabled": false}}`))
+	assert.NoError(t, err)
+	client := flags.New(provider)
+
+	assert.True(t, client.Enabled(context.Background(), "new-checkout", false))
+	recorder.AssertLogged(zapcore.InfoLevel, "Flag evaluated")
+	recorder.AssertField(log.FlagName, "new-checkout")
+	recorder.AssertField(log.FlagReason, flags.ReasonMatch)
+	recorder.AssertField(log.FlagDefaultUsed, false)
+
+	recorder.Reset()
+	assert.False(t, client.Enabled(context.Background(), "legacy-pricing", true))
+	recorder.AssertField(log.FlagReason, flags.ReasonDisabled)
+
+	recorder.Reset()
+	assert.True(t, client.Enabled(context.Background(), "unknown", true))
+	recorder.AssertField(log.FlagReason, flags.ReasonMissing)
+	recorder.AssertField(log.FlagDefaultUsed, true)
+
+	recorder.Reset()
+	assert.True(t, flags.New(failingProvider{}).Enabled(context.Background(), "new-checkout", true))
+	recorder.AssertLogged(zapcore.WarnLevel, "Flag evaluated")
+	recorder.AssertField(log.FlagReason, flags.ReasonError)
+}
+
+func TestVariant(t *testing.T) {
+	recorder := logtest.Capture(t)
+	assert.NoError(t, os.Setenv("FLAG_CHECKOUT_LAYOUT", "compact"))
+	defer os.Unsetenv("FLAG_CHECKOUT_LAYOUT")
+	client := flags.New(flags.EnvProvider("FLAG_"))
+
+	assert.Equal(t, "compact", client.Variant(context.Background(), "checkout-layout", "classic"))
+	recorder.AssertField(log.FlagVariant, "compact")
+	recorder.AssertField(log.FlagDefaultUsed, false)
+
+	recorder.Reset()
+	assert.Equal(t, "classic", client.Variant(context.Background(), "search-layout", "classic"))
+	recorder.AssertField(log.FlagVariant, "classic")
+	recorder.AssertField(log.FlagReason, flags.ReasonMissing)
+}
+
+func TestAppConfigProvider(t *testing.T) {
+	logtest.Capture(t)
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		fetches++
+		assert.Equal(t, "/applications/bookings/environments/prod/configurations/flags", request.URL.Path)
+		_, _ = writer.Write([]byte(`{"new-checkout": {"enabled": true, "_variant": "compact"}}`))
+	}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	assert.NoError(t, os.Setenv("AWS_APPCONFIG_EXTENSION_HTTP_PORT", port))
+	defer os.Unsetenv("AWS_APPCONFIG_EXTENSION_HTTP_PORT")
+	client := flags.New(flags.AppConfigProvider("bookings", "prod", "flags"))
+
+	assert.Equal(t, "compact", client.Variant(context.Background(), "new-checkout", "classic"))
+	assert.True(t, client.Enabled(context.Background(), "new-checkout", false))
+	assert.Equal(t, 1, fetches)
+}
@@ -1,13 +1,20 @@
 module github.com/Ryanair/gofrlib
 
-go 1.14
+go 1.15
 
 require (
-	github.com/aws/aws-lambda-go v1.11.1
+	github.com/aws/aws-lambda-go v1.28.0
 	github.com/aws/aws-sdk-go v1.25.25 // indirect
+	github.com/aws/aws-sdk-go-v2 v1.11.2
+	github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs v1.11.0
 	github.com/aws/aws-sdk-go-v2/service/dynamodb v1.10.0
+	github.com/aws/aws-sdk-go-v2/service/firehose v1.10.1
+	github.com/aws/aws-sdk-go-v2/service/sns v1.13.0
+	github.com/aws/aws-sdk-go-v2/service/sqs v1.13.1
 	github.com/aws/aws-xray-sdk-go v1.6.0
+	github.com/aws/smithy-go v1.9.0
 	github.com/kr/pretty v0.3.0 // indirect
 	github.com/stretchr/testify v1.6.1
 	go.uber.org/zap v1.10.0
+	google.golang.org/grpc v1.35.0
 )
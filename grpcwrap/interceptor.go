@@ -0,0 +1,117 @@
+package grpcwrap
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+// CorrelationIdMetadata is the metadata key carrying the correlation id, the gRPC counterpart of log.CorrelationIdHeader.
+var CorrelationIdMetadata = strings.ToLower(log.CorrelationIdHeader)
+
+// UnaryServerInterceptor logs every call with its method, status code and duration. The correlation id of the
+// incoming metadata, or a new one when missing, is attached to the context passed to the handler, see log.FromContext.
+// Calls failing with a server side code are logged at WARN, the rest at INFO.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = incomingContext(ctx)
+		start := time.Now()
+		response, err := handler(ctx, req)
+		logServerCall(ctx, info.FullMethod, time.Since(start), err)
+		return response, err
+	}
+}
+
+// StreamServerInterceptor behaves like UnaryServerInterceptor for streams, logging them once they are closed.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := incomingContext(stream.Context())
+		start := time.Now()
+		err := handler(srv, &contextServerStream{ServerStream: stream, ctx: ctx})
+		logServerCall(ctx, info.FullMethod, time.Since(start), err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor logs every call with its method, status code and duration, and sends the correlation id
+// of ctx in the outgoing metadata. Calls failing with a server side code are logged at WARN, the rest at INFO.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+		logClientCall(ctx, method, time.Since(start), err)
+		return err
+	}
+}
+
+// StreamClientInterceptor behaves like UnaryClientInterceptor for streams, logging them once they are established.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(outgoingContext(ctx), desc, cc, method, opts...)
+		logClientCall(ctx, method, time.Since(start), err)
+		return stream, err
+	}
+}
+
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func incomingContext(ctx context.Context) context.Context {
+	var correlationId string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(CorrelationIdMetadata); len(values) > 0 {
+			correlationId = values[0]
+		}
+	}
+	return log.ContextWithCorrelationId(ctx, correlationId)
+}
+
+func outgoingContext(ctx context.Context) context.Context {
+	if correlationId := log.CorrelationIdFromContext(ctx); correlationId != "" {
+		return metadata.AppendToOutgoingContext(ctx, CorrelationIdMetadata, correlationId)
+	}
+	return ctx
+}
+
+func logServerCall(ctx context.Context, method string, duration time.Duration, err error) {
+	logCall(ctx, "gRPC call",
+		[]interface{}{log.RpcMethod, method, log.RpcStatus, status.Code(err).String(), log.RpcDuration, duration}, err)
+}
+
+func logClientCall(ctx context.Context, method string, duration time.Duration, err error) {
+	logCall(ctx, "gRPC request",
+		[]interface{}{log.ClientRpcMethod, method, log.ClientRpcStatus, status.Code(err).String(), log.ClientRequestDuration, duration}, err)
+}
+
+func logCall(ctx context.Context, msg string, fields []interface{}, err error) {
+	logger := log.FromContext(ctx)
+	switch {
+	case err == nil:
+		logger.Infow(msg, fields...)
+	case isServerFault(status.Code(err)):
+		logger.Warnw(msg+" failed", append(fields, log.ErrorFields(err)...)...)
+	default:
+		logger.Infow(msg+" failed", append(fields, log.ErrorFields(err)...)...)
+	}
+}
+
+// isServerFault reports whether code signals a failure of the server rather than of the request.
+func isServerFault(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	}
+	return false
+}
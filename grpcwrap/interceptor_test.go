@@ -0,0 +1,112 @@
+package grpcwrap_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/grpcwrap"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+	"time"
+)
+
+// healthClient starts a health server behind the server interceptors and returns a client using the client interceptors,
+// the correlation id seen by the server handlers is stored in correlationId.
+func healthClient(t *testing.T, correlationId *string) healthpb.HealthClient {
+	listener := bufconn.Listen(1024 * 1024)
+	capture := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		*correlationId = log.CorrelationIdFromContext(ctx)
+		return handler(ctx, req)
+	}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcwrap.UnaryServerInterceptor(), capture),
+		grpc.StreamInterceptor(grpcwrap.StreamServerInterceptor()))
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(grpcwrap.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(grpcwrap.StreamClientInterceptor()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	recorder := logtest.Capture(t)
+	var correlationId string
+	client := healthClient(t, &correlationId)
+	ctx := log.ContextWithCorrelationId(context.Background(), "a1b2c3")
+
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "orders"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a1b2c3", correlationId)
+	recorder.AssertLogged(zapcore.InfoLevel, "gRPC call")
+	recorder.AssertLogged(zapcore.InfoLevel, "gRPC request")
+	recorder.AssertField(log.RpcMethod, "/grpc.health.v1.Health/Check")
+	recorder.AssertField(log.RpcStatus, "OK")
+	recorder.AssertField(log.ClientRpcMethod, "/grpc.health.v1.Health/Check")
+	recorder.AssertField(log.ClientRpcStatus, "OK")
+	for _, entry := range recorder.Entries() {
+		assert.Equal(t, "a1b2c3", entry.Fields[log.CorrelationId])
+	}
+}
+
+func TestUnaryInterceptorsFailure(t *testing.T) {
+	recorder := logtest.Capture(t)
+	var correlationId string
+	client := healthClient(t, &correlationId)
+
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "missing"})
+
+	assert.Error(t, err)
+	assert.NotEmpty(t, correlationId)
+	recorder.AssertLogged(zapcore.InfoLevel, "gRPC call failed")
+	recorder.AssertField(log.RpcStatus, "NotFound")
+	recorder.AssertField(log.ClientRpcStatus, "NotFound")
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	recorder := logtest.Capture(t)
+	var correlationId string
+	client := healthClient(t, &correlationId)
+	ctx, cancel := context.WithCancel(log.ContextWithCorrelationId(context.Background(), "a1b2c3"))
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "orders"})
+	assert.NoError(t, err)
+	response, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+	cancel()
+
+	recorder.AssertLogged(zapcore.InfoLevel, "gRPC request")
+	recorder.AssertField(log.ClientRpcMethod, "/grpc.health.v1.Health/Watch")
+	assert.Eventually(t, func() bool {
+		for _, entry := range recorder.Entries() {
+			if entry.Fields[log.RpcMethod] == "/grpc.health.v1.Health/Watch" {
+				return entry.Fields[log.CorrelationId] == "a1b2c3"
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,87 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/metrics"
+	"github.com/aws/aws-lambda-go/lambda"
+	"reflect"
+	"sync"
+)
+
+// LivenessMetric is counted by every warm-up invocation.
+const LivenessMetric = "Liveness"
+
+// warmupResponse is returned to the warmer instead of the response of the handler.
+var warmupResponse = []byte(`{"warmup":true}`)
+
+// Matcher tells whether the top level keys of an event are the ones of a warm-up invocation.
+type Matcher func(event map[string]interface{}) bool
+
+// FieldMatcher matches the events whose key holds value, as decoded from json: numbers are float64.
+func FieldMatcher(key string, value interface{}) Matcher {
+	return func(event map[string]interface{}) bool {
+		actual, ok := event[key]
+		return ok && reflect.DeepEqual(actual, value)
+	}
+}
+
+var (
+	matchersMutex sync.RWMutex
+	// the events of serverless-plugin-warmup and lambda-warmer
+	matchers = []Matcher{
+		FieldMatcher("source", "serverless-plugin-warmup"),
+		FieldMatcher("warmer", true),
+	}
+)
+
+// SetMatchers replaces the matchers detecting warm-up invocations, by default the events of serverless-plugin-warmup,
+// {"source": "serverless-plugin-warmup"}, and lambda-warmer, {"warmer": true}. It should be called once at start up.
+func SetMatchers(m ...Matcher) {
+	matchersMutex.Lock()
+	defer matchersMutex.Unlock()
+	matchers = append([]Matcher{}, m...)
+}
+
+// IsWarmup reports whether payload is the event of a warm-up invocation, a json object matching any of the matchers.
+func IsWarmup(payload []byte) bool {
+	var event map[string]interface{}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return false
+	}
+	matchersMutex.RLock()
+	defer matchersMutex.RUnlock()
+	for _, matcher := range matchers {
+		if matcher(event) {
+			return true
+		}
+	}
+	return false
+}
+
+type warmupHandler struct {
+	next lambda.Handler
+}
+
+// Handler returns a lambda.Handler short-circuiting the warm-up invocations, see IsWarmup: they are logged at DEBUG
+// with log.Warmup true, count the LivenessMetric and respond {"warmup":true} without calling next. Other invocations
+// are handled by next, so warmers keep provisioned or idle environments warm without polluting the business records.
+func Handler(next lambda.Handler) lambda.Handler {
+	return &warmupHandler{next: next}
+}
+
+// Wrap returns Handler of handler, any signature supported by lambda.Start, e.g. Wrap(lambdawrap.Wrap(handler))
+// so the warm-up invocations aren't set up and logged as the other ones.
+func Wrap(handler interface{}) lambda.Handler {
+	return Handler(lambda.NewHandler(handler))
+}
+
+func (h *warmupHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	if !IsWarmup(payload) {
+		return h.next.Invoke(ctx, payload)
+	}
+	log.FromContext(ctx).Debugw("Warm-up invocation", log.Warmup, true)
+	metrics.NewRecordFromContext(ctx).Count(LivenessMetric, 1).Emit()
+	return warmupResponse, nil
+}
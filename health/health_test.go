@@ -0,0 +1,48 @@
+package health_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/health"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func TestIsWarmup(t *testing.T) {
+	assert.True(t, health.IsWarmup([]byte(`{"source":"serverless-plugin-warmup"}`)))
+	assert.True(t, health.IsWarmup([]byte(`{"warmer":true,"concurrency":3}`)))
+	assert.False(t, health.IsWarmup([]byte(`{"source":"aws.events"}`)))
+	assert.False(t, health.IsWarmup([]byte(`[{"warmer":true}]`)))
+
+	health.SetMatchers(health.FieldMatcher("ping", "ok"))
+	defer health.SetMatchers(
+		health.FieldMatcher("source", "serverless-plugin-warmup"),
+		health.FieldMatcher("warmer", true))
+	assert.True(t, health.IsWarmup([]byte(`{"ping":"ok"}`)))
+	assert.False(t, health.IsWarmup([]byte(`{"warmer":true}`)))
+}
+
+func TestHandlerShortCircuitsWarmups(t *testing.T) {
+	recorder := logtest.Capture(t)
+	calls := 0
+	handler := health.Wrap(func(ctx context.Context, event map[string]interface{}) (string, error) {
+		calls++
+		return "handled", nil
+	})
+
+	response, err := handler.Invoke(context.Background(), []byte(`{"warmer":true}`))
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"warmup":true}`, string(response))
+	assert.Equal(t, 0, calls)
+	recorder.AssertLogged(zapcore.DebugLevel, "Warm-up invocation")
+	recorder.AssertField(log.Warmup, true)
+
+	response, err = handler.Invoke(context.Background(), []byte(`{"id":"booking-1"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"handled"`, string(response))
+	assert.Equal(t, 1, calls)
+}
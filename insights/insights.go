@@ -0,0 +1,112 @@
+package insights
+
+import (
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"strings"
+	"time"
+)
+
+// errorLevels are the levels of the records reported as errors, lower cased as some profiles write them.
+const errorLevels = `["error", "dpanic", "panic", "fatal"]`
+
+// Query is the text of a CloudWatch Logs Insights query.
+type Query string
+
+func (q Query) String() string {
+	return string(q)
+}
+
+// Builder writes the queries with the keys a configuration of the log package writes to the output,
+// so they keep matching the records whatever the Profile and the FieldNames of the configuration.
+type Builder struct {
+	config log.Configuration
+}
+
+// New returns a Builder of the queries of the records written with config, e.g. log.GetConfiguration().
+func New(config log.Configuration) *Builder {
+	return &Builder{config: config}
+}
+
+// Field returns the output key of a key constant of the log package as used in a query,
+// between backticks when it holds characters other than letters, digits, underscores and dots.
+func (b *Builder) Field(key string) string {
+	name := b.config.FieldName(key)
+	for _, r := range name {
+		if !(r == '_' || r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return "`" + strings.Replace(name, "`", "``", -1) + "`"
+		}
+	}
+	return name
+}
+
+// Errors lists the ERROR and higher records, newest first, with their message, error and correlation id.
+func (b *Builder) Errors() Query {
+	return b.query(
+		fmt.Sprintf("fields @timestamp, %s, %s, %s, %s",
+			b.Field(log.Message), b.Field(log.ErrorKind), b.Field(log.ErrorMessage), b.Field(log.CorrelationId)),
+		b.errorFilter(),
+		"sort @timestamp desc")
+}
+
+// ErrorsByCorrelationId counts the ERROR and higher records of every correlation id, the most failing first.
+func (b *Builder) ErrorsByCorrelationId() Query {
+	return b.query(
+		b.errorFilter(),
+		fmt.Sprintf("stats count(*) as errors, latest(%s) as lastError by %s", b.Field(log.Message), b.Field(log.CorrelationId)),
+		"sort errors desc")
+}
+
+// Correlation lists the records of correlationId in order, across every function writing to the queried log groups.
+func (b *Builder) Correlation(correlationId string) Query {
+	return b.query(
+		fmt.Sprintf("fields @timestamp, %s, %s, %s", b.Field(log.Level), b.Field(log.Application), b.Field(log.Message)),
+		fmt.Sprintf("filter %s = %s", b.Field(log.CorrelationId), quote(correlationId)),
+		"sort @timestamp asc")
+}
+
+// LatencyPercentiles computes the p50, p90 and p99 of the InvocationDuration, in seconds, by intervals of bin,
+// from the records of log.StartInvocationTimer and the wide events.
+func (b *Builder) LatencyPercentiles(bin time.Duration) Query {
+	duration := b.Field(log.InvocationDuration)
+	return b.query(
+		fmt.Sprintf("filter ispresent(%s)", duration),
+		fmt.Sprintf("stats pct(%[1]s, 50) as p50, pct(%[1]s, 90) as p90, pct(%[1]s, 99) as p99, max(%[1]s) as max, count(*) as invocations by bin(%[2]s)",
+			duration, binString(bin)))
+}
+
+// ColdStarts counts the cold starts of the "Cold start" records by intervals of bin, with the average and max InitDuration in seconds.
+func (b *Builder) ColdStarts(bin time.Duration) Query {
+	initDuration := b.Field(log.InitDuration)
+	return b.query(
+		fmt.Sprintf("filter ispresent(%s)", initDuration),
+		fmt.Sprintf("stats count(*) as coldStarts, avg(%[1]s) as averageInit, max(%[1]s) as maxInit by bin(%[2]s)",
+			initDuration, binString(bin)))
+}
+
+func (b *Builder) errorFilter() string {
+	return fmt.Sprintf("filter lower(%s) in %s", b.Field(log.Level), errorLevels)
+}
+
+func (b *Builder) query(commands ...string) Query {
+	return Query(strings.Join(commands, "\n| "))
+}
+
+func quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// binString writes bin in the units of the bin function, 5m by default.
+func binString(bin time.Duration) string {
+	switch {
+	case bin <= 0:
+		return "5m"
+	case bin%time.Hour == 0:
+		return fmt.Sprintf("%dh", bin/time.Hour)
+	case bin%time.Minute == 0:
+		return fmt.Sprintf("%dm", bin/time.Minute)
+	case bin%time.Second == 0:
+		return fmt.Sprintf("%ds", bin/time.Second)
+	}
+	return fmt.Sprintf("%dms", bin/time.Millisecond)
+}
@@ -0,0 +1,77 @@
+package insights_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/insights"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestQueries(t *testing.T) {
+	builder := insights.New(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", ""))
+
+	assert.Equal(t, "filter lower(SeverityText) in [\"error\", \"dpanic\", \"panic\", \"fatal\"]\n"+
+		"| stats count(*) as errors, latest(Body.message) as lastError by CorrelationId\n"+
+		"| sort errors desc", builder.ErrorsByCorrelationId().String())
+	assert.Equal(t, "fields @timestamp, SeverityText, Resource.application, Body.message\n"+
+		"| filter CorrelationId = \"c-\\\"1\\\"\"\n"+
+		"| sort @timestamp asc", builder.Correlation(`c-"1"`).String())
+	assert.Equal(t, "filter ispresent(Body.invocation.duration)\n"+
+		"| stats pct(Body.invocation.duration, 50) as p50, pct(Body.invocation.duration, 90) as p90, "+
+		"pct(Body.invocation.duration, 99) as p99, max(Body.invocation.duration) as max, count(*) as invocations by bin(15m)",
+		builder.LatencyPercentiles(15*time.Minute).String())
+	assert.Contains(t, builder.ColdStarts(time.Hour).String(), "by bin(1h)")
+}
+
+func TestQueriesUseTheOutputKeys(t *testing.T) {
+	builder := insights.New(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithProfile(log.ECSProfile).
+		WithFieldNames(log.FieldNames{log.CorrelationId: "correlation-id"}))
+
+	assert.Equal(t, "log.level", builder.Field(log.Level))
+	assert.Equal(t, "`correlation-id`", builder.Field(log.CorrelationId))
+	assert.Contains(t, builder.Errors().String(), "fields @timestamp, message, error.type, error.message, `correlation-id`")
+}
+
+type fakeClient struct {
+	input    *cloudwatchlogs.StartQueryInput
+	statuses []types.QueryStatus
+}
+
+func (c *fakeClient) StartQuery(ctx context.Context, input *cloudwatchlogs.StartQueryInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error) {
+	c.input = input
+	return &cloudwatchlogs.StartQueryOutput{QueryId: aws.String("q-1")}, nil
+}
+
+func (c *fakeClient) GetQueryResults(ctx context.Context, input *cloudwatchlogs.GetQueryResultsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetQueryResultsOutput, error) {
+	status := c.statuses[0]
+	c.statuses = c.statuses[1:]
+	return &cloudwatchlogs.GetQueryResultsOutput{
+		Status:  status,
+		Results: [][]types.ResultField{{{Field: aws.String("errors"), Value: aws.String("3")}}},
+	}, nil
+}
+
+func TestRun(t *testing.T) {
+	insights.PollInterval = time.Millisecond
+	client := &fakeClient{statuses: []types.QueryStatus{types.QueryStatusRunning, types.QueryStatusComplete}}
+	query := insights.New(log.GetConfiguration()).ErrorsByCorrelationId()
+	end := time.Now()
+
+	rows, err := insights.Run(context.Background(), client, query, []string{"/aws/lambda/booking"}, end.Add(-time.Hour), end)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"errors": "3"}}, rows)
+	assert.Equal(t, query.String(), aws.ToString(client.input.QueryString))
+	assert.Equal(t, end.Unix(), aws.ToInt64(client.input.EndTime))
+
+	client.statuses = []types.QueryStatus{types.QueryStatusFailed}
+	_, err = insights.Run(context.Background(), client, query, []string{"/aws/lambda/booking"}, end.Add(-time.Hour), end)
+
+	assert.EqualError(t, err, "query q-1 ended Failed")
+}
@@ -0,0 +1,60 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"time"
+)
+
+// PollInterval is the time waited between two checks of a running query.
+var PollInterval = time.Second
+
+type CloudWatchLogsAPI interface {
+	StartQuery(ctx context.Context, input *cloudwatchlogs.StartQueryInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.StartQueryOutput, error)
+	GetQueryResults(ctx context.Context, input *cloudwatchlogs.GetQueryResultsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.GetQueryResultsOutput, error)
+}
+
+// Run runs query over the records of logGroups between start and end and waits for its results, one map
+// from field to value by row. The query is left running on the service when ctx is done.
+func Run(ctx context.Context, client CloudWatchLogsAPI, query Query, logGroups []string, start, end time.Time) ([]map[string]string, error) {
+	started, err := client.StartQuery(ctx, &cloudwatchlogs.StartQueryInput{
+		QueryString:   aws.String(query.String()),
+		LogGroupNames: logGroups,
+		StartTime:     aws.Int64(start.Unix()),
+		EndTime:       aws.Int64(end.Unix()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for {
+		output, err := client.GetQueryResults(ctx, &cloudwatchlogs.GetQueryResultsInput{QueryId: started.QueryId})
+		if err != nil {
+			return nil, err
+		}
+		switch output.Status {
+		case types.QueryStatusComplete:
+			return rows(output.Results), nil
+		case types.QueryStatusFailed, types.QueryStatusCancelled, types.QueryStatusTimeout:
+			return nil, fmt.Errorf("query %s ended %s", aws.ToString(started.QueryId), output.Status)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(PollInterval):
+		}
+	}
+}
+
+func rows(results [][]types.ResultField) []map[string]string {
+	rows := make([]map[string]string, len(results))
+	for i, result := range results {
+		rows[i] = make(map[string]string, len(result))
+		for _, field := range result {
+			rows[i][aws.ToString(field.Field)] = aws.ToString(field.Value)
+		}
+	}
+	return rows
+}
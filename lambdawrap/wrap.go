@@ -0,0 +1,109 @@
+package lambdawrap
+
+import (
+	"context"
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"reflect"
+	"runtime/debug"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Wrap decorates a Lambda handler, accepting any signature supported by lambda.Start, so that every invocation
+// sets up the logger with the SetUp* helper matching the event type, passing the handler the context it returns, logs its start and end with the duration,
+// warns when it's about to time out, see log.StartInvocationTimer, emits the wide event of the invocation when enabled,
+// see log.StartWideEvent, turns panics into logged errors, writes the tail of the failed invocations, see log.WithTailBuffering,
+// and flushes the logger before returning.
+// Handlers that do not return an error are re-panicked after logging, as there is no other way to fail them.
+func Wrap(handler interface{}) interface{} {
+	handlerValue := reflect.ValueOf(handler)
+	handlerType := handlerValue.Type()
+	if handlerType.Kind() != reflect.Func {
+		panic(fmt.Sprintf("handler kind %s is not %s", handlerType.Kind(), reflect.Func))
+	}
+
+	wrapped := reflect.MakeFunc(handlerType, func(args []reflect.Value) (results []reflect.Value) {
+		ctx, event := invocationArgs(handlerType, args)
+		ctx = log.SetUp(ctx, event)
+		ctx, finishEvent := log.StartWideEvent(ctx)
+		withContextArg(handlerType, args, ctx)
+		stopTimer := log.StartInvocationTimer(ctx)
+		log.Debug("Invocation started")
+
+		defer func() {
+			recovered := recover()
+			invocationErr := resultError(results)
+			if recovered != nil {
+				log.ErrorW("Invocation panicked",
+					"panic", fmt.Sprintf("%v", recovered),
+					log.StackTrace, string(debug.Stack()))
+				invocationErr = fmt.Errorf("panic: %v", recovered)
+			}
+			if invocationErr != nil {
+				log.FlushTail()
+			}
+			stopTimer()
+			finishEvent(invocationErr)
+			log.ResetInvocation()
+			_ = log.Flush()
+			if recovered != nil {
+				results = panicResults(handlerType, recovered)
+			}
+		}()
+
+		return handlerValue.Call(args)
+	})
+	return wrapped.Interface()
+}
+
+func invocationArgs(handlerType reflect.Type, args []reflect.Value) (context.Context, interface{}) {
+	ctx := context.Background()
+	var event interface{}
+	for i, arg := range args {
+		if handlerType.In(i).Implements(contextType) {
+			if c, ok := arg.Interface().(context.Context); ok && c != nil {
+				ctx = c
+			}
+			continue
+		}
+		event = arg.Interface()
+	}
+	return ctx, event
+}
+
+// withContextArg replaces the context argument of the handler by ctx.
+func withContextArg(handlerType reflect.Type, args []reflect.Value, ctx context.Context) {
+	for i := range args {
+		if handlerType.In(i).Implements(contextType) && reflect.TypeOf(ctx).AssignableTo(handlerType.In(i)) {
+			args[i] = reflect.ValueOf(ctx)
+		}
+	}
+}
+
+// resultError returns the error result of the handler, if any.
+func resultError(results []reflect.Value) error {
+	if len(results) == 0 {
+		return nil
+	}
+	err, _ := results[len(results)-1].Interface().(error)
+	return err
+}
+
+func panicResults(handlerType reflect.Type, recovered interface{}) []reflect.Value {
+	numOut := handlerType.NumOut()
+	if numOut == 0 || handlerType.Out(numOut-1) != errorType {
+		panic(recovered)
+	}
+	results := make([]reflect.Value, numOut)
+	for i := 0; i < numOut-1; i++ {
+		results[i] = reflect.Zero(handlerType.Out(i))
+	}
+	err := reflect.New(errorType).Elem()
+	err.Set(reflect.ValueOf(fmt.Errorf("panic: %v", recovered)))
+	results[numOut-1] = err
+	return results
+}
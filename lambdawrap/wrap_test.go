@@ -0,0 +1,104 @@
+package lambdawrap_test
+
+import (
+	"context"
+	"errors"
+	"github.com/Ryanair/gofrlib/lambdawrap"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func init() {
+	log.Init(log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix"))
+}
+
+func TestWrapKeepsSignature(t *testing.T) {
+	handler := func(ctx context.Context, event events.SQSEvent) (string, error) {
+		return event.Records[0].MessageId, nil
+	}
+
+	wrapped := lambdawrap.Wrap(handler).(func(context.Context, events.SQSEvent) (string, error))
+	result, err := wrapped(context.Background(), events.SQSEvent{Records: []events.SQSMessage{{MessageId: "test-id"}}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test-id", result)
+}
+
+func TestWrapReturnsHandlerError(t *testing.T) {
+	handler := func(event events.SNSEvent) error {
+		return errors.New("handler error")
+	}
+
+	wrapped := lambdawrap.Wrap(handler).(func(events.SNSEvent) error)
+
+	assert.EqualError(t, wrapped(events.SNSEvent{}), "handler error")
+}
+
+func TestWrapRecoversPanic(t *testing.T) {
+	handler := func(ctx context.Context) (int, error) {
+		panic("test panic")
+	}
+
+	wrapped := lambdawrap.Wrap(handler).(func(context.Context) (int, error))
+	result, err := wrapped(context.Background())
+
+	assert.EqualError(t, err, "panic: test panic")
+	assert.Equal(t, 0, result)
+}
+
+func TestWrapRepanicsWithoutErrorResult(t *testing.T) {
+	wrapped := lambdawrap.Wrap(func() { panic("test panic") }).(func())
+
+	assert.PanicsWithValue(t, "test panic", wrapped)
+}
+
+func TestWrapEmitsWideEvent(t *testing.T) {
+	recorder := logtest.Capture(t)
+	log.Init(log.GetConfiguration().WithWideEvents(log.WideEventsAlongside))
+	defer log.Init(log.GetConfiguration().WithWideEvents(log.WideEventsOff))
+	handler := func(ctx context.Context) error {
+		log.AddEventFields(ctx, "customerId", "test-customer")
+		return errors.New("handler error")
+	}
+
+	wrapped := lambdawrap.Wrap(handler).(func(context.Context) error)
+	assert.EqualError(t, wrapped(context.Background()), "handler error")
+
+	assert.True(t, recorder.AssertLogged(zapcore.ErrorLevel, "Invocation summary"))
+	assert.True(t, recorder.AssertField(log.WideEventStatus, "error"))
+	assert.True(t, recorder.AssertField("customerId", "test-customer"))
+}
+
+func TestWrapFlushesTailOfFailedInvocations(t *testing.T) {
+	recorder := logtest.Capture(t)
+	log.Init(log.GetConfiguration().WithTailBuffering(10))
+	assert.NoError(t, log.SetLevel("INFO"))
+	handler := func(ctx context.Context, fail bool) error {
+		log.DebugW("Debug record of the handler", "fail", fail)
+		if fail {
+			return errors.New("handler error")
+		}
+		return nil
+	}
+
+	wrapped := lambdawrap.Wrap(handler).(func(context.Context, bool) error)
+	assert.NoError(t, wrapped(context.Background(), false))
+	for _, entry := range recorder.Entries() {
+		assert.NotEqual(t, zapcore.DebugLevel, entry.Level, entry.Message)
+	}
+	assert.EqualError(t, wrapped(context.Background(), true), "handler error")
+
+	assert.True(t, recorder.AssertLogged(zapcore.DebugLevel, "Debug record of the handler"))
+	assert.True(t, recorder.AssertField("fail", true))
+	assert.True(t, recorder.AssertField(log.TailEscalated, true))
+}
@@ -8,9 +8,39 @@ import (
 	"strings"
 )
 
-func SetUpALBApiRequest(ctx context.Context, req events.ALBTargetGroupRequest) {
-	SetupTraceIds(ctx)
+func SetUpALBApiRequest(ctx context.Context, req events.ALBTargetGroupRequest) context.Context {
+	ctx = SetupTraceIdsFromHeaders(ctx, req.Headers)
 	ReportALBApiRequest(req)
+	return ctx
+}
+
+// SetUpALB attaches the request fields to the invocation. Query parameters are logged as a map,
+// so single parameters can be redacted with RedactFields, and the body apart from the event, see BodyField.
+// The event logged at DEBUG level has neither the query nor the credential headers.
+func SetUpALB(ctx context.Context, request events.ALBTargetGroupRequest) context.Context {
+	headers := request.Headers
+	if len(headers) == 0 {
+		headers = firstHeaderValues(request.MultiValueHeaders)
+	}
+	ctx = SetupTraceIdsFromHeaders(ctx, headers)
+	ctx = withSetUpFields(ctx,
+		RequestMethod, request.HTTPMethod,
+		RequestPath, request.Path,
+		RequestQuery, albQueryParams(request),
+		TargetGroupArn, request.RequestContext.ELB.TargetGroupArn)
+	if IsDebugEnabled() {
+		event := request
+		event.Body, event.IsBase64Encoded = "", false
+		// the query is logged apart so it can be redacted
+		event.QueryStringParameters, event.MultiValueQueryStringParameters = nil, nil
+		event.Headers, event.MultiValueHeaders = allowedHeaders(request.Headers), allowedMultiValueHeaders(request.MultiValueHeaders)
+		fields := []interface{}{EventSource, "alb", LazyJSON(EventBody, event)}
+		if request.Body != "" {
+			fields = append(fields, BodyField(RequestBody, request.Body, request.IsBase64Encoded, requestContentType(request.Headers, request.MultiValueHeaders)))
+		}
+		DebugW("Got event", fields...)
+	}
+	return ctx
 }
 
 func ReportALBApiRequest(req events.ALBTargetGroupRequest) {
@@ -53,3 +83,25 @@ func buildAlbQueryParam(request events.ALBTargetGroupRequest) string {
 	}
 	return strings.Join(params, "&")
 }
+
+// albQueryParams merges single and multi value parameters, only one of them is set depending on the target group.
+func albQueryParams(request events.ALBTargetGroupRequest) map[string]string {
+	params := make(map[string]string, len(request.QueryStringParameters)+len(request.MultiValueQueryStringParameters))
+	for key, value := range request.QueryStringParameters {
+		params[key] = value
+	}
+	for key, values := range request.MultiValueQueryStringParameters {
+		params[key] = strings.Join(values, ",")
+	}
+	return params
+}
+
+func firstHeaderValues(headers map[string][]string) map[string]string {
+	first := make(map[string]string, len(headers))
+	for key, values := range headers {
+		if len(values) > 0 {
+			first[key] = values[0]
+		}
+	}
+	return first
+}
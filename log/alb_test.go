@@ -0,0 +1,76 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func TestSetUpALB(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"INFO",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithRedaction(log.RedactFields("token"))
+	log.Init(config)
+	request := events.ALBTargetGroupRequest{
+		HTTPMethod: "GET",
+		Path:       "/bookings",
+		MultiValueQueryStringParameters: map[string][]string{
+			"page":  {"2"},
+			"token": {"secret"},
+		},
+		MultiValueHeaders: map[string][]string{
+			"traceparent": {"00-5759e988bd862e3fe1be46a994272793-53995c3f42cd8ad8-01"},
+		},
+		RequestContext: events.ALBTargetGroupRequestContext{
+			ELB: events.ELBContext{TargetGroupArn: "arn:aws:elasticloadbalancing:eu-west-1:123456789012:targetgroup/bookings/1"},
+		},
+	}
+	log.SetUp(context.Background(), request)
+	log.Info("Handling request")
+	log.ResetInvocation()
+
+	output := buffer.String()
+	assert.Contains(t, output, `"Body.context.origin.request.method":"GET"`)
+	assert.Contains(t, output, `"Body.context.origin.request.path":"/bookings"`)
+	assert.Contains(t, output, `"Body.context.origin.request.queryParams":{"page":"2","token":"[REDACTED]"}`)
+	assert.Contains(t, output, `"Body.context.origin.request.targetGroupArn":"arn:aws:elasticloadbalancing:eu-west-1:123456789012:targetgroup/bookings/1"`)
+	assert.Contains(t, output, "5759e988bd862e3fe1be46a994272793")
+	assert.NotContains(t, output, "secret")
+}
+
+func TestSetUpALBDumpsNeitherQueryNorCredentials(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("DEBUG", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithRedaction(log.RedactFields("token")))
+	log.SetUpALB(context.Background(), events.ALBTargetGroupRequest{
+		HTTPMethod:            "GET",
+		Path:                  "/bookings",
+		QueryStringParameters: map[string]string{"token": "QUERYSECRET"},
+		Headers: map[string]string{
+			"x-auth-token": "ALBSECRET",
+			"accept":       "application/json",
+		},
+		MultiValueHeaders: map[string][]string{"Authorization": {"Bearer SECRET"}},
+	})
+	log.ResetInvocation()
+
+	output := buffer.String()
+	assert.Contains(t, output, `"Body.message":"Got event"`)
+	assert.Contains(t, output, "application/json")
+	assert.Contains(t, output, `"Body.context.origin.request.queryParams":{"token":"[REDACTED]"}`)
+	assert.NotContains(t, output, "QUERYSECRET")
+	assert.NotContains(t, output, "ALBSECRET")
+	assert.NotContains(t, output, "Bearer SECRET")
+}
@@ -0,0 +1,53 @@
+package log
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// SetUpApiGateway attaches the request fields to the invocation, the X-Correlation-Id header being the CorrelationId,
+// and logs the request at DEBUG level without the credential headers and with its body apart, see BodyField.
+func SetUpApiGateway(ctx context.Context, request events.APIGatewayProxyRequest) context.Context {
+	ctx = SetupTraceIdsFromHeaders(headersCorrelationContext(ctx, request.Headers), request.Headers)
+	ctx = withSetUpFields(ctx,
+		RequestId, request.RequestContext.RequestID,
+		RequestRoute, request.Resource,
+		RequestMethod, request.HTTPMethod,
+		RequestPath, request.Path,
+		RequestSourceIp, request.RequestContext.Identity.SourceIP,
+		RequestStage, request.RequestContext.Stage)
+	if IsDebugEnabled() {
+		event := request
+		event.Body, event.IsBase64Encoded = "", false
+		event.Headers, event.MultiValueHeaders = allowedHeaders(request.Headers), allowedMultiValueHeaders(request.MultiValueHeaders)
+		fields := []interface{}{EventSource, "apigateway", LazyJSON(EventBody, event)}
+		if request.Body != "" {
+			fields = append(fields, BodyField(RequestBody, request.Body, request.IsBase64Encoded, requestContentType(request.Headers, request.MultiValueHeaders)))
+		}
+		DebugW("Got event", fields...)
+	}
+	return ctx
+}
+
+// SetUpApiGatewayV2 is the SetUpApiGateway of the 2.0 payload of the HTTP APIs.
+func SetUpApiGatewayV2(ctx context.Context, request events.APIGatewayV2HTTPRequest) context.Context {
+	ctx = SetupTraceIdsFromHeaders(headersCorrelationContext(ctx, request.Headers), request.Headers)
+	ctx = withSetUpFields(ctx,
+		RequestId, request.RequestContext.RequestID,
+		RequestRoute, request.RouteKey,
+		RequestMethod, request.RequestContext.HTTP.Method,
+		RequestPath, request.RawPath,
+		RequestSourceIp, request.RequestContext.HTTP.SourceIP,
+		RequestStage, request.RequestContext.Stage)
+	if IsDebugEnabled() {
+		event := request
+		event.Body, event.IsBase64Encoded = "", false
+		event.Headers = allowedHeaders(request.Headers)
+		fields := []interface{}{EventSource, "apigateway", LazyJSON(EventBody, event)}
+		if request.Body != "" {
+			fields = append(fields, BodyField(RequestBody, request.Body, request.IsBase64Encoded, headerValue(request.Headers, ContentTypeHeader)))
+		}
+		DebugW("Got event", fields...)
+	}
+	return ctx
+}
@@ -0,0 +1,108 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+)
+
+func TestSetUpApiGateway(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)))
+	request := events.APIGatewayProxyRequest{
+		Resource:   "/bookings/{id}",
+		Path:       "/bookings/42",
+		HTTPMethod: "GET",
+		Headers: map[string]string{
+			"traceparent":      "00-5759e988bd862e3fe1be46a994272793-53995c3f42cd8ad8-01",
+			"x-correlation-id": "TEST-CORRELATION-ID",
+		},
+		RequestContext: events.APIGatewayProxyRequestContext{
+			RequestID: "TEST-REQUEST-ID",
+			Stage:     "prod",
+			Identity:  events.APIGatewayRequestIdentity{SourceIP: "10.0.0.1"},
+		},
+	}
+	ctx := log.SetUpApiGateway(context.Background(), request)
+	log.Info("Handling request")
+	log.ResetInvocation()
+
+	output := buffer.String()
+	assert.Contains(t, output, `"Body.context.origin.request.method":"GET"`)
+	assert.Contains(t, output, `"Body.context.origin.request.path":"/bookings/42"`)
+	assert.Contains(t, output, `"Body.context.origin.request.route":"/bookings/{id}"`)
+	assert.Contains(t, output, `"Body.context.origin.request.sourceIp":"10.0.0.1"`)
+	assert.Contains(t, output, `"Body.context.origin.request.stage":"prod"`)
+	assert.Contains(t, output, `"`+log.RequestId+`":"TEST-REQUEST-ID"`)
+	assert.Contains(t, output, `"TraceId":"5759e988bd862e3fe1be46a994272793"`)
+	assert.Contains(t, output, `"CorrelationId":"TEST-CORRELATION-ID"`)
+	assert.Equal(t, "TEST-CORRELATION-ID", log.CorrelationIdFromContext(ctx))
+}
+
+func TestSetUpApiGatewayV2(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)))
+	request := events.APIGatewayV2HTTPRequest{
+		RouteKey: "GET /bookings/{id}",
+		RawPath:  "/bookings/42",
+		Headers: map[string]string{
+			"traceparent":      "00-5759e988bd862e3fe1be46a994272793-53995c3f42cd8ad8-01",
+			"x-correlation-id": "TEST-CORRELATION-ID",
+		},
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			RequestID: "TEST-REQUEST-ID",
+			Stage:     "$default",
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method:   "GET",
+				SourceIP: "10.0.0.1",
+			},
+		},
+	}
+	ctx := log.SetUpApiGatewayV2(context.Background(), request)
+	log.Info("Handling request")
+	log.ResetInvocation()
+
+	output := buffer.String()
+	assert.Contains(t, output, `"Body.context.origin.request.method":"GET"`)
+	assert.Contains(t, output, `"Body.context.origin.request.path":"/bookings/42"`)
+	assert.Contains(t, output, `"Body.context.origin.request.route":"GET /bookings/{id}"`)
+	assert.Contains(t, output, `"Body.context.origin.request.sourceIp":"10.0.0.1"`)
+	assert.Contains(t, output, `"Body.context.origin.request.stage":"$default"`)
+	assert.Contains(t, output, `"`+log.RequestId+`":"TEST-REQUEST-ID"`)
+	assert.Contains(t, output, `"TraceId":"5759e988bd862e3fe1be46a994272793"`)
+	assert.Contains(t, output, `"CorrelationId":"TEST-CORRELATION-ID"`)
+	assert.Equal(t, "TEST-CORRELATION-ID", log.CorrelationIdFromContext(ctx))
+}
+
+func TestSetUpApiGatewayDropsCredentialHeaders(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("DEBUG", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)))
+	headers := map[string]string{
+		"Authorization": "Bearer SECRET",
+		"fr-token-sig":  "SIGNATURE",
+		"X-Auth-Token":  "TOKEN",
+		"Accept":        "application/json",
+	}
+	log.SetUpApiGateway(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod:        "GET",
+		Headers:           headers,
+		MultiValueHeaders: map[string][]string{"Authorization": {"Bearer SECRET"}, "fr-token-sig": {"SIGNATURE"}},
+	})
+	log.SetUpApiGatewayV2(context.Background(), events.APIGatewayV2HTTPRequest{RawPath: "/bookings", Headers: headers})
+	log.ResetInvocation()
+
+	output := buffer.String()
+	assert.Equal(t, 2, strings.Count(output, `"Got event"`))
+	assert.Contains(t, output, "application/json")
+	assert.NotContains(t, output, "SECRET")
+	assert.NotContains(t, output, "SIGNATURE")
+	assert.NotContains(t, output, "TOKEN")
+}
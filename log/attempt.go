@@ -0,0 +1,52 @@
+package log
+
+import (
+	"github.com/aws/aws-lambda-go/events"
+	"sync"
+)
+
+// maxTrackedAttempts bounds the message ids remembered by the execution environment.
+const maxTrackedAttempts = 1000
+
+var attempts = &attemptTracker{counts: map[string]int{}}
+
+// attemptTracker counts the deliveries seen by the execution environment of the messages whose source doesn't report them.
+type attemptTracker struct {
+	sync.Mutex
+	counts map[string]int
+}
+
+// observe records a delivery of the message id and returns its number, starting at 1.
+func (t *attemptTracker) observe(id string) int {
+	t.Lock()
+	defer t.Unlock()
+	if _, seen := t.counts[id]; !seen && len(t.counts) >= maxTrackedAttempts {
+		t.counts = map[string]int{}
+	}
+	t.counts[id]++
+	return t.counts[id]
+}
+
+// sqsAttemptFields returns the MessageId and ReceiveCount of message, the Attempt being its ApproximateReceiveCount
+// and the LogicalMessageId its message id, which SQS keeps across the deliveries of a message.
+func sqsAttemptFields(message events.SQSMessage) []interface{} {
+	fields := []interface{}{
+		MessageId, message.MessageId,
+		LogicalMessageId, message.MessageId,
+	}
+	if receiveCount, ok := sqsReceiveCount(message); ok {
+		fields = append(fields, ReceiveCount, receiveCount, Attempt, receiveCount)
+	}
+	return fields
+}
+
+// snsAttemptFields returns the SnsMessageId of entity, also its LogicalMessageId as SNS keeps it across retries.
+// SNS doesn't report the delivery number, the Attempt is the number of deliveries of the message seen by the
+// execution environment: retries of a failed asynchronous invocation usually reach the same one, it's a lower bound.
+func snsAttemptFields(entity events.SNSEntity) []interface{} {
+	return []interface{}{
+		SnsMessageId, entity.MessageID,
+		LogicalMessageId, entity.MessageID,
+		Attempt, attempts.observe(entity.MessageID),
+	}
+}
@@ -0,0 +1,52 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSqsRecordAttempt(t *testing.T) {
+	recorder := logtest.Capture(t)
+	message := events.SQSMessage{
+		MessageId:   "sqs-message-attempt",
+		EventSource: "aws:sqs",
+		Attributes:  map[string]string{"ApproximateReceiveCount": "3"},
+	}
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "request-3"})
+
+	ctx = log.SetUpSqsRecord(ctx, message)
+	log.InfoCtxW(ctx, "Processing message")
+	log.ResetInvocation()
+
+	recorder.AssertField(log.AwsRequestId, "request-3")
+	recorder.AssertField(log.MessageId, "sqs-message-attempt")
+	recorder.AssertField(log.LogicalMessageId, "sqs-message-attempt")
+	recorder.AssertField(log.ReceiveCount, 3)
+	recorder.AssertField(log.Attempt, 3)
+}
+
+func TestSnsRecordAttempt(t *testing.T) {
+	recorder := logtest.Capture(t)
+	record := events.SNSEventRecord{
+		EventSource: "aws:sns",
+		SNS:         events.SNSEntity{MessageID: "sns-message-attempt"},
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		recorder.Reset()
+		ctx := log.SetUpSnsRecord(context.Background(), record)
+		log.InfoCtxW(ctx, "Processing message")
+		log.ResetInvocation()
+
+		entries := recorder.Entries()
+		last := entries[len(entries)-1]
+		assert.Equal(t, "sns-message-attempt", last.Fields[log.LogicalMessageId])
+		assert.Equal(t, "sns-message-attempt", last.Fields[log.SnsMessageId])
+		assert.EqualValues(t, attempt, last.Fields[log.Attempt])
+	}
+}
@@ -0,0 +1,129 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sync"
+)
+
+// attrsKey is the key of the field marking the attribute bag of a logger, encoders skip it.
+const attrsKey = "gofrlib.attrs"
+
+// attrBag holds the attributes of an invocation, in the order they were first put.
+type attrBag struct {
+	mutex  sync.RWMutex
+	keys   []string
+	values map[string]interface{}
+}
+
+func newAttrBag() *attrBag {
+	return &attrBag{values: map[string]interface{}{}}
+}
+
+func attrsField(bag *attrBag) zap.Field {
+	return zap.Field{Key: attrsKey, Type: zapcore.SkipType, Interface: bag}
+}
+
+// PutAttr sets the attribute key of the invocation of ctx to value, it's added as Body.<custom attributes prefix>.key
+// to every record logged afterwards during the invocation, by the package functions and by the loggers of the
+// contexts derived from the invocation, including the ones carried to other goroutines by CarryContext.
+// Unlike WithCustomAttr the attributes are dropped by ResetInvocation, the next invocation starts without them.
+func PutAttr(ctx context.Context, key string, value interface{}) {
+	bag := attrsOf(ctx)
+	bag.mutex.Lock()
+	defer bag.mutex.Unlock()
+	if _, exists := bag.values[key]; !exists {
+		bag.keys = append(bag.keys, key)
+	}
+	bag.values[key] = value
+	mirrorFields(ctx, []interface{}{fmt.Sprintf("Body.%s.%s", GetConfiguration().customAttributesPrefix, key), value})
+}
+
+// GetAttrs returns a copy of the attributes put by PutAttr during the invocation of ctx.
+func GetAttrs(ctx context.Context) map[string]interface{} {
+	bag := attrsOf(ctx)
+	bag.mutex.RLock()
+	defer bag.mutex.RUnlock()
+	attrs := make(map[string]interface{}, len(bag.values))
+	for key, value := range bag.values {
+		attrs[key] = value
+	}
+	return attrs
+}
+
+// attrsOf returns the attribute bag of the logger of ctx, attaching one to the invocation when it has none yet.
+func attrsOf(ctx context.Context) *attrBag {
+	if bag := findAttrs(loggerFromContext(ctx).fields); bag != nil {
+		return bag
+	}
+	var bag *attrBag
+	updateState(func(next *loggerState) {
+		if bag = findAttrs(next.invocationFields); bag == nil {
+			bag = newAttrBag()
+			next.invocationFields = appendFields(next.invocationFields, []interface{}{attrsField(bag)})
+			next.withLogger()
+		}
+	})
+	return bag
+}
+
+func findAttrs(fields []zap.Field) *attrBag {
+	for _, field := range fields {
+		if field.Key == attrsKey {
+			if bag, ok := field.Interface.(*attrBag); ok {
+				return bag
+			}
+		}
+	}
+	return nil
+}
+
+func (b *attrBag) fields(prefix string) []zapcore.Field {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if len(b.keys) == 0 {
+		return nil
+	}
+	fields := make([]zapcore.Field, len(b.keys))
+	for i, key := range b.keys {
+		fields[i] = zap.Any(fmt.Sprintf("Body.%s.%s", prefix, key), b.values[key])
+	}
+	return fields
+}
+
+// attrsCore adds the current attributes of the bag attached to its logger to every record it writes.
+type attrsCore struct {
+	zapcore.Core
+	prefix string
+	bag    *attrBag
+}
+
+func (c *attrsCore) With(fields []zapcore.Field) zapcore.Core {
+	bag := c.bag
+	if found := findAttrs(fields); found != nil {
+		bag = found
+	}
+	return &attrsCore{Core: c.Core.With(fields), prefix: c.prefix, bag: bag}
+}
+
+func (c *attrsCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.bag == nil {
+		return c.Core.Check(entry, checked)
+	}
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *attrsCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if c.bag == nil {
+		return c.Core.Write(entry, fields)
+	}
+	if attrs := c.bag.fields(c.prefix); len(attrs) > 0 {
+		fields = append(fields[:len(fields):len(fields)], attrs...)
+	}
+	return c.Core.Write(entry, fields)
+}
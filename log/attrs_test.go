@@ -0,0 +1,72 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPutAttr(t *testing.T) {
+	log.Init(log.NewConfiguration("DEBUG", "TEST-APPLICATION", "", "", "", "testPrefix"))
+	recorder := logtest.Capture(t)
+	log.SetUpFunctionURL(context.Background(), functionURLRequest("first"))
+	ctx := log.NewContext(context.Background(), "orderId", "1")
+
+	log.Info("Info msg before the attribute")
+	log.PutAttr(ctx, "channel", "web")
+	log.Info("Info msg with the attribute")
+	log.InfoCtxW(ctx, "Info msg of the context with the attribute")
+	log.PutAttr(ctx, "channel", "mobile")
+	log.Info("Info msg with the replaced attribute")
+
+	entries := recorder.Entries()
+	assert.NotContains(t, entries[1].Fields, "Body.testPrefix.channel")
+	assert.Equal(t, "web", entries[2].Fields["Body.testPrefix.channel"])
+	assert.Equal(t, "web", entries[3].Fields["Body.testPrefix.channel"])
+	assert.Equal(t, "1", entries[3].Fields["orderId"])
+	assert.Equal(t, "mobile", entries[4].Fields["Body.testPrefix.channel"])
+	assert.Equal(t, map[string]interface{}{"channel": "mobile"}, log.GetAttrs(ctx))
+}
+
+func TestAttrsAreKeptAcrossRecords(t *testing.T) {
+	log.Init(log.NewConfiguration("DEBUG", "TEST-APPLICATION", "", "", "", "testPrefix"))
+	recorder := logtest.Capture(t)
+	defer log.ResetInvocation()
+	event := events.SQSEvent{Records: []events.SQSMessage{{MessageId: "1"}, {MessageId: "2"}}}
+
+	ctx := log.SetUpSqs(context.Background(), event)
+	log.PutAttr(ctx, "channel", "web")
+	for _, message := range event.Records {
+		log.SetUpSqsRecord(ctx, message)
+		log.Info("Record processed")
+	}
+
+	for _, entry := range recorder.Entries() {
+		if entry.Message == "Record processed" {
+			assert.Equal(t, "web", entry.Fields["Body.testPrefix.channel"])
+		}
+	}
+	assert.Equal(t, map[string]interface{}{"channel": "web"}, log.GetAttrs(ctx))
+}
+
+func TestAttrsAreDroppedByResetInvocation(t *testing.T) {
+	log.Init(log.NewConfiguration("DEBUG", "TEST-APPLICATION", "", "", "", "testPrefix"))
+	recorder := logtest.Capture(t)
+	log.SetUpFunctionURL(context.Background(), functionURLRequest("first"))
+	log.PutAttr(context.Background(), "channel", "web")
+	carried := log.CarryContext(context.Background())
+
+	log.ResetInvocation()
+	log.SetUpFunctionURL(context.Background(), functionURLRequest("next"))
+	log.Info("Info msg of the next invocation")
+	log.InfoCtxW(carried, "Info msg of the carried invocation")
+
+	entries := recorder.Entries()
+	assert.NotContains(t, entries[len(entries)-2].Fields, "Body.testPrefix.channel")
+	assert.Equal(t, "web", entries[len(entries)-1].Fields["Body.testPrefix.channel"])
+	assert.Empty(t, log.GetAttrs(context.Background()))
+	assert.Equal(t, map[string]interface{}{"channel": "web"}, log.GetAttrs(carried))
+}
@@ -0,0 +1,99 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"os"
+)
+
+// Outcome is the result of an audited action.
+type Outcome string
+
+const (
+	AuditSuccess Outcome = "success"
+	AuditFailure Outcome = "failure"
+	AuditDenied  Outcome = "denied"
+
+	auditMessage = "audit"
+)
+
+type auditOptions struct {
+	paths   []string
+	writers []zapcore.WriteSyncer
+}
+
+// WithAuditOutputPaths writes audit records to the given paths, see WithOutputPaths, instead of the application outputs.
+func (c Configuration) WithAuditOutputPaths(paths ...string) Configuration {
+	c.audit.paths = append(append([]string{}, c.audit.paths...), paths...)
+	return c
+}
+
+// WithAuditWriters writes audit records to the given write syncers, e.g. a sink.BatchWriter shipping to a dedicated
+// Firehose delivery stream, instead of the application outputs.
+func (c Configuration) WithAuditWriters(writers ...zapcore.WriteSyncer) Configuration {
+	c.audit.writers = append(append([]zapcore.WriteSyncer{}, c.audit.writers...), writers...)
+	return c
+}
+
+// openAuditOutput returns nil when no audit output is configured.
+func openAuditOutput(config Configuration) (zapcore.WriteSyncer, error) {
+	if len(config.audit.paths) == 0 && len(config.audit.writers) == 0 {
+		return nil, nil
+	}
+	writers := make([]zapcore.WriteSyncer, 0, len(config.audit.writers)+1)
+	if len(config.audit.paths) > 0 {
+		sink, _, err := zap.Open(config.audit.paths...)
+		if err != nil {
+			return nil, err
+		}
+		writers = append(writers, sink)
+	}
+	for _, writer := range config.audit.writers {
+		writers = append(writers, zapcore.Lock(writer))
+	}
+	return zapcore.NewMultiWriteSyncer(writers...), nil
+}
+
+// newAuditLogger builds the logger behind Audit: json records, neither filtered by level nor sampled, redacted with the
+// rules of the configuration, written to the audit output or to the application output when there is none.
+func newAuditLogger(config Configuration, output, auditOutput zapcore.WriteSyncer) *zap.Logger {
+	if auditOutput == nil {
+		auditOutput = output
+	}
+	var core zapcore.Core = zapcore.NewCore(newJSONEncoder(config), auditOutput, zap.DebugLevel)
+	if config.core != nil {
+		core = config.core
+	}
+	if len(config.redaction) > 0 {
+		core = newRedactingCore(core, newRedactor(config.redaction))
+	}
+	return zap.New(config.withClock(core), zap.ErrorOutput(zapcore.Lock(os.Stderr))).With(resourceFields(config)...)
+}
+
+// Audit writes an audit record of actor performing action on resource, regardless of the log level and sampling.
+// Records have a fixed schema: the AuditAction, AuditActor, AuditResource and AuditOutcome fields, the trace,
+// correlation and request ids of the invocation, and keysAndValues nested under AuditDetails.
+// They are written to the audit output, see WithAuditOutputPaths and WithAuditWriters, so they can be kept apart from
+// the application logs, and to the application outputs when none is configured.
+func Audit(action, actor, resource string, outcome Outcome, keysAndValues ...interface{}) {
+	current := packageState()
+	fields := []zap.Field{
+		zap.String(AuditAction, action),
+		zap.String(AuditActor, actor),
+		zap.String(AuditResource, resource),
+		zap.String(AuditOutcome, string(outcome)),
+	}
+	for _, field := range current.invocationFields {
+		if field.Key == TraceId || field.Key == CorrelationId || field.Key == AwsRequestId {
+			fields = append(fields, field)
+		}
+	}
+	if len(keysAndValues) > 0 {
+		details := zapcore.NewMapObjectEncoder()
+		for _, field := range appendFields(nil, keysAndValues) {
+			field.AddTo(details)
+		}
+		fields = append(fields, zap.Reflect(AuditDetails, details.Fields))
+	}
+	current.audit.Info(auditMessage, fields...)
+}
@@ -0,0 +1,61 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	var buffer, auditBuffer bytes.Buffer
+	config := log.NewConfiguration(
+		"ERROR",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithAuditWriters(zapcore.AddSync(&auditBuffer)).
+		WithRedaction(log.RedactFields("password"))
+	assert.NoError(t, log.InitE(config))
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Sampled=1;Root=TraceIdValue;Parent=ParentIdValue")
+	log.SetupTraceIds(ctx)
+	defer log.ResetInvocation()
+
+	log.Audit("user.update", "admin-1", "user-42", log.AuditSuccess, "field", "email", "password", "secret")
+
+	assert.Empty(t, buffer.String())
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(auditBuffer.Bytes(), &record))
+	assert.Equal(t, "user.update", record[log.AuditAction])
+	assert.Equal(t, "admin-1", record[log.AuditActor])
+	assert.Equal(t, "user-42", record[log.AuditResource])
+	assert.Equal(t, "success", record[log.AuditOutcome])
+	assert.Equal(t, "TraceIdValue", record[log.TraceId])
+	assert.Equal(t, "TEST-APPLICATION", record[log.Application])
+	assert.Equal(t, map[string]interface{}{"field": "email", "password": log.Redacted}, record[log.AuditDetails])
+	assert.NotContains(t, record, log.SpanId)
+}
+
+func TestAuditWithoutAuditOutput(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration(
+		"ERROR",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)))
+
+	log.Audit("order.cancel", "user-1", "order-1", log.AuditDenied)
+
+	assert.Contains(t, buffer.String(), `"`+log.AuditOutcome+`":"denied"`)
+	assert.NotContains(t, buffer.String(), log.AuditDetails)
+}
@@ -0,0 +1,104 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-xray-sdk-go/instrumentation/awsv2"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/logging"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"time"
+)
+
+// InstrumentAWS configures cfg so every API call of the clients created from it is logged with its service, operation,
+// region, duration, status code, request id, retry count and error code, and recorded as a subsegment of the active
+// X-Ray segment. Failed calls are logged at WARN, the rest at INFO. The SDK logger is replaced by one writing
+// to the logger of the request context.
+func InstrumentAWS(cfg *aws.Config) {
+	cfg.Logger = sdkLogger{}
+	awsv2.AWSV2Instrumentor(&cfg.APIOptions)
+	cfg.APIOptions = append(cfg.APIOptions, addAWSLogging)
+}
+
+type awsResponseKey struct{}
+
+// awsResponse holds the status code of the last attempt of a call, set by the deserialize step.
+type awsResponse struct {
+	status int
+}
+
+func addAWSLogging(stack *middleware.Stack) error {
+	if err := stack.Initialize.Add(middleware.InitializeMiddlewareFunc("GofrlibLogging", logAWSCall), middleware.After); err != nil {
+		return err
+	}
+	return stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("GofrlibResponseStatus", recordAWSStatus), middleware.Before)
+}
+
+func recordAWSStatus(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (
+	out middleware.DeserializeOutput, metadata middleware.Metadata, err error) {
+
+	out, metadata, err = next.HandleDeserialize(ctx, in)
+	if response, ok := ctx.Value(awsResponseKey{}).(*awsResponse); ok {
+		if raw, ok := out.RawResponse.(*smithyhttp.Response); ok {
+			response.status = raw.StatusCode
+		}
+	}
+	return out, metadata, err
+}
+
+func logAWSCall(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (
+	out middleware.InitializeOutput, metadata middleware.Metadata, err error) {
+
+	response := &awsResponse{}
+	start := time.Now()
+	out, metadata, err = next.HandleInitialize(context.WithValue(ctx, awsResponseKey{}, response), in)
+	fields := []interface{}{
+		ClientAwsService, awsmiddleware.GetServiceID(ctx),
+		ClientAwsOperation, awsmiddleware.GetOperationName(ctx),
+		ClientAwsRegion, awsmiddleware.GetRegion(ctx),
+		ClientRequestDuration, time.Since(start),
+	}
+	if attempts, ok := retry.GetAttemptResults(metadata); ok && len(attempts.Results) > 1 {
+		fields = append(fields, ClientRetryCount, len(attempts.Results)-1)
+	}
+	if requestId, ok := awsmiddleware.GetRequestIDMetadata(metadata); ok {
+		fields = append(fields, ClientAwsRequestId, requestId)
+	}
+	if response.status != 0 {
+		fields = append(fields, ClientResponseStatus, response.status)
+	}
+
+	logger := enrichedLogger(ctx)
+	if err != nil {
+		var apiError smithy.APIError
+		if errors.As(err, &apiError) {
+			fields = append(fields, ClientAwsErrorCode, apiError.ErrorCode())
+		}
+		logger.Warnw("AWS request failed", append(fields, ErrorFields(err)...)...)
+	} else {
+		logger.Infow("AWS request", fields...)
+	}
+	return out, metadata, err
+}
+
+// sdkLogger writes the records of the SDK to the logger of the request context.
+type sdkLogger struct {
+	ctx context.Context
+}
+
+func (l sdkLogger) WithContext(ctx context.Context) logging.Logger {
+	return sdkLogger{ctx: ctx}
+}
+
+func (l sdkLogger) Logf(classification logging.Classification, format string, v ...interface{}) {
+	logger := FromContext(l.ctx)
+	if classification == logging.Warn {
+		logger.Warnf(format, v...)
+	} else {
+		logger.Debugf(format, v...)
+	}
+}
@@ -0,0 +1,68 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func sqsClient(t *testing.T, status int, body string) *sqs.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("x-amzn-RequestId", "2c3d4e5f")
+		writer.WriteHeader(status)
+		_, _ = writer.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := aws.Config{
+		Region:      "eu-west-1",
+		Credentials: aws.AnonymousCredentials{},
+		Retryer:     func() aws.Retryer { return aws.NopRetryer{} },
+		EndpointResolver: aws.EndpointResolverFunc(func(service, region string) (aws.Endpoint, error) {
+			return aws.Endpoint{URL: server.URL}, nil
+		}),
+	}
+	log.InstrumentAWS(&cfg)
+	return sqs.NewFromConfig(cfg)
+}
+
+func TestInstrumentAWS(t *testing.T) {
+	recorder := logtest.Capture(t)
+	client := sqsClient(t, http.StatusOK, `<SendMessageResponse><SendMessageResult><MessageId>1</MessageId></SendMessageResult></SendMessageResponse>`)
+
+	_, err := client.SendMessage(context.Background(), &sqs.SendMessageInput{
+		QueueUrl:    aws.String("https://sqs.eu-west-1.amazonaws.com/123456789012/orders"),
+		MessageBody: aws.String("{}"),
+	})
+
+	assert.NoError(t, err)
+	recorder.AssertLogged(zapcore.InfoLevel, "AWS request")
+	recorder.AssertField(log.ClientAwsService, "SQS")
+	recorder.AssertField(log.ClientAwsOperation, "SendMessage")
+	recorder.AssertField(log.ClientAwsRegion, "eu-west-1")
+	recorder.AssertField(log.ClientAwsRequestId, "2c3d4e5f")
+	recorder.AssertField(log.ClientResponseStatus, http.StatusOK)
+}
+
+func TestInstrumentAWSFailure(t *testing.T) {
+	recorder := logtest.Capture(t)
+	client := sqsClient(t, http.StatusBadRequest, `<ErrorResponse><Error><Type>Sender</Type><Code>AWS.SimpleQueueService.NonExistentQueue</Code><Message>The specified queue does not exist.</Message></Error><RequestId>2c3d4e5f</RequestId></ErrorResponse>`)
+
+	_, err := client.SendMessage(context.Background(), &sqs.SendMessageInput{
+		QueueUrl:    aws.String("https://sqs.eu-west-1.amazonaws.com/123456789012/missing"),
+		MessageBody: aws.String("{}"),
+	})
+
+	assert.Error(t, err)
+	recorder.AssertLogged(zapcore.WarnLevel, "AWS request failed")
+	recorder.AssertField(log.ClientAwsErrorCode, "AWS.SimpleQueueService.NonExistentQueue")
+	recorder.AssertField(log.ClientResponseStatus, http.StatusBadRequest)
+	recorder.AssertField(log.ClientAwsRequestId, "2c3d4e5f")
+}
@@ -0,0 +1,205 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snsTypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqsTypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	BaggageHeader = "baggage"
+	// maxBaggageMembers and maxBaggageSize are the limits of the W3C baggage header, entries beyond them aren't injected.
+	maxBaggageMembers = 64
+	maxBaggageSize    = 8192
+)
+
+var errMalformedBaggage = errors.New("malformed baggage")
+
+type baggageKey struct{}
+
+// WithBaggageFields writes the baggage entries with the given keys, e.g. "orderId", as Body.baggage.<key> fields
+// of the records of the invocations set up with them, see SetupTraceIds, and of the loggers of SetBaggage.
+func (c Configuration) WithBaggageFields(keys ...string) Configuration {
+	c.baggageFields = mergeKeys(c.baggageFields, keys)
+	return c
+}
+
+// ContextWithBaggage returns a copy of ctx carrying the entries of baggage on top of the ones it already has.
+func ContextWithBaggage(ctx context.Context, baggage map[string]string) context.Context {
+	if len(baggage) == 0 {
+		return ctx
+	}
+	merged := BaggageFromContext(ctx)
+	for key, value := range baggage {
+		merged[key] = value
+	}
+	return context.WithValue(ctx, baggageKey{}, merged)
+}
+
+// SetBaggage returns a copy of ctx carrying the baggage entry key, propagated to the outgoing calls,
+// and a logger with its field when key is selected by WithBaggageFields.
+func SetBaggage(ctx context.Context, key, value string) context.Context {
+	ctx = ContextWithBaggage(ctx, map[string]string{key: value})
+	if packageState().config.baggageFields[key] {
+		ctx = withSetUpFields(ctx, Baggage+"."+key, value)
+	}
+	return ctx
+}
+
+// GetBaggage returns the baggage entry key of ctx, an empty string when there is none.
+func GetBaggage(ctx context.Context, key string) string {
+	baggage, _ := ctx.Value(baggageKey{}).(map[string]string)
+	return baggage[key]
+}
+
+// BaggageFromContext returns a copy of the baggage entries of ctx.
+func BaggageFromContext(ctx context.Context) map[string]string {
+	baggage, _ := ctx.Value(baggageKey{}).(map[string]string)
+	copied := make(map[string]string, len(baggage))
+	for key, value := range baggage {
+		copied[key] = value
+	}
+	return copied
+}
+
+// ParseBaggage parses a "key1=value1;property,key2=value2" W3C baggage value, the values being percent decoded
+// and the properties dropped.
+func ParseBaggage(value string) (map[string]string, error) {
+	baggage := map[string]string{}
+	if strings.TrimSpace(value) == "" {
+		return baggage, nil
+	}
+	for _, member := range strings.Split(value, ",") {
+		keyValue := strings.SplitN(strings.SplitN(member, ";", 2)[0], "=", 2)
+		if len(keyValue) != 2 {
+			return nil, errMalformedBaggage
+		}
+		key := strings.TrimSpace(keyValue[0])
+		decoded, err := url.PathUnescape(strings.TrimSpace(keyValue[1]))
+		if key == "" || strings.ContainsAny(key, " \t\"(),/:;<=>?@[\\]{}") || err != nil {
+			return nil, errMalformedBaggage
+		}
+		baggage[key] = decoded
+	}
+	return baggage, nil
+}
+
+// FormatBaggage formats baggage as a W3C baggage value, sorted by key, dropping the entries beyond
+// the 64 members and 8192 bytes of the W3C limits.
+func FormatBaggage(baggage map[string]string) string {
+	keys := make([]string, 0, len(baggage))
+	for key := range baggage {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var members []string
+	size := 0
+	for _, key := range keys {
+		member := key + "=" + escapeBaggageValue(baggage[key])
+		if len(members) == maxBaggageMembers || size+len(member)+len(members) > maxBaggageSize {
+			break
+		}
+		members = append(members, member)
+		size += len(member)
+	}
+	return strings.Join(members, ",")
+}
+
+// escapeBaggageValue percent encodes the characters out of the baggage-octet range of the W3C baggage.
+func escapeBaggageValue(value string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c < 0x21 || c > 0x7e || c == '"' || c == ',' || c == ';' || c == '\\' || c == '%' {
+			fmt.Fprintf(&escaped, "%%%02X", c)
+			continue
+		}
+		escaped.WriteByte(c)
+	}
+	return escaped.String()
+}
+
+// BaggageFromHeaders reads the baggage header of headers, malformed ones are ignored.
+func BaggageFromHeaders(headers map[string]string) map[string]string {
+	return parsedBaggage(headerValue(headers, BaggageHeader))
+}
+
+// BaggageFromSqs reads the baggage message attribute of message, malformed ones are ignored.
+func BaggageFromSqs(message events.SQSMessage) map[string]string {
+	return parsedBaggage(sqsStringAttribute(message, BaggageHeader))
+}
+
+// BaggageFromSns reads the baggage message attribute of entity, malformed ones are ignored.
+func BaggageFromSns(entity events.SNSEntity) map[string]string {
+	return parsedBaggage(snsStringAttribute(entity, BaggageHeader))
+}
+
+func parsedBaggage(value string) map[string]string {
+	baggage, err := ParseBaggage(value)
+	if err != nil {
+		return nil
+	}
+	return baggage
+}
+
+// InjectBaggageHeader sets the baggage header of an outgoing request from the baggage of ctx.
+func InjectBaggageHeader(ctx context.Context, request *http.Request) {
+	if baggage := FormatBaggage(BaggageFromContext(ctx)); baggage != "" {
+		request.Header.Set(BaggageHeader, baggage)
+	}
+}
+
+func InjectBaggageSqs(ctx context.Context, input *sqs.SendMessageInput) {
+	if baggage := FormatBaggage(BaggageFromContext(ctx)); baggage != "" {
+		if input.MessageAttributes == nil {
+			input.MessageAttributes = map[string]sqsTypes.MessageAttributeValue{}
+		}
+		input.MessageAttributes[BaggageHeader] = sqsTypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(baggage),
+		}
+	}
+}
+
+func InjectBaggageSns(ctx context.Context, input *sns.PublishInput) {
+	if baggage := FormatBaggage(BaggageFromContext(ctx)); baggage != "" {
+		if input.MessageAttributes == nil {
+			input.MessageAttributes = map[string]snsTypes.MessageAttributeValue{}
+		}
+		input.MessageAttributes[BaggageHeader] = snsTypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(baggage),
+		}
+	}
+}
+
+// baggageFields returns the fields of the baggage entries of ctx selected by WithBaggageFields.
+func baggageFields(ctx context.Context) []interface{} {
+	selected := packageState().config.baggageFields
+	if len(selected) == 0 {
+		return nil
+	}
+	baggage, _ := ctx.Value(baggageKey{}).(map[string]string)
+	keys := make([]string, 0, len(baggage))
+	for key := range baggage {
+		if selected[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	fields := make([]interface{}, 0, 2*len(keys))
+	for _, key := range keys {
+		fields = append(fields, Baggage+"."+key, baggage[key])
+	}
+	return fields
+}
@@ -0,0 +1,85 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseBaggage(t *testing.T) {
+	baggage, err := log.ParseBaggage("orderId=FR-42, customerTier = gold;ttl=60,note=hello%20world%2C%20bye")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"orderId": "FR-42", "customerTier": "gold", "note": "hello world, bye"}, baggage)
+	assert.Equal(t, "customerTier=gold,note=hello%20world%2C%20bye,orderId=FR-42", log.FormatBaggage(baggage))
+}
+
+func TestParseMalformedBaggage(t *testing.T) {
+	for _, baggage := range []string{"orderId", "=FR-42", "order id=FR-42", "orderId=%zz"} {
+		_, err := log.ParseBaggage(baggage)
+		assert.Error(t, err, baggage)
+	}
+}
+
+func TestFormatBaggageLimits(t *testing.T) {
+	baggage := map[string]string{}
+	for i := 0; i < 100; i++ {
+		baggage[string(rune('a'+i%26))+strings.Repeat("k", i)] = "v"
+	}
+
+	assert.Len(t, strings.Split(log.FormatBaggage(baggage), ","), 64)
+	assert.Len(t, strings.Split(log.FormatBaggage(map[string]string{"a": strings.Repeat("v", 5000), "b": strings.Repeat("v", 5000)}), ","), 1)
+}
+
+func TestSetupTraceIdsFromHeadersWithBaggage(t *testing.T) {
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").WithBaggageFields("orderId"))
+	recorder := logtest.Capture(t)
+	defer log.ResetInvocation()
+
+	ctx := log.SetupTraceIdsFromHeaders(context.Background(), map[string]string{"Baggage": "orderId=FR-42,customerTier=gold"})
+	log.Info("Booking confirmed")
+
+	assert.Equal(t, "gold", log.GetBaggage(ctx, "customerTier"))
+	recorder.AssertLogged(zapcore.InfoLevel, "Booking confirmed")
+	recorder.AssertField(log.Baggage+".orderId", "FR-42")
+	for _, entry := range recorder.Entries() {
+		assert.NotContains(t, entry.Fields, log.Baggage+".customerTier")
+	}
+}
+
+func TestSetBaggage(t *testing.T) {
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").WithBaggageFields("orderId"))
+	recorder := logtest.Capture(t)
+	defer log.ResetInvocation()
+
+	ctx := log.SetBaggage(context.Background(), "orderId", "FR-42")
+	log.FromContext(ctx).Info("Booking confirmed")
+
+	recorder.AssertField(log.Baggage+".orderId", "FR-42")
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost", nil)
+	log.InjectBaggageHeader(ctx, request)
+	assert.Equal(t, "orderId=FR-42", request.Header.Get(log.BaggageHeader))
+}
+
+func TestSqsBaggage(t *testing.T) {
+	logtest.Capture(t)
+	defer log.ResetInvocation()
+	baggageValue := "orderId=FR-42"
+	message := events.SQSMessage{MessageAttributes: map[string]events.SQSMessageAttribute{
+		log.BaggageHeader: {DataType: "String", StringValue: &baggageValue},
+	}}
+
+	ctx := log.SetUpSqsRecord(context.Background(), message)
+	input := &sqs.SendMessageInput{}
+	log.InjectTraceSqs(ctx, input)
+
+	assert.Equal(t, "FR-42", log.GetBaggage(ctx, "orderId"))
+	assert.Equal(t, "orderId=FR-42", *input.MessageAttributes[log.BaggageHeader].StringValue)
+}
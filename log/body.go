@@ -0,0 +1,90 @@
+package log
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+const ContentTypeHeader = "Content-Type"
+
+// textContentTypes are the media types, beside text/*, whose bodies are logged as text.
+var textContentTypes = map[string]bool{
+	"application/javascript":            true,
+	"application/graphql":               true,
+	"application/x-www-form-urlencoded": true,
+	"application/x-yaml":                true,
+	"application/xml":                   true,
+	"application/yaml":                  true,
+}
+
+// BodyField returns a field holding an HTTP body the way it's best read in the logs given its contentType:
+// JSON bodies are compacted, text bodies are kept as they are, and binary ones, like images or the bodies that
+// aren't valid UTF-8, are summarized as an object with their contentType, size and sha256 instead of being dumped.
+// base64Encoded bodies, e.g. the ones API Gateway flags with isBase64Encoded, are decoded first.
+// Bodies without contentType are detected from their content.
+func BodyField(key, body string, base64Encoded bool, contentType string) zap.Field {
+	content := []byte(body)
+	if base64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return zap.Object(key, binaryBody{contentType: contentType, content: content})
+		}
+		content = decoded
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case isJSONMediaType(mediaType) || mediaType == "" && json.Valid(content):
+		var compacted bytes.Buffer
+		if json.Compact(&compacted, content) == nil {
+			return zap.String(key, compacted.String())
+		}
+		return textBodyField(key, content, contentType)
+	case mediaType == "" || strings.HasPrefix(mediaType, "text/") || textContentTypes[mediaType] || strings.HasSuffix(mediaType, "+xml"):
+		return textBodyField(key, content, contentType)
+	default:
+		return zap.Object(key, binaryBody{contentType: contentType, content: content})
+	}
+}
+
+func textBodyField(key string, content []byte, contentType string) zap.Field {
+	if !utf8.Valid(content) || bytes.IndexByte(content, 0) >= 0 {
+		return zap.Object(key, binaryBody{contentType: contentType, content: content})
+	}
+	return zap.String(key, string(content))
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// binaryBody is the summary of a body that isn't logged.
+type binaryBody struct {
+	contentType string
+	content     []byte
+}
+
+func (b binaryBody) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	if b.contentType != "" {
+		encoder.AddString("contentType", b.contentType)
+	}
+	encoder.AddInt("size", len(b.content))
+	sum := sha256.Sum256(b.content)
+	encoder.AddString("sha256", hex.EncodeToString(sum[:]))
+	return nil
+}
+
+// requestContentType returns the Content-Type header of a request with single or multi value headers.
+func requestContentType(headers map[string]string, multiValueHeaders map[string][]string) string {
+	if contentType := headerValue(headers, ContentTypeHeader); contentType != "" {
+		return contentType
+	}
+	return headerValue(firstHeaderValues(multiValueHeaders), ContentTypeHeader)
+}
@@ -0,0 +1,66 @@
+package log_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func bodyValue(key, body string, base64Encoded bool, contentType string) interface{} {
+	encoder := zapcore.NewMapObjectEncoder()
+	log.BodyField(key, body, base64Encoded, contentType).AddTo(encoder)
+	return encoder.Fields[key]
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestBodyField(t *testing.T) {
+	pdf := "%PDF-1.7\x00\xff\xfe binary"
+	for name, test := range map[string]struct {
+		body          string
+		base64Encoded bool
+		contentType   string
+		expected      interface{}
+	}{
+		"json":             {"{\n  \"id\": 1,\n  \"tags\": [\"a\"]\n}", false, "application/json; charset=utf-8", `{"id":1,"tags":["a"]}`},
+		"detected json":    {`{ "id": 1 }`, false, "", `{"id":1}`},
+		"problem json":     {`{ "title": "Not Found" }`, false, "application/problem+json", `{"title":"Not Found"}`},
+		"malformed json":   {`{"id":`, false, "application/json", `{"id":`},
+		"text":             {"id=1&name=jane", false, "application/x-www-form-urlencoded", "id=1&name=jane"},
+		"base64 json":      {base64.StdEncoding.EncodeToString([]byte(`{ "id": 1 }`)), true, "application/json", `{"id":1}`},
+		"base64 text":      {base64.StdEncoding.EncodeToString([]byte("hello")), true, "", "hello"},
+		"binary":           {base64.StdEncoding.EncodeToString([]byte(pdf)), true, "application/pdf", map[string]interface{}{"contentType": "application/pdf", "size": len(pdf), "sha256": sha256Hex(pdf)}},
+		"detected binary":  {pdf, false, "", map[string]interface{}{"size": len(pdf), "sha256": sha256Hex(pdf)}},
+		"malformed base64": {"not base64!", true, "image/png", map[string]interface{}{"contentType": "image/png", "size": 11, "sha256": sha256Hex("not base64!")}},
+	} {
+		assert.Equal(t, test.expected, bodyValue(log.RequestBody, test.body, test.base64Encoded, test.contentType), name)
+	}
+}
+
+func TestSetUpApiGatewayLogsTheBodyApart(t *testing.T) {
+	recorder := logtest.Capture(t)
+	defer log.ResetInvocation()
+	request := events.APIGatewayProxyRequest{
+		HTTPMethod:        "POST",
+		MultiValueHeaders: map[string][]string{"content-type": {"image/png"}},
+		Body:              base64.StdEncoding.EncodeToString([]byte{0x89, 'P', 'N', 'G'}),
+		IsBase64Encoded:   true,
+	}
+
+	log.SetUpApiGateway(context.Background(), request)
+
+	recorder.AssertLogged(zapcore.DebugLevel, "Got event")
+	entry := recorder.Entries()[len(recorder.Entries())-1]
+	assert.NotContains(t, entry.Fields[log.EventBody], request.Body)
+	assert.Equal(t, map[string]interface{}{"contentType": "image/png", "size": 4, "sha256": sha256Hex("\x89PNG")}, entry.Fields[log.RequestBody])
+}
@@ -0,0 +1,116 @@
+package log
+
+import (
+	"go.uber.org/zap/zapcore"
+	"sync"
+	"time"
+)
+
+// DefaultBufferSize is the buffer size used by WithBuffering when size is not positive.
+const DefaultBufferSize = 256 * 1024
+
+type bufferOptions struct {
+	enabled  bool
+	size     int
+	interval time.Duration
+}
+
+// WithBuffering keeps records in memory and writes them to the outputs in batches of up to size bytes,
+// and at least every interval when it's positive, to save syscalls on functions logging every record they process.
+// Records above ERROR are written right away. The buffer is written on Flush, which must be called before the handler
+// returns, e.g. with defer log.Flush(), otherwise the buffered records are delayed until the next invocation
+// or lost when the execution environment is frozen and shut down.
+func (c Configuration) WithBuffering(size int, interval time.Duration) Configuration {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	c.buffering = bufferOptions{enabled: true, size: size, interval: interval}
+	return c
+}
+
+// currentOutput is the output of the package logger, kept to release its buffer when Init replaces it.
+var currentOutput zapcore.WriteSyncer
+
+func replaceOutput(output zapcore.WriteSyncer) {
+	if buffered, ok := currentOutput.(*bufferedWriteSyncer); ok && buffered != output {
+		buffered.close()
+	}
+	currentOutput = output
+}
+
+type bufferedWriteSyncer struct {
+	sync.Mutex
+	out    zapcore.WriteSyncer
+	buffer []byte
+	size   int
+	stop   chan struct{}
+}
+
+func newBufferedWriteSyncer(out zapcore.WriteSyncer, options bufferOptions) *bufferedWriteSyncer {
+	buffered := &bufferedWriteSyncer{
+		out:    out,
+		buffer: make([]byte, 0, options.size),
+		size:   options.size,
+		stop:   make(chan struct{}),
+	}
+	if options.interval > 0 {
+		go buffered.flushEvery(options.interval)
+	}
+	return buffered
+}
+
+func (b *bufferedWriteSyncer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	if len(b.buffer)+len(p) > b.size {
+		if err := b.flush(); err != nil {
+			return 0, err
+		}
+	}
+	if len(p) >= b.size {
+		return b.out.Write(p)
+	}
+	b.buffer = append(b.buffer, p...)
+	return len(p), nil
+}
+
+func (b *bufferedWriteSyncer) Sync() error {
+	b.Lock()
+	err := b.flush()
+	b.Unlock()
+	if err != nil {
+		return err
+	}
+	return b.out.Sync()
+}
+
+// flush writes the buffer to the output, the caller must hold the lock.
+func (b *bufferedWriteSyncer) flush() error {
+	if len(b.buffer) == 0 {
+		return nil
+	}
+	_, err := b.out.Write(b.buffer)
+	b.buffer = b.buffer[:0]
+	return err
+}
+
+func (b *bufferedWriteSyncer) flushEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.Lock()
+			_ = b.flush()
+			b.Unlock()
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+// close stops the periodic flush and writes what is left in the buffer.
+func (b *bufferedWriteSyncer) close() {
+	close(b.stop)
+	_ = b.Sync()
+}
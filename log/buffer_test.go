@@ -0,0 +1,87 @@
+package log_test
+
+import (
+	"bytes"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is safe for the concurrent writes of the periodic flush.
+type syncBuffer struct {
+	sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.buffer.String()
+}
+
+func bufferedConfig(buffer *syncBuffer, size int, interval time.Duration) log.Configuration {
+	return log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(buffer)).
+		WithBuffering(size, interval)
+}
+
+func TestBufferingWritesOnFlush(t *testing.T) {
+	var buffer syncBuffer
+	log.Init(bufferedConfig(&buffer, 0, 0))
+
+	log.Info("First buffered msg")
+	log.Info("Second buffered msg")
+	assert.Empty(t, buffer.String())
+
+	log.Flush()
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "First buffered msg")
+	assert.Contains(t, lines[1], "Second buffered msg")
+}
+
+func TestBufferingWritesFullBuffer(t *testing.T) {
+	var buffer syncBuffer
+	log.Init(bufferedConfig(&buffer, 400, 0))
+
+	log.Info("First buffered msg")
+	log.Info("Second buffered msg")
+	assert.Contains(t, buffer.String(), "First buffered msg")
+	assert.NotContains(t, buffer.String(), "Second buffered msg")
+	log.Flush()
+}
+
+func TestBufferingWritesPeriodically(t *testing.T) {
+	var buffer syncBuffer
+	log.Init(bufferedConfig(&buffer, 0, 10*time.Millisecond))
+
+	log.Info("Buffered msg")
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buffer.String(), "Buffered msg")
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestBufferingWritesOnInit(t *testing.T) {
+	var buffer syncBuffer
+	log.Init(bufferedConfig(&buffer, 0, 0))
+
+	log.Info("Buffered msg")
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").WithWriters(zapcore.AddSync(&bytes.Buffer{})))
+	assert.Contains(t, buffer.String(), "Buffered msg")
+}
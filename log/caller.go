@@ -0,0 +1,65 @@
+package log
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type callerOptions struct {
+	disabled bool
+	skip     int
+}
+
+type stacktraceOptions struct {
+	configured bool
+	disabled   bool
+	level      zapcore.Level
+}
+
+// WithCaller enables or disables the Caller field, enabled by default.
+func (c Configuration) WithCaller(enabled bool) Configuration {
+	c.caller.disabled = !enabled
+	return c
+}
+
+// WithCallerSkip skips skip more frames when reporting the Caller, for applications logging through their own facade:
+// a skip of 1 reports the caller of the facade function instead of the facade itself.
+func (c Configuration) WithCallerSkip(skip int) Configuration {
+	c.caller.skip = skip
+	return c
+}
+
+// WithStacktraceLevel attaches a StackTrace to the records at level or above, ERROR by default.
+func (c Configuration) WithStacktraceLevel(level zapcore.Level) Configuration {
+	c.stacktrace = stacktraceOptions{configured: true, level: level}
+	return c
+}
+
+// WithoutStacktrace never attaches a StackTrace to the records.
+func (c Configuration) WithoutStacktrace() Configuration {
+	c.stacktrace = stacktraceOptions{configured: true, disabled: true}
+	return c
+}
+
+// callerOptions returns the zap options adding the caller and the stack trace of the records.
+func (c Configuration) callerOptions() []zap.Option {
+	var options []zap.Option
+	if !c.caller.disabled {
+		options = append(options, zap.AddCaller())
+	}
+	switch {
+	case !c.stacktrace.configured:
+		options = append(options, zap.AddStacktrace(zapcore.ErrorLevel))
+	case !c.stacktrace.disabled:
+		options = append(options, zap.AddStacktrace(c.stacktrace.level))
+	}
+	return options
+}
+
+func (c Configuration) validateCaller() error {
+	if c.caller.skip < 0 {
+		return fmt.Errorf("caller skip can't be negative, got %d", c.caller.skip)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func initCallerLogger(t *testing.T, configure func(log.Configuration) log.Configuration) *bytes.Buffer {
+	var buffer bytes.Buffer
+	assert.NoError(t, log.InitE(configure(log.NewConfiguration(
+		"INFO",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)))))
+	return &buffer
+}
+
+func lastEntry(t *testing.T, buffer *bytes.Buffer) map[string]interface{} {
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &entry))
+	return entry
+}
+
+// facadeInfo stands for the logging facade of an application.
+func facadeInfo(msg string) {
+	log.Info(msg)
+}
+
+func TestWithCallerSkip(t *testing.T) {
+	buffer := initCallerLogger(t, func(config log.Configuration) log.Configuration {
+		return config.WithCallerSkip(1)
+	})
+
+	_, _, line, _ := runtime.Caller(0)
+	facadeInfo("Info msg")
+
+	assert.Equal(t, fmt.Sprintf("log/caller_test.go:%d", line+1), lastEntry(t, buffer)[log.Caller])
+}
+
+func TestWithCallerDisabled(t *testing.T) {
+	buffer := initCallerLogger(t, func(config log.Configuration) log.Configuration {
+		return config.WithCaller(false)
+	})
+
+	log.Info("Info msg")
+
+	assert.NotContains(t, lastEntry(t, buffer), log.Caller)
+}
+
+func TestWithStacktraceLevel(t *testing.T) {
+	buffer := initCallerLogger(t, func(config log.Configuration) log.Configuration {
+		return config.WithStacktraceLevel(zapcore.WarnLevel)
+	})
+
+	log.Info("Info msg")
+	assert.NotContains(t, lastEntry(t, buffer), log.StackTrace)
+	log.Warn("Warn msg")
+	assert.Contains(t, lastEntry(t, buffer)[log.StackTrace], "TestWithStacktraceLevel")
+}
+
+func TestWithoutStacktrace(t *testing.T) {
+	buffer := initCallerLogger(t, func(config log.Configuration) log.Configuration {
+		return config.WithoutStacktrace()
+	})
+
+	log.Error("Error msg")
+
+	assert.NotContains(t, lastEntry(t, buffer), log.StackTrace)
+	assert.Error(t, log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").WithCallerSkip(-1).Validate())
+}
@@ -0,0 +1,65 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"runtime/debug"
+)
+
+// CarryContext returns a copy of ctx carrying a snapshot of the invocation logger: the fields attached by With,
+// WithCustomAttr, SetupTraceIds and the SetUp* helpers, the trace ids found in ctx and the fields of NewContext.
+// The package level functions follow state that the next invocation replaces, goroutines fanning out work
+// must log with the *Ctx functions or FromContext and a carried context, which don't touch that state
+// and keep the fields of the invocation that started them.
+//
+//	ctx = log.CarryContext(ctx)
+//	group, ctx := errgroup.WithContext(ctx)
+//	group.Go(func() error {
+//		log.InfoCtxW(ctx, "Processing record")
+//		...
+//	})
+func CarryContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	current := packageState()
+	fields := current.invocationFields
+	if carried, ok := ctx.Value(contextKey{}).(*contextLogger); ok {
+		fields = appendFields(fields, fieldsToKeysAndValues(carried.fields))
+	}
+	base := current.base.Desugar().WithOptions(zap.AddCallerSkip(-1))
+	logger := (&contextLogger{base: base}).with(fieldsToKeysAndValues(fields))
+	if !logger.has(TraceId) {
+		if traceFields := traceIdFields(ctx); traceFields != nil {
+			logger = logger.with(traceFields)
+		}
+	}
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// Go runs fn in a new goroutine with CarryContext(ctx), logging its panics like RecoverAndLog instead of crashing
+// the execution environment.
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	ctx = CarryContext(ctx)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				logger := FromContext(ctx)
+				logger.Errorw("Recovered from panic",
+					PanicValue, fmt.Sprintf("%v", recovered),
+					StackTrace, string(debug.Stack()))
+				_ = logger.Sync()
+			}
+		}()
+		fn(ctx)
+	}()
+}
+
+func fieldsToKeysAndValues(fields []zap.Field) []interface{} {
+	keysAndValues := make([]interface{}, len(fields))
+	for i, field := range fields {
+		keysAndValues[i] = field
+	}
+	return keysAndValues
+}
@@ -0,0 +1,71 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCarryContext(t *testing.T) {
+	log.Init(log.NewConfiguration("DEBUG", "TEST-APPLICATION", "", "", "", "testPrefix"))
+	recorder := logtest.Capture(t)
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+	log.SetUpFunctionURL(ctx, functionURLRequest("first"))
+	log.WithCustomAttr("channel", "web")
+	carried := log.CarryContext(log.NewContext(ctx, "orderId", "1"))
+
+	var workers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		workers.Add(1)
+		log.Go(carried, func(ctx context.Context) {
+			defer workers.Done()
+			for j := 0; j < 50; j++ {
+				log.InfoCtxW(ctx, "Processing record")
+			}
+		})
+	}
+	for i := 0; i < 50; i++ {
+		log.ResetInvocation()
+		log.SetUpFunctionURL(context.Background(), functionURLRequest("next"))
+	}
+	workers.Wait()
+
+	processed := 0
+	for _, entry := range recorder.Entries() {
+		if entry.Message != "Processing record" {
+			continue
+		}
+		processed++
+		assert.Equal(t, "first", entry.Fields[log.RequestId])
+		assert.Equal(t, "1", entry.Fields["orderId"])
+		assert.Equal(t, "web", entry.Fields["Body.testPrefix.channel"])
+		assert.Equal(t, "1-5759e988-bd862e3fe1be46a994272793", entry.Fields[log.TraceId])
+	}
+	assert.Equal(t, 200, processed)
+}
+
+func TestGoRecoversPanic(t *testing.T) {
+	recorder := logtest.Capture(t)
+	done := make(chan struct{})
+
+	log.Go(context.Background(), func(ctx context.Context) {
+		defer close(done)
+		panic("worker failed")
+	})
+	<-done
+
+	assert.Eventually(t, func() bool {
+		return len(recorder.Entries()) == 1
+	}, time.Second, time.Millisecond)
+	recorder.AssertField(log.PanicValue, "worker failed")
+}
+
+func functionURLRequest(id string) log.FunctionURLRequest {
+	return log.FunctionURLRequest{RequestContext: events.APIGatewayV2HTTPRequestContext{RequestID: id}}
+}
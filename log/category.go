@@ -0,0 +1,54 @@
+package log
+
+import "errors"
+
+// Category classifies errors so metric filters and alarms can tell noise from failures that need attention,
+// it's written in the ErrorCategory field.
+type Category string
+
+const (
+	// Retryable failures are expected to succeed when retried, e.g. throttling or timeouts.
+	Retryable Category = "Retryable"
+	// ClientError failures are caused by invalid input and won't succeed when retried.
+	ClientError Category = "ClientError"
+	// DependencyFailure failures are caused by a downstream service being unavailable or failing.
+	DependencyFailure Category = "DependencyFailure"
+	// DataCorruption failures are caused by stored data that is inconsistent or can't be read.
+	DataCorruption Category = "DataCorruption"
+)
+
+type categorizedError struct {
+	error
+	category Category
+}
+
+func (e *categorizedError) Unwrap() error {
+	return e.error
+}
+
+// Categorize returns err tagged with category, ErrorFields, ErrorErr and WarnErr write it in the ErrorCategory field.
+func Categorize(err error, category Category) error {
+	if err == nil {
+		return nil
+	}
+	return &categorizedError{error: err, category: category}
+}
+
+// CategoryOf returns the category of the outermost error of the chain of err tagged by Categorize.
+func CategoryOf(err error) (Category, bool) {
+	var categorized *categorizedError
+	if errors.As(err, &categorized) {
+		return categorized.category, true
+	}
+	return "", false
+}
+
+// ErrorWithCategory behaves like ErrorW and writes category in the ErrorCategory field.
+func ErrorWithCategory(category Category, msg string, keysAndValues ...interface{}) {
+	logger().Errorw(msg, append(keysAndValues, ErrorCategory, string(category))...)
+}
+
+// WarnWithCategory behaves like WarnW and writes category in the ErrorCategory field.
+func WarnWithCategory(category Category, msg string, keysAndValues ...interface{}) {
+	logger().Warnw(msg, append(keysAndValues, ErrorCategory, string(category))...)
+}
@@ -0,0 +1,42 @@
+package log_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func TestErrorWithCategory(t *testing.T) {
+	recorder := logtest.Capture(t)
+
+	log.ErrorWithCategory(log.DataCorruption, "Unreadable booking", "bookingId", "1")
+	log.WarnWithCategory(log.Retryable, "Throttled")
+
+	recorder.AssertLogged(zapcore.ErrorLevel, "Unreadable booking")
+	recorder.AssertField(log.ErrorCategory, "DataCorruption")
+	recorder.AssertLogged(zapcore.WarnLevel, "Throttled")
+	recorder.AssertField(log.ErrorCategory, "Retryable")
+}
+
+func TestCategorize(t *testing.T) {
+	recorder := logtest.Capture(t)
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("unable to fetch order: %w", log.Categorize(cause, log.DependencyFailure))
+
+	category, ok := log.CategoryOf(err)
+	assert.True(t, ok)
+	assert.Equal(t, log.DependencyFailure, category)
+	assert.True(t, errors.Is(err, cause))
+	_, ok = log.CategoryOf(cause)
+	assert.False(t, ok)
+	assert.Nil(t, log.Categorize(nil, log.Retryable))
+
+	log.ErrorErr(err, "Processing failed")
+	recorder.AssertField(log.ErrorCategory, "DependencyFailure")
+	recorder.AssertField(log.ErrorKind, "*errors.errorString")
+	recorder.AssertField(log.ErrorChain, []interface{}{"unable to fetch order: connection refused", "connection refused"})
+}
@@ -0,0 +1,83 @@
+package log
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"go.uber.org/zap"
+)
+
+// SetUpCfnCustomResource attaches the request type, resource ids and stack id of the request to the invocation.
+// The pre-signed response url is never logged.
+func SetUpCfnCustomResource(ctx context.Context, event cfn.Event) context.Context {
+	ctx = SetupTraceIds(ctx)
+	ctx = withSetUpFields(ctx,
+		RequestId, event.RequestID,
+		RequestType, string(event.RequestType),
+		ResourceType, event.ResourceType,
+		LogicalResourceId, event.LogicalResourceID,
+		PhysicalResourceId, event.PhysicalResourceID,
+		StackId, event.StackID)
+	if IsDebugEnabled() {
+		event.ResponseURL = ""
+		DebugW("Got event",
+			EventSource, "cloudformation",
+			LazyJSON(EventBody, event))
+	}
+	return ctx
+}
+
+// RespondCfnCustomResource sends the result of the request to CloudFormation, FAILED with the error as reason
+// when err isn't nil, and logs it with the logger of ctx. The log stream name is used when physicalResourceId is empty,
+// like cfn.LambdaWrap does.
+func RespondCfnCustomResource(ctx context.Context, event cfn.Event, physicalResourceId string, data map[string]interface{}, err error) error {
+	response := cfn.NewResponse(&event)
+	response.PhysicalResourceID = physicalResourceId
+	if response.PhysicalResourceID == "" {
+		response.PhysicalResourceID = event.PhysicalResourceID
+	}
+	if response.PhysicalResourceID == "" {
+		response.PhysicalResourceID = lambdacontext.LogStreamName
+	}
+	logger := FromContext(ctx).Desugar().WithOptions(zap.AddCallerSkip(1)).Sugar()
+	if err != nil {
+		response.Status = cfn.StatusFailed
+		response.Reason = err.Error()
+		logger.Errorw("Custom resource failed", append([]interface{}{
+			PhysicalResourceId, response.PhysicalResourceID,
+			ResponseStatus, string(response.Status),
+		}, ErrorFields(err)...)...)
+	} else {
+		response.Status = cfn.StatusSuccess
+		response.Data = data
+		logger.Infow("Custom resource succeeded",
+			PhysicalResourceId, response.PhysicalResourceID,
+			ResponseStatus, string(response.Status))
+	}
+
+	if sendErr := response.Send(); sendErr != nil {
+		logger.Errorw("Unable to send custom resource response", ErrorFields(sendErr)...)
+		return sendErr
+	}
+	return nil
+}
+
+// WrapCfnCustomResource returns a handler that sets up the logger with the request, runs fn and responds
+// with its result, a response is also sent when fn panics so the stack doesn't hang until it times out.
+func WrapCfnCustomResource(fn cfn.CustomResourceFunction) cfn.CustomResourceLambdaFunction {
+	return func(ctx context.Context, event cfn.Event) (reason string, err error) {
+		ctx = SetUpCfnCustomResource(ctx, event)
+		defer ResetInvocation()
+
+		var physicalResourceId string
+		var data map[string]interface{}
+		func() {
+			defer RecoverAndLog(ctx, &err)
+			physicalResourceId, data, err = fn(ctx, event)
+		}()
+		if sendErr := RespondCfnCustomResource(ctx, event, physicalResourceId, data, err); sendErr != nil {
+			return sendErr.Error(), sendErr
+		}
+		return "", nil
+	}
+}
@@ -0,0 +1,86 @@
+package log_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func cfnServer(t *testing.T, responses *[]cfn.Response) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var response cfn.Response
+		assert.NoError(t, json.NewDecoder(request.Body).Decode(&response))
+		*responses = append(*responses, response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func cfnEvent(responseURL string) cfn.Event {
+	return cfn.Event{
+		RequestType:       cfn.RequestCreate,
+		RequestID:         "request-1",
+		ResponseURL:       responseURL + "/?X-Amz-Signature=secret",
+		ResourceType:      "Custom::Bucket",
+		LogicalResourceID: "Bucket",
+		StackID:           "arn:aws:cloudformation:eu-west-1:123456789012:stack/bookings/1",
+	}
+}
+
+func TestWrapCfnCustomResource(t *testing.T) {
+	recorder := logtest.Capture(t)
+	var responses []cfn.Response
+	server := cfnServer(t, &responses)
+
+	handler := log.WrapCfnCustomResource(func(ctx context.Context, event cfn.Event) (string, map[string]interface{}, error) {
+		log.Info("Creating bucket")
+		return "bucket-1", map[string]interface{}{"Arn": "arn:aws:s3:::bucket-1"}, nil
+	})
+	reason, err := handler(context.Background(), cfnEvent(server.URL))
+
+	assert.NoError(t, err)
+	assert.Empty(t, reason)
+	assert.Len(t, responses, 1)
+	assert.Equal(t, cfn.StatusSuccess, responses[0].Status)
+	assert.Equal(t, "bucket-1", responses[0].PhysicalResourceID)
+	assert.Equal(t, "request-1", responses[0].RequestID)
+	recorder.AssertLogged(zapcore.InfoLevel, "Custom resource succeeded")
+	recorder.AssertField(log.RequestType, "Create")
+	recorder.AssertField(log.LogicalResourceId, "Bucket")
+	for _, entry := range recorder.Entries() {
+		assert.NotContains(t, log.ToString(entry.Fields), "X-Amz-Signature")
+	}
+}
+
+func TestWrapCfnCustomResourceFailures(t *testing.T) {
+	recorder := logtest.Capture(t)
+	var responses []cfn.Response
+	server := cfnServer(t, &responses)
+
+	failing := log.WrapCfnCustomResource(func(ctx context.Context, event cfn.Event) (string, map[string]interface{}, error) {
+		return "", nil, errors.New("bucket already exists")
+	})
+	panicking := log.WrapCfnCustomResource(func(ctx context.Context, event cfn.Event) (string, map[string]interface{}, error) {
+		panic("unexpected")
+	})
+	_, err := failing(context.Background(), cfnEvent(server.URL))
+	assert.NoError(t, err)
+	_, err = panicking(context.Background(), cfnEvent(server.URL))
+	assert.NoError(t, err)
+
+	assert.Len(t, responses, 2)
+	assert.Equal(t, cfn.StatusFailed, responses[0].Status)
+	assert.Equal(t, "bucket already exists", responses[0].Reason)
+	assert.Equal(t, cfn.StatusFailed, responses[1].Status)
+	assert.Equal(t, "panic: unexpected", responses[1].Reason)
+	recorder.AssertLogged(zapcore.ErrorLevel, "Custom resource failed")
+	recorder.AssertLogged(zapcore.ErrorLevel, "Recovered from panic")
+}
@@ -0,0 +1,71 @@
+package log
+
+import (
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+// Clock is the time source of the record timestamps.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to a Clock.
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// FixedClock returns a Clock always telling t, for golden file tests.
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time {
+		return t
+	})
+}
+
+// WithClock sets the time source of the timestamps of every record, including the Emit and Audit ones,
+// so tests and replay tools get deterministic timestamps. The system clock is used by default.
+func (c Configuration) WithClock(clock Clock) Configuration {
+	c.clock = clock
+	return c
+}
+
+// Now returns the time of the clock of the configuration, see WithClock, e.g. for timestamps embedded in records.
+func Now() time.Time {
+	if clock := GetConfiguration().clock; clock != nil {
+		return clock.Now()
+	}
+	return time.Now()
+}
+
+// withClock wraps core so the records it checks are timestamped by the clock of the configuration, if any.
+func (c Configuration) withClock(core zapcore.Core) zapcore.Core {
+	if c.clock == nil {
+		return core
+	}
+	return &clockCore{Core: core, clock: c.clock}
+}
+
+type clockCore struct {
+	zapcore.Core
+	clock Clock
+}
+
+func (c *clockCore) With(fields []zapcore.Field) zapcore.Core {
+	return &clockCore{Core: c.Core.With(fields), clock: c.clock}
+}
+
+func (c *clockCore) namespaceEnabled(name string, level zapcore.Level) bool {
+	return namespaceEnabled(c.Core, name, level)
+}
+
+func (c *clockCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	entry.Time = c.clock.Now()
+	return c.Core.Check(entry, checked)
+}
+
+func (c *clockCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Time = c.clock.Now()
+	return c.Core.Write(entry, fields)
+}
@@ -0,0 +1,48 @@
+package log_test
+
+import (
+	"bytes"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithClock(t *testing.T) {
+	var buffer bytes.Buffer
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	clock := log.ClockFunc(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	})
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "TEST-PROJECT", "TEST-PROJECT-GROUP", "1.0.0", "").
+		WithCaller(false).
+		WithClock(clock).
+		WithWriters(zapcore.AddSync(&buffer)))
+
+	log.Info("First record")
+	log.Emit("Emitted record")
+	log.Audit("delete", "jane", "booking/1", log.AuditSuccess)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Equal(t, `{"SeverityText":"INFO","Timestamp":"2021-03-04T05:06:08.000Z","Body.message":"First record",`+
+		`"Resource.application":"TEST-APPLICATION","Resource.project":"TEST-PROJECT","Resource.projectGroup":"TEST-PROJECT-GROUP","Resource.version":"1.0.0",`+
+		`"Resource.schemaVersion":1}`, lines[0])
+	assert.Contains(t, lines[1], `"Timestamp":"2021-03-04T05:06:09.000Z"`)
+	assert.Contains(t, lines[2], `"Timestamp":"2021-03-04T05:06:10.000Z"`)
+	assert.Equal(t, time.Date(2021, 3, 4, 5, 6, 11, 0, time.UTC), log.Now())
+}
+
+func TestFixedClock(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithClock(log.FixedClock(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))).
+		WithWriters(zapcore.AddSync(&buffer)))
+
+	log.Info("First record")
+	log.Info("Second record")
+
+	assert.Equal(t, 2, strings.Count(buffer.String(), `"Timestamp":"2021-03-04T05:06:07.000Z"`))
+}
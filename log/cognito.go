@@ -0,0 +1,44 @@
+package log
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func SetUpCognitoPreSignUp(ctx context.Context, event events.CognitoEventUserPoolsPreSignup) context.Context {
+	return setUpCognito(ctx, event.CognitoEventUserPoolsHeader)
+}
+
+func SetUpCognitoPostConfirmation(ctx context.Context, event events.CognitoEventUserPoolsPostConfirmation) context.Context {
+	return setUpCognito(ctx, event.CognitoEventUserPoolsHeader)
+}
+
+func SetUpCognitoPreTokenGeneration(ctx context.Context, event events.CognitoEventUserPoolsPreTokenGen) context.Context {
+	return setUpCognito(ctx, event.CognitoEventUserPoolsHeader)
+}
+
+func SetUpCognitoCustomMessage(ctx context.Context, event events.CognitoEventUserPoolsCustomMessage) context.Context {
+	return setUpCognito(ctx, event.CognitoEventUserPoolsHeader)
+}
+
+// HashUserName returns the hex encoded sha256 of userName.
+func HashUserName(userName string) string {
+	return hashValue(userName)
+}
+
+// setUpCognito never logs the event body, it holds user attributes like emails and phone numbers.
+// The user name is logged as HashUserName so records of the same user can still be correlated.
+func setUpCognito(ctx context.Context, header events.CognitoEventUserPoolsHeader) context.Context {
+	ctx = SetupTraceIds(ctx)
+	ctx = withSetUpFields(ctx,
+		TriggerSource, header.TriggerSource,
+		UserPoolId, header.UserPoolID,
+		ClientId, header.CallerContext.ClientID,
+		Region, header.Region,
+		UserNameHash, HashUserName(header.UserName))
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, "cognito")
+	}
+	return ctx
+}
@@ -0,0 +1,34 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetUpCognitoPreSignUp(t *testing.T) {
+	recorder := logtest.Capture(t)
+	event := events.CognitoEventUserPoolsPreSignup{
+		CognitoEventUserPoolsHeader: events.CognitoEventUserPoolsHeader{
+			TriggerSource: "PreSignUp_SignUp",
+			Region:        "eu-west-1",
+			UserPoolID:    "eu-west-1_pool",
+			UserName:      "john.doe@example.com",
+		},
+		Request: events.CognitoEventUserPoolsPreSignupRequest{
+			UserAttributes: map[string]string{"email": "john.doe@example.com"},
+		},
+	}
+	log.SetUp(context.Background(), event)
+	log.Info("Signing up")
+
+	recorder.AssertField(log.TriggerSource, "PreSignUp_SignUp")
+	recorder.AssertField(log.UserPoolId, "eu-west-1_pool")
+	recorder.AssertField(log.UserNameHash, log.HashUserName("john.doe@example.com"))
+	for _, entry := range recorder.Entries() {
+		assert.NotContains(t, log.ToString(entry.Fields), "john.doe")
+	}
+}
@@ -0,0 +1,50 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+// loadTime approximates the start of the execution environment, it's set when the package is initialized.
+var loadTime = time.Now()
+
+var coldStart = &coldStartState{cold: true}
+
+type coldStartState struct {
+	sync.Mutex
+	seen      bool
+	requestId string
+	cold      bool
+	duration  time.Duration
+}
+
+// observe records the invocation with requestId, it returns whether it's the cold start invocation
+// and whether it's the first time the cold start invocation is seen.
+func (s *coldStartState) observe(requestId string) (cold bool, first bool) {
+	s.Lock()
+	defer s.Unlock()
+	if !s.seen {
+		s.seen = true
+		s.requestId = requestId
+		s.duration = time.Since(loadTime)
+		return true, true
+	}
+	s.cold = s.cold && s.requestId == requestId
+	return s.cold, false
+}
+
+// ColdStart reports whether the current invocation is the first one of the execution environment.
+// It's detected by SetupTraceIds and the SetUp* helpers, which attach it to every record as IsColdStart
+// and log a "Cold start" record with the InitDuration, the time elapsed since this package was loaded, on the first invocation.
+func ColdStart() bool {
+	coldStart.Lock()
+	defer coldStart.Unlock()
+	return coldStart.cold
+}
+
+func logColdStart() {
+	coldStart.Lock()
+	duration := coldStart.duration
+	coldStart.Unlock()
+	logger().Infow("Cold start", InitDuration, duration)
+}
@@ -0,0 +1,45 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestColdStart(t *testing.T) {
+	if os.Getenv("GOFRLIB_COLD_START") == "true" {
+		log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", ""))
+		invoke := func(requestId string) {
+			log.SetupTraceIds(lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: requestId}))
+			log.InfoW("Invoked", "requestId", requestId, "coldStart", log.ColdStart())
+		}
+		invoke("cold-request")
+		invoke("cold-request")
+		invoke("warm-request")
+		return
+	}
+	command := exec.Command(os.Args[0], "-test.run=^TestColdStart$")
+	command.Env = append(os.Environ(), "GOFRLIB_COLD_START=true")
+	output, err := command.CombinedOutput()
+	assert.NoError(t, err)
+
+	var records []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "{") {
+			records = append(records, line)
+		}
+	}
+	assert.Len(t, records, 4)
+	assert.Contains(t, records[0], `"Body.message":"Cold start"`)
+	assert.Contains(t, records[0], `"`+log.InitDuration+`":`)
+	assert.Contains(t, records[1], `"`+log.IsColdStart+`":true`)
+	assert.Contains(t, records[1], `"coldStart":true`)
+	assert.Contains(t, records[2], `"coldStart":true`)
+	assert.Contains(t, records[3], `"`+log.IsColdStart+`":false`)
+	assert.Contains(t, records[3], `"coldStart":false`)
+}
@@ -0,0 +1,72 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// These tests are meant to run with -race.
+
+func TestConcurrentWith(t *testing.T) {
+	var buffer syncBuffer
+	log.Init(log.NewConfiguration("DEBUG", "TEST-APPLICATION", "", "", "", "testPrefix").
+		WithoutSampling().
+		WithWriters(zapcore.AddSync(&buffer)))
+
+	var group sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		group.Add(1)
+		go func(i int) {
+			defer group.Done()
+			log.With(fmt.Sprintf("with%d", i), i)
+			log.WithCustomAttr(fmt.Sprintf("attr%d", i), i)
+			log.InfoW("Concurrent record", "worker", i)
+		}(i)
+	}
+	group.Wait()
+	log.Info("Last record")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	var last map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &last))
+	for i := 0; i < 8; i++ {
+		assert.Equal(t, float64(i), last[fmt.Sprintf("with%d", i)])
+		assert.Equal(t, float64(i), last[fmt.Sprintf("Body.testPrefix.attr%d", i)])
+	}
+}
+
+func TestConcurrentInvocations(t *testing.T) {
+	log.Init(log.NewConfiguration("DEBUG", "TEST-APPLICATION", "", "", "", "").
+		WithoutSampling().
+		WithWriters(zapcore.AddSync(&bytes.Buffer{})))
+	repository := log.Named("repository")
+
+	var group sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		group.Add(1)
+		go func(i int) {
+			defer group.Done()
+			for j := 0; j < 20; j++ {
+				ctx := log.SetupTraceIds(context.Background())
+				log.SetUpFunctionURL(ctx, functionURLRequest(fmt.Sprint(i)))
+				log.DebugW("Handling request", "iteration", j)
+				log.InfoCtxW(ctx, "Context record")
+				repository.InfoW("Named record")
+				log.Default().WarnW("Default record")
+				_ = log.SetLevel([]string{"DEBUG", "INFO"}[j%2])
+				_ = log.GetLevel()
+				log.ResetInvocation()
+			}
+		}(i)
+	}
+	group.Wait()
+	assert.NoError(t, log.SetLevel("DEBUG"))
+}
@@ -1,5 +1,6 @@
 package log
 
+// Keys of the fields written by this package, the output can use other names with WithFieldNames or a Profile.
 const (
 	TraceId       = "TraceId"
 	CorrelationId = "CorrelationId"
@@ -12,12 +13,204 @@ const (
 	Message    = "Body.message"
 	StackTrace = "Body.stacktrace"
 
-	Logger       = "Resource.logger"
+	ErrorKind     = "Body.error.kind"
+	ErrorMessage  = "Body.error.message"
+	ErrorChain    = "Body.error.chain"
+	ErrorStack    = "Body.error.stack"
+	ErrorCategory = "Body.error.category"
+
+	Caller       = "Resource.logger"
+	Namespace    = "Resource.namespace"
 	Application  = "Resource.application"
 	Project      = "Resource.project"
 	ProjectGroup = "Resource.projectGroup"
 	Version      = "Resource.version"
+	GitSha       = "Resource.gitSha"
+	DeploymentId = "Resource.deploymentId"
+	// SchemaVersion holds LogSchemaVersion, the version of the field layout of the records.
+	SchemaVersion = "Resource.schemaVersion"
+
+	// Logger is the former name of Caller.
+	//
+	// Deprecated: use Caller.
+	Logger = Caller
+
+	AwsRequestId    = "Body.context.lambda.awsRequestId"
+	FunctionArn     = "Body.context.lambda.functionArn"
+	FunctionVersion = "Body.context.lambda.functionVersion"
+	FunctionMemory  = "Body.context.lambda.memoryLimitInMB"
+	RemainingTime   = "Body.context.lambda.remainingTime"
+	IsColdStart     = "Body.context.lambda.coldStart"
+	InitDuration    = "Body.context.lambda.initDuration"
+
+	AuditAction   = "Body.audit.action"
+	AuditActor    = "Body.audit.actor"
+	AuditResource = "Body.audit.resource"
+	AuditOutcome  = "Body.audit.outcome"
+	AuditDetails  = "Body.audit.details"
+
+	TenantId      = "Body.tenant.id"
+	TenantAccount = "Body.tenant.account"
+
+	Baggage = "Body.baggage"
+
+	ErrorBudgetErrors    = "Body.errorBudget.errors"
+	ErrorBudgetThreshold = "Body.errorBudget.threshold"
+	ErrorBudgetWindow    = "Body.errorBudget.window"
+
+	BatchRecords         = "Body.batch.records"
+	BatchSucceeded       = "Body.batch.succeeded"
+	BatchRetried         = "Body.batch.retried"
+	BatchDeadLettered    = "Body.batch.deadLettered"
+	BatchDuration        = "Body.batch.duration"
+	BatchRecordAverage   = "Body.batch.recordDuration.average"
+	BatchRecordMax       = "Body.batch.recordDuration.max"
+	BatchRecordHistogram = "Body.batch.recordDuration.histogram"
+
+	SchemaField    = "Body.schema.field"
+	SchemaExpected = "Body.schema.expected"
+	SchemaActual   = "Body.schema.actual"
+
+	RuntimeGoroutines = "Body.runtime.goroutines"
+	RuntimeHeapAlloc  = "Body.runtime.heapAlloc"
+	RuntimeGCPause    = "Body.runtime.gcPause"
+	RuntimeNumGC      = "Body.runtime.numGC"
+	RuntimeRSS        = "Body.runtime.rss"
+
+	InvocationDuration = "Body.invocation.duration"
+	DebugSampled       = "Body.invocation.debugSampled"
+	TailEscalated      = "Body.invocation.tailEscalated"
+	WideEventStatus    = "Body.invocation.status"
+	WideEventRecords   = "Body.invocation.records"
+	WideEventCounts    = "Body.invocation.counts"
+	WideEventDurations = "Body.invocation.durations"
+	SubsegmentId       = "Body.subsegment.id"
+	SubsegmentName     = "Body.subsegment.name"
+	SubsegmentDuration = "Body.subsegment.duration"
+	RepeatCount        = "Body.repeatCount"
+	ValidationErrors   = "Body.validation.errors"
+	Truncated          = "Body.truncated"
 
 	EventSource = "Body.origin.event.eventSource"
 	EventBody   = "Body.origin.event.eventBody"
+
+	MessageId         = "Body.origin.event.messageId"
+	ReceiveCount      = "Body.origin.event.receiveCount"
+	Attempt           = "Body.origin.event.attempt"
+	LogicalMessageId  = "Body.origin.event.logicalMessageId"
+	QueueArn          = "Body.origin.event.queueArn"
+	TopicArn          = "Body.origin.event.topicArn"
+	SnsMessageId      = "Body.origin.event.snsMessageId"
+	MessageAttributes = "Body.origin.event.messageAttributes"
+
+	PartitionKey   = "Body.origin.event.partitionKey"
+	SequenceNumber = "Body.origin.event.sequenceNumber"
+	ShardId        = "Body.origin.event.shardId"
+	ArrivalTime    = "Body.origin.event.approximateArrivalTime"
+
+	Topic           = "Body.origin.event.topic"
+	Partition       = "Body.origin.event.partition"
+	Offset          = "Body.origin.event.offset"
+	RecordTimestamp = "Body.origin.event.timestamp"
+	RecordKey       = "Body.origin.event.key"
+
+	EventName       = "Body.origin.event.eventName"
+	BucketName      = "Body.origin.event.bucketName"
+	ObjectKey       = "Body.origin.event.objectKey"
+	ObjectSize      = "Body.origin.event.objectSize"
+	ObjectVersionId = "Body.origin.event.objectVersionId"
+
+	DynamoKeys        = "Body.origin.event.keys"
+	ChangedAttributes = "Body.origin.event.changedAttributes"
+	Changes           = "Body.origin.event.changes"
+
+	DetailType  = "Body.origin.event.detailType"
+	EventDetail = "Body.origin.event.eventDetail"
+	Account     = "Body.origin.event.account"
+	Region      = "Body.origin.event.region"
+	Resources   = "Body.origin.event.resources"
+
+	MailSource      = "Body.origin.event.source"
+	RecipientHashes = "Body.origin.event.recipientHashes"
+	SpamVerdict     = "Body.origin.event.verdicts.spam"
+	VirusVerdict    = "Body.origin.event.verdicts.virus"
+	SpfVerdict      = "Body.origin.event.verdicts.spf"
+	DkimVerdict     = "Body.origin.event.verdicts.dkim"
+	DmarcVerdict    = "Body.origin.event.verdicts.dmarc"
+
+	RequestType        = "Body.origin.event.requestType"
+	ResourceType       = "Body.origin.event.resourceType"
+	LogicalResourceId  = "Body.origin.event.logicalResourceId"
+	PhysicalResourceId = "Body.origin.event.physicalResourceId"
+	StackId            = "Body.origin.event.stackId"
+	ResponseStatus     = "Body.origin.event.responseStatus"
+	ResponseReason     = "Body.origin.event.responseReason"
+
+	TriggerSource = "Body.origin.event.triggerSource"
+	UserPoolId    = "Body.origin.event.userPoolId"
+	UserNameHash  = "Body.origin.event.userNameHash"
+	ClientId      = "Body.origin.event.clientId"
+
+	ExecutionArn     = "Body.context.stepFunctions.executionArn"
+	ExecutionName    = "Body.context.stepFunctions.executionName"
+	StateMachineName = "Body.context.stepFunctions.stateMachineName"
+	StateName        = "Body.context.stepFunctions.stateName"
+	RetryCount       = "Body.context.stepFunctions.retryCount"
+
+	ClientRequestMethod   = "Body.context.client.request.method"
+	ClientRequestUrl      = "Body.context.client.request.url"
+	ClientRequestDuration = "Body.context.client.request.duration"
+	ClientRetryCount      = "Body.context.client.request.retryCount"
+	ClientResponseStatus  = "Body.context.client.response.status"
+
+	ClientAwsService   = "Body.context.client.aws.service"
+	ClientAwsOperation = "Body.context.client.aws.operation"
+	ClientAwsRegion    = "Body.context.client.aws.region"
+	ClientAwsRequestId = "Body.context.client.aws.requestId"
+	ClientAwsErrorCode = "Body.context.client.aws.errorCode"
+
+	QueryOperation        = "Body.context.client.query.operation"
+	QueryTable            = "Body.context.client.query.table"
+	QueryKey              = "Body.context.client.query.key"
+	QueryDuration         = "Body.context.client.query.duration"
+	QueryConsumedCapacity = "Body.context.client.query.consumedCapacity"
+	QueryThrottled        = "Body.context.client.query.throttled"
+
+	ClientRpcMethod = "Body.context.client.rpc.method"
+	ClientRpcStatus = "Body.context.client.rpc.status"
+
+	PublishDestination = "Body.context.publish.destination"
+	PublishMessageId   = "Body.context.publish.messageId"
+	PublishPayload     = "Body.context.publish.payload"
+	PublishDuration    = "Body.context.publish.duration"
+	PublishAttempt     = "Body.context.publish.attempt"
+
+	ConfigSources = "Body.config.sources"
+
+	FlagName        = "Body.flag.name"
+	FlagVariant     = "Body.flag.variant"
+	FlagReason      = "Body.flag.reason"
+	FlagDefaultUsed = "Body.flag.defaultUsed"
+
+	Warmup = "Body.warmup"
+
+	RpcMethod   = "Body.context.origin.rpc.method"
+	RpcStatus   = "Body.context.origin.rpc.status"
+	RpcDuration = "Body.context.origin.rpc.duration"
+
+	RequestId        = "Body.context.origin.request.id"
+	RequestMethod    = "Body.context.origin.request.method"
+	RequestRoute     = "Body.context.origin.request.route"
+	RequestSourceIp  = "Body.context.origin.request.sourceIp"
+	RequestStage     = "Body.context.origin.request.stage"
+	RequestPath      = "Body.context.origin.request.path"
+	RequestQuery     = "Body.context.origin.request.queryParams"
+	RequestUserAgent = "Body.context.origin.request.userAgent"
+	RequestBody      = "Body.context.origin.request.body"
+	TargetGroupArn   = "Body.context.origin.request.targetGroupArn"
+
+	ResponseStatusCode = "Body.context.origin.response.status"
+	ResponseSize       = "Body.context.origin.response.size"
+	ResponseBody       = "Body.context.origin.response.body"
+	ResponseDuration   = "Body.context.origin.response.duration"
 )
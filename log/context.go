@@ -0,0 +1,104 @@
+package log
+
+import (
+	"context"
+	"go.uber.org/zap"
+)
+
+type contextKey struct{}
+
+type contextLogger struct {
+	base   *zap.Logger
+	fields []zap.Field
+	logger *zap.SugaredLogger
+}
+
+func (c *contextLogger) with(keysAndValues []interface{}) *contextLogger {
+	fields := appendFields(c.fields, keysAndValues)
+	return &contextLogger{
+		base:   c.base,
+		fields: fields,
+		logger: c.base.With(fields...).Sugar(),
+	}
+}
+
+// NewContext returns a copy of ctx carrying a logger derived from FromContext(ctx) with the given fields.
+// Fields attached this way live only as long as the context, so nothing leaks into the next invocation.
+func NewContext(ctx context.Context, fields ...interface{}) context.Context {
+	mirrorFields(ctx, fields)
+	return context.WithValue(ctx, contextKey{}, loggerFromContext(ctx).with(fields))
+}
+
+// FromContext returns the logger stored by NewContext, or the package logger when ctx carries none.
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	return loggerFromContext(ctx).logger
+}
+
+func loggerFromContext(ctx context.Context) *contextLogger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey{}).(*contextLogger); ok {
+			return logger
+		}
+	}
+	current := packageState()
+	base := current.base.Desugar().WithOptions(zap.AddCallerSkip(-1))
+	return &contextLogger{
+		base:   base,
+		fields: current.invocationFields,
+		logger: base.With(current.invocationFields...).Sugar(),
+	}
+}
+
+// enrichedLogger returns FromContext(ctx) with the trace and correlation ids found in ctx when its logger has no trace id,
+// so records are correlated even on code paths that never called SetupTraceIds.
+// It skips one more caller frame, it's meant for functions logging on behalf of their caller.
+func enrichedLogger(ctx context.Context) *zap.SugaredLogger {
+	logger := loggerFromContext(ctx)
+	if ctx != nil && !logger.has(TraceId) {
+		if fields := traceIdFields(ctx); fields != nil {
+			logger = logger.with(fields)
+		}
+	}
+	return logger.logger.Desugar().WithOptions(zap.AddCallerSkip(1)).Sugar()
+}
+
+func (c *contextLogger) has(key string) bool {
+	for _, field := range c.fields {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func DebugCtx(ctx context.Context, template string, args ...interface{}) {
+	enrichedLogger(ctx).Debugf(template, args...)
+}
+
+func DebugCtxW(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	enrichedLogger(ctx).Debugw(msg, keysAndValues...)
+}
+
+func InfoCtx(ctx context.Context, template string, args ...interface{}) {
+	enrichedLogger(ctx).Infof(template, args...)
+}
+
+func InfoCtxW(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	enrichedLogger(ctx).Infow(msg, keysAndValues...)
+}
+
+func WarnCtx(ctx context.Context, template string, args ...interface{}) {
+	enrichedLogger(ctx).Warnf(template, args...)
+}
+
+func WarnCtxW(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	enrichedLogger(ctx).Warnw(msg, keysAndValues...)
+}
+
+func ErrorCtx(ctx context.Context, template string, args ...interface{}) {
+	enrichedLogger(ctx).Errorf(template, args...)
+}
+
+func ErrorCtxW(ctx context.Context, msg string, keysAndValues ...interface{}) {
+	enrichedLogger(ctx).Errorw(msg, keysAndValues...)
+}
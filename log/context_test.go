@@ -0,0 +1,55 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func TestInfoCtxW(t *testing.T) {
+	recorder := logtest.Capture(t)
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+
+	log.InfoCtxW(ctx, "Order saved", "orderId", "1")
+
+	recorder.AssertLogged(zapcore.InfoLevel, "Order saved")
+	recorder.AssertField(log.TraceId, "1-5759e988-bd862e3fe1be46a994272793")
+	recorder.AssertField(log.CorrelationId, "1-5759e988-bd862e3fe1be46a994272793")
+	recorder.AssertField("orderId", "1")
+}
+
+func TestErrorCtxWithContextFields(t *testing.T) {
+	recorder := logtest.Capture(t)
+	ctx := log.NewContext(context.Background(), "orderId", "1")
+
+	log.ErrorCtx(ctx, "Unable to save order %s", "1")
+	log.DebugCtx(nil, "No context")
+
+	entries := recorder.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "Unable to save order 1", entries[0].Message)
+	assert.Equal(t, "1", entries[0].Fields["orderId"])
+	assert.NotContains(t, entries[1].Fields, "orderId")
+	assert.NotContains(t, entries[1].Fields, log.TraceId)
+}
+
+func TestWarnCtxCaller(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)))
+
+	log.WarnCtx(context.Background(), "Slow response")
+
+	assert.Contains(t, buffer.String(), `"`+log.Caller+`":"log/context_test.go:`)
+}
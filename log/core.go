@@ -0,0 +1,84 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newCore builds the core behind the package logger, its layers from the outermost:
+//  1. the clock of the configuration, timestamping the records
+//  2. the invocation sampling, logging DEBUG for the sampled invocations
+//  3. the tail buffering, keeping the records below the level
+//  4. the namespace levels, or the log level when 2 or 3 let DEBUG through the cores below
+//  5. the wide event, counting the records passing the levels
+//  6. the deduplication, collapsing repeated records so they are not sampled again
+//  7. the sampling
+//  8. the attributes of PutAttr
+//  9. the redaction, covering every sink
+//  10. the hooks, run once the record is written
+//  11. the encoder core tee'd with the OTel bridge and the sinks
+func newCore(config Configuration, encoder zapcore.Encoder, output zapcore.WriteSyncer, logLevel zap.AtomicLevel) zapcore.Core {
+	var otelLogger OTelLogger
+	if config.otelProvider != nil {
+		otelLogger = config.otelProvider.Logger(config.application)
+	}
+	var sinks []recordSink
+	if config.syslog != nil {
+		sinks = append(sinks, newSyslogSink(*config.syslog, config.application))
+	}
+	if config.journaldSocket != "" {
+		sinks = append(sinks, newJournaldSink(config.journaldSocket, config.application))
+	}
+	var redactor *redactor
+	if len(config.redaction) > 0 {
+		redactor = newRedactor(config.redaction)
+	}
+	var enabler zapcore.LevelEnabler = logLevel
+	namespaceLevels := newNamespaceLevels(config.namespaceLevels, logLevel)
+	if namespaceLevels != nil {
+		enabler = namespaceLevels
+	}
+	levelFilter := enabler
+	relaxed := config.invocationSampling > 0 || config.tailSize > 0
+	if relaxed {
+		enabler = debugEnabler{enabler}
+	}
+	core := newSampledCore(config, enabler, func(enabler zapcore.LevelEnabler) zapcore.Core {
+		var core zapcore.Core
+		if config.core != nil {
+			core = &levelFilterCore{Core: config.core, enabler: enabler}
+		} else {
+			core = zapcore.NewCore(encoder, output, enabler)
+		}
+		if otelLogger != nil {
+			core = zapcore.NewTee(core, newOTelCore(otelLogger, enabler))
+		}
+		for _, sink := range sinks {
+			core = zapcore.NewTee(core, newSinkCore(newJSONEncoder(config), sink, enabler))
+		}
+		core = &hookCore{Core: core}
+		if redactor != nil {
+			core = newRedactingCore(core, redactor)
+		}
+		return &attrsCore{Core: core, prefix: config.customAttributesPrefix}
+	})
+	if config.deduplication > 0 {
+		core = newDedupCore(core, config.deduplication)
+	}
+	if config.wideEvents != WideEventsOff {
+		core = &wideEventCore{Core: core, mode: config.wideEvents}
+	}
+	unfiltered := core
+	if namespaceLevels != nil {
+		core = &namespaceLevelCore{Core: core, levels: namespaceLevels}
+	} else if relaxed {
+		core = &levelFilterCore{Core: core, enabler: levelFilter}
+	}
+	if config.tailSize > 0 {
+		core = &tailCore{Core: core, unfiltered: unfiltered, logLevel: logLevel, levels: namespaceLevels}
+	}
+	if config.invocationSampling > 0 {
+		core = &invocationSamplingCore{Core: core, unfiltered: unfiltered}
+	}
+	return config.withClock(core)
+}
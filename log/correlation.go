@@ -0,0 +1,136 @@
+package log
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snsTypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqsTypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"net/http"
+)
+
+const (
+	CorrelationIdHeader    = "X-Correlation-Id"
+	CorrelationIdAttribute = "CorrelationId"
+)
+
+type correlationIdKey struct{}
+
+// CorrelationSource extracts an incoming correlation id, returning an empty string when there is none.
+type CorrelationSource func() string
+
+// EnsureCorrelationId resolves the correlation id of the invocation and attaches it to both the package logger
+// and the returned context. The id is taken from ctx when already present, then from the first source returning
+// a value, then from the X-Ray trace id, and is generated when none of them provides one.
+func EnsureCorrelationId(ctx context.Context, sources ...CorrelationSource) (context.Context, string) {
+	if correlationId := CorrelationIdFromContext(ctx); correlationId != "" {
+		return ctx, correlationId
+	}
+	correlationId := resolveCorrelationId(ctx, sources)
+	withInvocationFields(CorrelationId, correlationId)
+	ctx = NewContext(ctx, CorrelationId, correlationId)
+	return context.WithValue(ctx, correlationIdKey{}, correlationId), correlationId
+}
+
+// ContextWithCorrelationId returns a copy of ctx carrying correlationId, resolved like EnsureCorrelationId when empty,
+// and a logger scoped to it. Unlike EnsureCorrelationId the package logger is not modified, so it can be used
+// by servers handling concurrent requests.
+func ContextWithCorrelationId(ctx context.Context, correlationId string) context.Context {
+	if correlationId == "" {
+		correlationId = resolveCorrelationId(ctx, nil)
+	}
+	ctx = NewContext(ctx, CorrelationId, correlationId)
+	return context.WithValue(ctx, correlationIdKey{}, correlationId)
+}
+
+func CorrelationIdFromContext(ctx context.Context) string {
+	if correlationId, ok := ctx.Value(correlationIdKey{}).(string); ok {
+		return correlationId
+	}
+	return ""
+}
+
+func resolveCorrelationId(ctx context.Context, sources []CorrelationSource) string {
+	for _, source := range sources {
+		if correlationId := source(); correlationId != "" {
+			return correlationId
+		}
+	}
+	if traceContext, ok := TraceContextFromContext(ctx); ok && traceContext.TraceId != "" {
+		return traceContext.TraceId
+	}
+	return newUUID()
+}
+
+func SqsCorrelationSource(message events.SQSMessage) CorrelationSource {
+	return func() string {
+		return sqsStringAttribute(message, CorrelationIdAttribute)
+	}
+}
+
+func SnsCorrelationSource(entity events.SNSEntity) CorrelationSource {
+	return func() string {
+		return snsStringAttribute(entity, CorrelationIdAttribute)
+	}
+}
+
+// HeadersCorrelationSource reads the X-Correlation-Id header, matching its name case-insensitively.
+func HeadersCorrelationSource(headers map[string]string) CorrelationSource {
+	return func() string {
+		return headerValue(headers, CorrelationIdHeader)
+	}
+}
+
+// headersCorrelationContext returns a copy of ctx carrying the X-Correlation-Id header of headers, if any,
+// so SetupTraceIds writes it as the CorrelationId instead of the trace id.
+func headersCorrelationContext(ctx context.Context, headers map[string]string) context.Context {
+	if correlationId := headerValue(headers, CorrelationIdHeader); correlationId != "" && CorrelationIdFromContext(ctx) == "" {
+		return context.WithValue(ctx, correlationIdKey{}, correlationId)
+	}
+	return ctx
+}
+
+func InjectCorrelationIdHeader(ctx context.Context, request *http.Request) {
+	if correlationId := CorrelationIdFromContext(ctx); correlationId != "" {
+		request.Header.Set(CorrelationIdHeader, correlationId)
+	}
+}
+
+func InjectCorrelationIdSqs(ctx context.Context, input *sqs.SendMessageInput) {
+	if correlationId := CorrelationIdFromContext(ctx); correlationId != "" {
+		if input.MessageAttributes == nil {
+			input.MessageAttributes = map[string]sqsTypes.MessageAttributeValue{}
+		}
+		input.MessageAttributes[CorrelationIdAttribute] = sqsTypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(correlationId),
+		}
+	}
+}
+
+func InjectCorrelationIdSns(ctx context.Context, input *sns.PublishInput) {
+	if correlationId := CorrelationIdFromContext(ctx); correlationId != "" {
+		if input.MessageAttributes == nil {
+			input.MessageAttributes = map[string]snsTypes.MessageAttributeValue{}
+		}
+		input.MessageAttributes[CorrelationIdAttribute] = snsTypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(correlationId),
+		}
+	}
+}
+
+// newUUID generates a random (version 4) UUID
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
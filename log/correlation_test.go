@@ -0,0 +1,72 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestEnsureCorrelationIdFromSqsAttribute(t *testing.T) {
+	initDebugLogger()
+	correlationId := "test-correlation-id"
+	message := events.SQSMessage{MessageAttributes: map[string]events.SQSMessageAttribute{
+		log.CorrelationIdAttribute: {StringValue: &correlationId, DataType: "String"},
+	}}
+
+	ctx, id := log.EnsureCorrelationId(context.Background(), log.SqsCorrelationSource(message))
+
+	assert.Equal(t, correlationId, id)
+	assert.Equal(t, correlationId, log.CorrelationIdFromContext(ctx))
+	log.ResetInvocation()
+}
+
+func TestEnsureCorrelationIdFromHeaders(t *testing.T) {
+	initDebugLogger()
+	headers := map[string]string{"x-correlation-id": "test-correlation-id"}
+
+	_, id := log.EnsureCorrelationId(context.Background(), log.HeadersCorrelationSource(headers))
+
+	assert.Equal(t, "test-correlation-id", id)
+	log.ResetInvocation()
+}
+
+func TestEnsureCorrelationIdFallsBackToTraceId(t *testing.T) {
+	initDebugLogger()
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Sampled=1;Root=TraceIdValue;Parent=ParentIdValue")
+
+	_, id := log.EnsureCorrelationId(ctx, log.HeadersCorrelationSource(nil))
+
+	assert.Equal(t, "TraceIdValue", id)
+	log.ResetInvocation()
+}
+
+func TestEnsureCorrelationIdGeneratesId(t *testing.T) {
+	initDebugLogger()
+
+	ctx, id := log.EnsureCorrelationId(context.Background())
+	sameCtx, sameId := log.EnsureCorrelationId(ctx)
+
+	assert.Len(t, id, 36)
+	assert.Equal(t, id, sameId)
+	assert.Equal(t, ctx, sameCtx)
+	log.ResetInvocation()
+}
+
+func TestInjectCorrelationId(t *testing.T) {
+	initDebugLogger()
+	ctx, id := log.EnsureCorrelationId(context.Background())
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	input := &sqs.SendMessageInput{}
+
+	log.InjectCorrelationIdHeader(ctx, request)
+	log.InjectCorrelationIdSqs(ctx, input)
+
+	assert.Equal(t, id, request.Header.Get(log.CorrelationIdHeader))
+	assert.Equal(t, id, *input.MessageAttributes[log.CorrelationIdAttribute].StringValue)
+	log.ResetInvocation()
+}
@@ -0,0 +1,60 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"strconv"
+)
+
+// DatadogProfile maps the fields to Datadog reserved attributes and converts trace and span ids to the
+// 64-bit decimal form Datadog uses to correlate logs with traces.
+func DatadogProfile(env string) Profile {
+	profile := Profile{
+		fieldNames: map[string]string{
+			Timestamp:    "timestamp",
+			Level:        "status",
+			Message:      "message",
+			StackTrace:   "error.stack",
+			Caller:       "logger.name",
+			TraceId:      "dd.trace_id",
+			SpanId:       "dd.span_id",
+			Application:  "service",
+			Version:      "version",
+			GitSha:       "git.commit.sha",
+			ErrorKind:    "error.kind",
+			ErrorMessage: "error.message",
+			ErrorStack:   "error.stack",
+		},
+		valueMappers: map[string]func(string) string{
+			TraceId: DatadogTraceId,
+			SpanId:  DatadogSpanId,
+		},
+		levelEncoder: zapcore.LowercaseLevelEncoder,
+	}
+	if env != "" {
+		profile.staticFields = []zap.Field{zap.String("env", env)}
+	}
+	return profile
+}
+
+// DatadogTraceId converts a W3C or X-Ray trace id to the decimal value of its lower 64 bits,
+// ids that cannot be converted are returned unchanged.
+func DatadogTraceId(traceId string) string {
+	hex := w3cTraceId(traceId)
+	if len(hex) != 32 {
+		return traceId
+	}
+	return hexToDecimal(hex[16:], traceId)
+}
+
+func DatadogSpanId(spanId string) string {
+	return hexToDecimal(spanId, spanId)
+}
+
+func hexToDecimal(hex, fallback string) string {
+	value, err := strconv.ParseUint(hex, 16, 64)
+	if err != nil {
+		return fallback
+	}
+	return strconv.FormatUint(value, 10)
+}
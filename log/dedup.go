@@ -0,0 +1,130 @@
+package log
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sync"
+	"time"
+)
+
+const maxDeduplicatedRecords = 1000
+
+// WithDeduplication collapses records with the same level, message and fields logged within window.
+// The first record is written right away and the repeats are counted, once the window is over, or on Flush,
+// a single record carrying the RepeatCount of the collapsed repeats is written.
+// Records above ERROR are never collapsed.
+func (c Configuration) WithDeduplication(window time.Duration) Configuration {
+	c.deduplication = window
+	return c
+}
+
+type dedupRecord struct {
+	core    zapcore.Core
+	entry   zapcore.Entry
+	fields  []zapcore.Field
+	last    time.Time
+	repeats int
+}
+
+// write writes the record carrying the number of repeats collapsed into it.
+func (r *dedupRecord) write() error {
+	entry := r.entry
+	entry.Time = r.last
+	fields := append(append([]zapcore.Field{}, r.fields...), zap.Int(RepeatCount, r.repeats))
+	return r.core.Write(entry, fields)
+}
+
+// dedupState is shared by a core and all its clones, so repeats are detected regardless of the logger writing them.
+type dedupState struct {
+	sync.Mutex
+	window  time.Duration
+	records map[string]*dedupRecord
+}
+
+// drain returns the records with repeats and forgets all of them.
+func (s *dedupState) drain() []*dedupRecord {
+	var pending []*dedupRecord
+	for _, record := range s.records {
+		if record.repeats > 0 {
+			pending = append(pending, record)
+		}
+	}
+	s.records = map[string]*dedupRecord{}
+	return pending
+}
+
+type dedupCore struct {
+	zapcore.Core
+	state   *dedupState
+	context string
+}
+
+func newDedupCore(core zapcore.Core, window time.Duration) zapcore.Core {
+	return &dedupCore{Core: core, state: &dedupState{window: window, records: map[string]*dedupRecord{}}}
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{Core: c.Core.With(fields), state: c.state, context: c.context + fingerprint(fields)}
+}
+
+func (c *dedupCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level > zapcore.ErrorLevel {
+		return c.Core.Check(entry, checked)
+	}
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *dedupCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	key := fmt.Sprintf("%s\x00%s\x00%s\x00%s", entry.Level, entry.Message, c.context, fingerprint(fields))
+
+	c.state.Lock()
+	previous := c.state.records[key]
+	if previous != nil && entry.Time.Sub(previous.entry.Time) < c.state.window {
+		previous.repeats++
+		previous.last = entry.Time
+		c.state.Unlock()
+		return nil
+	}
+	var pending []*dedupRecord
+	if previous != nil && previous.repeats > 0 {
+		pending = append(pending, previous)
+	} else if previous == nil && len(c.state.records) >= maxDeduplicatedRecords {
+		pending = c.state.drain()
+	}
+	c.state.records[key] = &dedupRecord{core: c.Core, entry: entry, fields: append([]zapcore.Field(nil), fields...), last: entry.Time}
+	c.state.Unlock()
+
+	for _, record := range pending {
+		if err := record.write(); err != nil {
+			return err
+		}
+	}
+	return c.Core.Write(entry, fields)
+}
+
+func (c *dedupCore) Sync() error {
+	c.state.Lock()
+	pending := c.state.drain()
+	c.state.Unlock()
+
+	for _, record := range pending {
+		_ = record.write()
+	}
+	return c.Core.Sync()
+}
+
+// fingerprint encodes fields into a string that doesn't depend on their order.
+func fingerprint(fields []zapcore.Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	return fmt.Sprint(encoder.Fields)
+}
@@ -0,0 +1,64 @@
+package log_test
+
+import (
+	"bytes"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeduplication(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithDeduplication(time.Minute)
+	log.Init(config)
+	for i := 0; i < 10; i++ {
+		log.InfoW("Repeated msg", "attempt", "same")
+	}
+	log.InfoW("Repeated msg", "attempt", "other")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.NotContains(t, buffer.String(), log.RepeatCount)
+
+	log.Flush()
+	lines = strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[2], `"attempt":"same"`)
+	assert.Contains(t, lines[2], `"`+log.RepeatCount+`":9`)
+}
+
+func TestDeduplicationWithRedactionAndLevelSampling(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithRedaction(log.RedactFields("password")).
+		WithLevelSampling(zapcore.DebugLevel, 1, 1000).
+		WithDeduplication(time.Minute)
+	log.Init(config)
+	for i := 0; i < 5; i++ {
+		log.WarnW("Login failed", "password", "secret")
+	}
+	log.Flush()
+
+	output := buffer.String()
+	assert.Equal(t, 2, strings.Count(output, "Login failed"))
+	assert.NotContains(t, output, "secret")
+	assert.Contains(t, output, `"`+log.RepeatCount+`":4`)
+}
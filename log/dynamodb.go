@@ -0,0 +1,57 @@
+package log
+
+import (
+	"github.com/aws/aws-lambda-go/events"
+	"sort"
+)
+
+// WithDynamoChanges makes SetUpDynamoRecord log an INFO record with the attributes changed between the old
+// and the new image, each one with its old and new value. Values go through the configured redaction,
+// where attribute names can be used as field names. Images are only available with a stream view type
+// including them, e.g. NEW_AND_OLD_IMAGES.
+func (c Configuration) WithDynamoChanges(enabled bool) Configuration {
+	c.dynamoChanges = enabled
+	return c
+}
+
+// DynamoChange holds the old and new value of a changed attribute, nil when the attribute wasn't present.
+type DynamoChange struct {
+	Old *events.DynamoDBAttributeValue `json:"old,omitempty"`
+	New *events.DynamoDBAttributeValue `json:"new,omitempty"`
+}
+
+// DynamoChanges returns the attributes whose value differs between the old and the new image of record.
+func DynamoChanges(record events.DynamoDBEventRecord) map[string]DynamoChange {
+	changes := map[string]DynamoChange{}
+	for name, old := range record.Change.OldImage {
+		old := old
+		change := DynamoChange{Old: &old}
+		if value, ok := record.Change.NewImage[name]; ok {
+			if ToString(value) == ToString(old) {
+				continue
+			}
+			change.New = &value
+		}
+		changes[name] = change
+	}
+	for name, value := range record.Change.NewImage {
+		if _, ok := record.Change.OldImage[name]; !ok {
+			value := value
+			changes[name] = DynamoChange{New: &value}
+		}
+	}
+	return changes
+}
+
+func logDynamoChanges(record events.DynamoDBEventRecord) {
+	changes := DynamoChanges(record)
+	names := make([]string, 0, len(changes))
+	for name := range changes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	InfoW("Dynamo record changed",
+		DynamoKeys, record.Change.Keys,
+		ChangedAttributes, names,
+		Changes, changes)
+}
@@ -0,0 +1,74 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func dynamoRecord() events.DynamoDBEventRecord {
+	return events.DynamoDBEventRecord{
+		EventName:   "MODIFY",
+		EventSource: "aws:dynamodb",
+		Change: events.DynamoDBStreamRecord{
+			SequenceNumber: "111",
+			Keys:           map[string]events.DynamoDBAttributeValue{"id": events.NewStringAttribute("1")},
+			OldImage: map[string]events.DynamoDBAttributeValue{
+				"id":     events.NewStringAttribute("1"),
+				"status": events.NewStringAttribute("PENDING"),
+				"email":  events.NewStringAttribute("old@example.com"),
+				"legacy": events.NewBooleanAttribute(true),
+			},
+			NewImage: map[string]events.DynamoDBAttributeValue{
+				"id":     events.NewStringAttribute("1"),
+				"status": events.NewStringAttribute("DONE"),
+				"email":  events.NewStringAttribute("new@example.com"),
+				"count":  events.NewNumberAttribute("2"),
+			},
+		},
+	}
+}
+
+func TestDynamoChanges(t *testing.T) {
+	changes := log.DynamoChanges(dynamoRecord())
+
+	assert.Len(t, changes, 4)
+	assert.NotContains(t, changes, "id")
+	assert.Equal(t, "PENDING", changes["status"].Old.String())
+	assert.Equal(t, "DONE", changes["status"].New.String())
+	assert.Nil(t, changes["legacy"].New)
+	assert.Nil(t, changes["count"].Old)
+}
+
+func TestSetUpDynamoRecordWithChanges(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"INFO",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithRedaction(log.RedactFields("email")).
+		WithDynamoChanges(true)
+	log.Init(config)
+	log.SetUpDynamoRecord(context.Background(), dynamoRecord())
+
+	output := buffer.String()
+	assert.Contains(t, output, `"Body.origin.event.changedAttributes":["count","email","legacy","status"]`)
+	assert.Contains(t, output, `"status":{"new":{"S":"DONE"},"old":{"S":"PENDING"}}`)
+	assert.Contains(t, output, `"email":"[REDACTED]"`)
+	assert.NotContains(t, output, "example.com")
+	assert.Contains(t, output, `"Body.origin.event.eventName":"MODIFY"`)
+}
+
+//doesn't assert anything because we have no method output, it's only to check if log format is valid
+func TestSetUpDynamoStream(t *testing.T) {
+	initDebugLogger()
+	log.SetUpDynamoStream(context.Background(), events.DynamoDBEvent{Records: []events.DynamoDBEventRecord{dynamoRecord()}})
+}
@@ -0,0 +1,23 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"os"
+)
+
+// newEmitLogger builds the logger behind Emit: same output as the package logger but neither filtered by level
+// nor sampled, so machine-readable records are never dropped.
+func newEmitLogger(config Configuration, output zapcore.WriteSyncer) *zap.Logger {
+	core := zapcore.NewCore(newJSONEncoder(config), output, zap.DebugLevel)
+	if config.core != nil {
+		core = config.core
+	}
+	return zap.New(config.withClock(core), zap.ErrorOutput(zapcore.Lock(os.Stderr))).With(resourceFields(config)...)
+}
+
+// Emit writes a record regardless of the log level and sampling. It is meant for records consumed by machines,
+// like CloudWatch embedded metrics, rather than for application logs.
+func Emit(msg string, fields ...zap.Field) {
+	packageState().emit.Info(msg, fields...)
+}
@@ -0,0 +1,42 @@
+package log
+
+import (
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+// set by sam local when running functions locally
+const samLocalEnv = "AWS_SAM_LOCAL"
+
+// WithDevelopment switches to a colorized console output with human readable timestamps and no sampling,
+// meant for local runs. It is enabled by default under sam local.
+func (c Configuration) WithDevelopment(enabled bool) Configuration {
+	c.development = enabled
+	return c
+}
+
+func newEncoder(config Configuration) zapcore.Encoder {
+	profile := config.outputProfile()
+	encoderConfig := profile.apply(newEncoderConfig())
+	if config.development {
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		encoderConfig.EncodeTime = developmentTimeEncoder
+		encoderConfig.EncodeDuration = zapcore.StringDurationEncoder
+		encoderConfig.EncodeLevel = withTraceLevel(encoderConfig.EncodeLevel)
+		return wrapTruncating(config, profile.wrap(zapcore.NewConsoleEncoder(encoderConfig)))
+	}
+	encoderConfig.EncodeLevel = withTraceLevel(encoderConfig.EncodeLevel)
+	return wrapTruncating(config, wrapNesting(config, profile.wrap(zapcore.NewJSONEncoder(encoderConfig))))
+}
+
+// newJSONEncoder ignores the development mode, for records that must stay machine-readable
+func newJSONEncoder(config Configuration) zapcore.Encoder {
+	profile := config.outputProfile()
+	encoderConfig := profile.apply(newEncoderConfig())
+	encoderConfig.EncodeLevel = withTraceLevel(encoderConfig.EncodeLevel)
+	return wrapTruncating(config, profile.wrap(zapcore.NewJSONEncoder(encoderConfig)))
+}
+
+func developmentTimeEncoder(t time.Time, encoder zapcore.PrimitiveArrayEncoder) {
+	encoder.AppendString(t.Format("15:04:05.000"))
+}
@@ -0,0 +1,198 @@
+package log
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/Ryanair/gofrlib/errorUtils"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	LogLevelEnv           = "LOG_LEVEL"
+	ApplicationEnv        = "APPLICATION"
+	ProjectEnv            = "PROJECT"
+	ProjectGroupEnv       = "PROJECT_GROUP"
+	VersionEnv            = "VERSION"
+	GitShaEnv             = "GIT_SHA"
+	DeploymentIdEnv       = "DEPLOYMENT_ID"
+	CustomAttrPrefixEnv   = "CUSTOM_ATTR_PREFIX"
+	OutputPathsEnv        = "LOG_OUTPUT_PATHS"
+	DevelopmentEnv        = "LOG_DEVELOPMENT"
+	ProfileEnv            = "LOG_PROFILE"
+	SamplingEnv           = "LOG_SAMPLING"
+	DeduplicationEnv      = "LOG_DEDUPLICATION"
+	MaxEntrySizeEnv       = "LOG_MAX_ENTRY_SIZE"
+	NamespaceLevelsEnv    = "LOG_NAMESPACE_LEVELS"
+	TokenizeFieldsEnv     = "LOG_TOKENIZE_FIELDS"
+	TokenizationKeyEnv    = "LOG_TOKENIZATION_KEY"
+	NestedKeysEnv         = "LOG_NESTED_KEYS"
+	InvocationSamplingEnv = "LOG_INVOCATION_SAMPLING"
+	TracePropagationEnv   = "LOG_TRACE_PROPAGATION"
+	datadogEnv            = "DD_ENV"
+)
+
+// NewConfigurationFromEnv reads the configuration from the environment:
+//
+//	LOG_LEVEL             TRACE, DEBUG, INFO, WARN or ERROR, the application log level of the function
+//	                      (AWS_LAMBDA_LOG_LEVEL) or INFO by default
+//	APPLICATION           the function name (AWS_LAMBDA_FUNCTION_NAME) by default
+//	PROJECT               empty by default
+//	PROJECT_GROUP         empty by default
+//	VERSION               the function version by default
+//	GIT_SHA               the commit the function was built from, not written by default
+//	DEPLOYMENT_ID         the id of the deployment of the function, not written by default
+//	CUSTOM_ATTR_PREFIX    empty by default
+//	LOG_OUTPUT_PATHS      comma separated output paths, stderr by default
+//	LOG_DEVELOPMENT       true for the console encoding, enabled by default under sam local
+//	LOG_PROFILE           default, ecs, datadog (using DD_ENV), gelf or lambda, lambda with the JSON log format of
+//	                      the function (AWS_LAMBDA_LOG_FORMAT) and default otherwise
+//	LOG_SAMPLING          "initial,thereafter" or "off", 100,100 by default
+//	LOG_DEDUPLICATION     deduplication window like "10s", disabled by default
+//	LOG_MAX_ENTRY_SIZE    max record size in bytes, unlimited by default
+//	LOG_NAMESPACE_LEVELS  level overrides like "repository.*=debug,client.payments=warn", see WithNamespaceLevels
+//	LOG_TOKENIZE_FIELDS   comma separated fields holding user identifiers, see TokenizeFields
+//	LOG_TOKENIZATION_KEY  base64 encoded key of LOG_TOKENIZE_FIELDS, e.g. a KMS encrypted environment variable
+//	LOG_NESTED_KEYS       true to write the dotted keys as nested json objects, see WithNestedKeys
+//	LOG_INVOCATION_SAMPLING
+//	                      fraction of the invocations logged at DEBUG like 0.01, see WithInvocationSampling
+//	LOG_TRACE_PROPAGATION comma separated trace headers read and written like "w3c,b3multi", among w3c, b3, b3multi
+//	                      and jaeger, see WithTracePropagation
+//
+// Malformed values and invalid configurations are reported together in the returned error.
+func NewConfigurationFromEnv() (Configuration, error) {
+	config := NewConfiguration(
+		envOrDefault(LogLevelEnv, envOrDefault(LambdaLogLevelEnv, "INFO")),
+		envOrDefault(ApplicationEnv, lambdacontext.FunctionName),
+		os.Getenv(ProjectEnv),
+		os.Getenv(ProjectGroupEnv),
+		os.Getenv(VersionEnv),
+		os.Getenv(CustomAttrPrefixEnv)).
+		WithGitSha(os.Getenv(GitShaEnv)).
+		WithDeploymentId(os.Getenv(DeploymentIdEnv))
+
+	var errs []error
+	if paths := os.Getenv(OutputPathsEnv); paths != "" {
+		for _, path := range strings.Split(paths, ",") {
+			config = config.WithOutputPaths(strings.TrimSpace(path))
+		}
+	}
+	if development := os.Getenv(DevelopmentEnv); development != "" {
+		enabled, err := strconv.ParseBool(development)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be a boolean, got %q", DevelopmentEnv, development))
+		}
+		config = config.WithDevelopment(enabled)
+	}
+	if nested := os.Getenv(NestedKeysEnv); nested != "" {
+		enabled, err := strconv.ParseBool(nested)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be a boolean, got %q", NestedKeysEnv, nested))
+		}
+		if enabled {
+			config = config.WithNestedKeys()
+		}
+	}
+	profile := strings.ToLower(os.Getenv(ProfileEnv))
+	if profile == "" && LambdaJSONFormat() {
+		profile = "lambda"
+	}
+	switch profile {
+	case "", "default":
+	case "ecs":
+		config = config.WithProfile(ECSProfile)
+	case "datadog":
+		config = config.WithProfile(DatadogProfile(os.Getenv(datadogEnv)))
+	case "gelf":
+		config = config.WithProfile(GELFProfile(""))
+	case "lambda":
+		config = config.WithProfile(LambdaProfile)
+	default:
+		errs = append(errs, fmt.Errorf("%s must be default, ecs, datadog, gelf or lambda, got %q", ProfileEnv, profile))
+	}
+	if sampling := os.Getenv(SamplingEnv); sampling != "" {
+		var err error
+		config, err = withEnvSampling(config, sampling)
+		errs = append(errs, err)
+	}
+	if window := os.Getenv(DeduplicationEnv); window != "" {
+		duration, err := time.ParseDuration(window)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be a duration, got %q", DeduplicationEnv, window))
+		}
+		config = config.WithDeduplication(duration)
+	}
+	if sampling := os.Getenv(InvocationSamplingEnv); sampling != "" {
+		rate, err := strconv.ParseFloat(sampling, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be a rate between 0 and 1, got %q", InvocationSamplingEnv, sampling))
+		}
+		config = config.WithInvocationSampling(rate)
+	}
+	if size := os.Getenv(MaxEntrySizeEnv); size != "" {
+		maxSize, err := strconv.Atoi(size)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be a number of bytes, got %q", MaxEntrySizeEnv, size))
+		}
+		config = config.WithMaxEntrySize(maxSize)
+	}
+
+	if namespaceLevels := os.Getenv(NamespaceLevelsEnv); namespaceLevels != "" {
+		levels, err := parseNamespaceLevels(namespaceLevels)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", NamespaceLevelsEnv, err))
+		}
+		config = config.WithNamespaceLevels(levels)
+	}
+	if propagation := os.Getenv(TracePropagationEnv); propagation != "" {
+		var propagations []TracePropagation
+		for _, name := range strings.Split(propagation, ",") {
+			parsed, err := ParseTracePropagation(name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", TracePropagationEnv, err))
+				continue
+			}
+			propagations = append(propagations, parsed)
+		}
+		config = config.WithTracePropagation(propagations...)
+	}
+	if fields := os.Getenv(TokenizeFieldsEnv); fields != "" {
+		key, err := base64.StdEncoding.DecodeString(os.Getenv(TokenizationKeyEnv))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be base64 encoded", TokenizationKeyEnv))
+		}
+		var names []string
+		for _, name := range strings.Split(fields, ",") {
+			names = append(names, strings.TrimSpace(name))
+		}
+		config = config.WithRedaction(TokenizeFields(key, names...))
+	}
+
+	errs = append(errs, config.Validate())
+	return config, errorUtils.MergeErrors(errs)
+}
+
+func withEnvSampling(config Configuration, sampling string) (Configuration, error) {
+	if strings.EqualFold(sampling, "off") {
+		return config.WithoutSampling(), nil
+	}
+	parts := strings.Split(sampling, ",")
+	if len(parts) == 2 {
+		initial, initialErr := strconv.Atoi(strings.TrimSpace(parts[0]))
+		thereafter, thereafterErr := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if initialErr == nil && thereafterErr == nil {
+			return config.WithSampling(initial, thereafter), nil
+		}
+	}
+	return config, fmt.Errorf(`%s must be "initial,thereafter" or "off", got %q`, SamplingEnv, sampling)
+}
+
+func envOrDefault(name, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,78 @@
+package log_test
+
+import (
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func setEnv(t *testing.T, values map[string]string) {
+	for name, value := range values {
+		assert.NoError(t, os.Setenv(name, value))
+	}
+	t.Cleanup(func() {
+		for name := range values {
+			_ = os.Unsetenv(name)
+		}
+	})
+}
+
+func TestNewConfigurationFromEnv(t *testing.T) {
+	setEnv(t, map[string]string{
+		log.LogLevelEnv:         "DEBUG",
+		log.ApplicationEnv:      "TEST-APPLICATION",
+		log.ProjectEnv:          "TEST-PROJECT",
+		log.ProjectGroupEnv:     "TEST-PROJECT-GROUP",
+		log.CustomAttrPrefixEnv: "testPrefix",
+		log.SamplingEnv:         "10, 100",
+		log.DeduplicationEnv:    "10s",
+		log.ProfileEnv:          "ecs",
+		log.GitShaEnv:           "3f2a9c1",
+		log.DeploymentIdEnv:     "d-42",
+	})
+	config, err := log.NewConfigurationFromEnv()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "DEBUG", config.LogLevel())
+	assert.Equal(t, "TEST-APPLICATION", config.Application())
+	assert.Equal(t, "TEST-PROJECT", config.Project())
+	assert.Equal(t, "TEST-PROJECT-GROUP", config.ProjectGroup())
+	assert.Equal(t, "testPrefix", config.CustomAttributesPrefix())
+	assert.Equal(t, "3f2a9c1", config.GitSha())
+	assert.Equal(t, "d-42", config.DeploymentId())
+	assert.NoError(t, log.InitE(config))
+}
+
+func TestNewConfigurationFromEnvErrors(t *testing.T) {
+	setEnv(t, map[string]string{
+		log.LogLevelEnv:         "VERBOSE",
+		log.ApplicationEnv:      "TEST-APPLICATION",
+		log.SamplingEnv:         "often",
+		log.DeduplicationEnv:    "ten seconds",
+		log.ProfileEnv:          "splunk",
+		log.TracePropagationEnv: "w3c,zipkin",
+	})
+	_, err := log.NewConfigurationFromEnv()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid log level "VERBOSE"`)
+	assert.Contains(t, err.Error(), `LOG_SAMPLING must be "initial,thereafter" or "off", got "often"`)
+	assert.Contains(t, err.Error(), `LOG_DEDUPLICATION must be a duration, got "ten seconds"`)
+	assert.Contains(t, err.Error(), `LOG_PROFILE must be default, ecs, datadog, gelf or lambda, got "splunk"`)
+	assert.Contains(t, err.Error(), `LOG_TRACE_PROPAGATION: unknown trace propagation "zipkin"`)
+}
+
+func TestNewConfigurationFromEnvWithLambdaLoggingControls(t *testing.T) {
+	setEnv(t, map[string]string{
+		log.ApplicationEnv:     "TEST-APPLICATION",
+		log.LambdaLogFormatEnv: "JSON",
+		log.LambdaLogLevelEnv:  "WARN",
+	})
+	config, err := log.NewConfigurationFromEnv()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "WARN", config.LogLevel())
+	assert.Equal(t, "level", config.FieldName(log.Level))
+	assert.Equal(t, "requestId", config.FieldName(log.AwsRequestId))
+}
@@ -0,0 +1,60 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// SetUpSqsRecordUnwrapped sets up the logger for a message of a queue subscribed to an SNS topic and returns
+// the payload published to the topic. Besides the SQS message id, receive count and queue, the topic,
+// SNS message id and message attributes of the envelope are attached to the invocation.
+// The body is returned as is when it isn't an SNS envelope, e.g. with raw message delivery.
+func SetUpSqsRecordUnwrapped(ctx context.Context, message events.SQSMessage) string {
+	_, payload := SetUpSqsRecordUnwrappedCtx(ctx, message)
+	return payload
+}
+
+// SetUpSqsRecordUnwrappedCtx behaves like SetUpSqsRecordUnwrapped but also returns the enriched context,
+// like SetUpSqsRecord.
+func SetUpSqsRecordUnwrappedCtx(ctx context.Context, message events.SQSMessage) (context.Context, string) {
+	ctx = SetUpSqsRecord(ctx, message)
+	keysAndValues := []interface{}{QueueArn, message.EventSourceARN}
+	envelope, ok := UnwrapSnsEnvelope(message.Body)
+	if ok {
+		keysAndValues = append(keysAndValues,
+			TopicArn, envelope.TopicArn,
+			SnsMessageId, envelope.MessageID,
+			MessageAttributes, snsAttributeValues(envelope.MessageAttributes))
+	}
+	ctx = withSetUpFields(ctx, keysAndValues...)
+	if !ok {
+		return ctx, message.Body
+	}
+	return ctx, envelope.Message
+}
+
+// UnwrapSnsEnvelope decodes body when it is the notification SNS delivers to subscribed queues.
+func UnwrapSnsEnvelope(body string) (events.SNSEntity, bool) {
+	var envelope events.SNSEntity
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return events.SNSEntity{}, false
+	}
+	if envelope.Type != "Notification" || envelope.TopicArn == "" {
+		return events.SNSEntity{}, false
+	}
+	return envelope, true
+}
+
+// snsAttributeValues keeps the values of {"Type": ..., "Value": ...} attributes.
+func snsAttributeValues(attributes map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(attributes))
+	for name, attribute := range attributes {
+		if typed, ok := attribute.(map[string]interface{}); ok {
+			values[name] = typed["Value"]
+		} else {
+			values[name] = attribute
+		}
+	}
+	return values
+}
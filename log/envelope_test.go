@@ -0,0 +1,72 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetUpSqsRecordUnwrapped(t *testing.T) {
+	recorder := logtest.Capture(t)
+	message := events.SQSMessage{
+		MessageId:      "sqs-message-1",
+		EventSource:    "aws:sqs",
+		EventSourceARN: "arn:aws:sqs:eu-west-1:123456789012:bookings",
+		Attributes:     map[string]string{"ApproximateReceiveCount": "2"},
+		Body: `{
+			"Type": "Notification",
+			"MessageId": "sns-message-1",
+			"TopicArn": "arn:aws:sns:eu-west-1:123456789012:bookings",
+			"Message": "{\"id\":\"booking-1\"}",
+			"MessageAttributes": {"CorrelationId": {"Type": "String", "Value": "correlation-1"}}
+		}`,
+	}
+	payload := log.SetUpSqsRecordUnwrapped(context.Background(), message)
+	log.Info("Processing booking")
+	log.ResetInvocation()
+
+	assert.Equal(t, `{"id":"booking-1"}`, payload)
+	recorder.AssertField(log.MessageId, "sqs-message-1")
+	recorder.AssertField(log.ReceiveCount, 2)
+	recorder.AssertField(log.QueueArn, "arn:aws:sqs:eu-west-1:123456789012:bookings")
+	recorder.AssertField(log.TopicArn, "arn:aws:sns:eu-west-1:123456789012:bookings")
+	recorder.AssertField(log.SnsMessageId, "sns-message-1")
+	recorder.AssertField(log.MessageAttributes, map[string]interface{}{"CorrelationId": "correlation-1"})
+
+	message.Body = `{"id":"booking-1"}`
+	assert.Equal(t, `{"id":"booking-1"}`, log.SetUpSqsRecordUnwrapped(context.Background(), message))
+}
+
+func TestSetUpSqsRecordUnwrappedDoesntKeepTheEnvelopeOfThePreviousRecord(t *testing.T) {
+	recorder := logtest.Capture(t)
+	defer log.ResetInvocation()
+	enveloped := events.SQSMessage{
+		MessageId: "sqs-message-1",
+		Body:      `{"Type": "Notification", "MessageId": "sns-1", "TopicArn": "arn:topic", "Message": "{}"}`,
+	}
+	plain := events.SQSMessage{MessageId: "sqs-message-2", Body: `{"id":"booking-2"}`}
+
+	ctx := log.SetUpSqs(context.Background(), events.SQSEvent{Records: []events.SQSMessage{enveloped, plain}})
+	log.PutAttr(ctx, "channel", "web")
+	log.SetUpSqsRecordUnwrappedCtx(ctx, enveloped)
+	log.Info("First record")
+	log.SetUpSqsRecordUnwrappedCtx(ctx, plain)
+	log.Info("Second record")
+
+	var second map[string]interface{}
+	for _, entry := range recorder.Entries() {
+		if entry.Message == "First record" {
+			assert.Equal(t, "arn:topic", entry.Fields[log.TopicArn])
+		}
+		if entry.Message == "Second record" {
+			second = entry.Fields
+		}
+	}
+	assert.Equal(t, "sqs-message-2", second[log.MessageId])
+	assert.NotContains(t, second, log.TopicArn)
+	assert.NotContains(t, second, log.SnsMessageId)
+	assert.Equal(t, map[string]interface{}{"channel": "web"}, log.GetAttrs(ctx))
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+)
+
+type stackTracer interface {
+	StackTrace() string
+}
+
+// ErrorErr logs msg at error level with err broken down into structured fields: the type of the root cause,
+// the full message, the message of every error in the chain and the stack trace recorded when it was wrapped
+// (see errorUtils.Wrap).
+func ErrorErr(err error, msg string, keysAndValues ...interface{}) {
+	logger().Errorw(msg, append(keysAndValues, ErrorFields(err)...)...)
+}
+
+func WarnErr(err error, msg string, keysAndValues ...interface{}) {
+	logger().Warnw(msg, append(keysAndValues, ErrorFields(err)...)...)
+}
+
+func ErrorFields(err error) []interface{} {
+	if err == nil {
+		return nil
+	}
+	var chain []string
+	var stack string
+	cause := err
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if _, categorized := e.(*categorizedError); categorized {
+			continue
+		}
+		chain = append(chain, e.Error())
+		if tracer, ok := e.(stackTracer); ok {
+			stack = tracer.StackTrace()
+		}
+		cause = e
+	}
+	fields := []interface{}{
+		ErrorKind, fmt.Sprintf("%T", cause),
+		ErrorMessage, err.Error(),
+		ErrorChain, chain,
+	}
+	if stack != "" {
+		fields = append(fields, ErrorStack, stack)
+	}
+	if category, ok := CategoryOf(err); ok {
+		fields = append(fields, ErrorCategory, string(category))
+	}
+	return fields
+}
@@ -0,0 +1,24 @@
+package log_test
+
+import (
+	"errors"
+	"github.com/Ryanair/gofrlib/errorUtils"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrorErr(t *testing.T) {
+	provider := &recordingProvider{}
+	initRedactingLogger(provider)
+	err := errorUtils.Wrap(errors.New("connection refused"), "unable to fetch order")
+
+	log.ErrorErr(err, "Processing failed", "orderId", "test-order")
+
+	attributes := provider.records[0].Attributes
+	assert.Equal(t, "*errors.errorString", attributes[log.ErrorKind])
+	assert.Equal(t, "unable to fetch order: connection refused", attributes[log.ErrorMessage])
+	assert.Equal(t, []interface{}{"unable to fetch order: connection refused", "connection refused"}, attributes[log.ErrorChain])
+	assert.Contains(t, attributes[log.ErrorStack], "TestErrorErr")
+	assert.Equal(t, "test-order", attributes["orderId"])
+}
@@ -0,0 +1,130 @@
+package log
+
+import (
+	"go.uber.org/zap/zapcore"
+	"sync"
+	"time"
+)
+
+type errorBudgetOptions struct {
+	threshold int
+	window    time.Duration
+}
+
+// WithErrorBudget counts the records at ERROR level or above, within a sliding window or, when window is 0,
+// within the current invocation, see ResetInvocation. Once threshold of them are reached ErrorBudgetExceeded
+// reports true, an "Error budget exceeded" WARN record is logged and the OnErrorBudgetExceeded functions are called,
+// e.g. for the metric of the metrics package. A threshold of 0 disables it.
+func (c Configuration) WithErrorBudget(threshold int, window time.Duration) Configuration {
+	c.errorBudget = errorBudgetOptions{threshold: threshold, window: window}
+	return c
+}
+
+// ErrorBudgetExceeded reports whether the error budget of WithErrorBudget is exhausted, so handlers can stop
+// retrying calls to a dependency that is clearly down.
+func ErrorBudgetExceeded() bool {
+	return budget.exceeded(time.Now())
+}
+
+// OnErrorBudgetExceeded adds fn to the functions called with the number of errors every time the error budget
+// gets exhausted. The functions are kept across Init.
+func OnErrorBudgetExceeded(fn func(errors int)) {
+	budget.Lock()
+	defer budget.Unlock()
+	budget.listeners = append(budget.listeners, fn)
+}
+
+var budget = &errorBudget{}
+
+type errorBudget struct {
+	sync.Mutex
+	options    errorBudgetOptions
+	errors     []time.Time
+	signaled   bool
+	listeners  []func(errors int)
+	unregister func()
+}
+
+func initErrorBudget(options errorBudgetOptions) {
+	budget.Lock()
+	defer budget.Unlock()
+	if budget.unregister != nil {
+		budget.unregister()
+		budget.unregister = nil
+	}
+	budget.options = options
+	budget.errors = nil
+	budget.signaled = false
+	if options.threshold > 0 {
+		budget.unregister = RegisterHook(func(entry zapcore.Entry, fields []zapcore.Field) error {
+			if entry.Level >= zapcore.ErrorLevel {
+				budget.record(entry.Time)
+			}
+			return nil
+		})
+	}
+}
+
+func (b *errorBudget) record(at time.Time) {
+	b.Lock()
+	b.errors = append(b.expire(at), at)
+	if len(b.errors) > b.options.threshold {
+		b.errors = b.errors[len(b.errors)-b.options.threshold:]
+	}
+	if len(b.errors) < b.options.threshold {
+		b.signaled = false
+	}
+	if len(b.errors) < b.options.threshold || b.signaled {
+		b.Unlock()
+		return
+	}
+	b.signaled = true
+	errors := len(b.errors)
+	options := b.options
+	listeners := b.listeners
+	b.Unlock()
+
+	logger().Warnw("Error budget exceeded",
+		ErrorBudgetErrors, errors,
+		ErrorBudgetThreshold, options.threshold,
+		ErrorBudgetWindow, options.window)
+	for _, listener := range listeners {
+		listener(errors)
+	}
+}
+
+func (b *errorBudget) exceeded(at time.Time) bool {
+	b.Lock()
+	defer b.Unlock()
+	if b.options.threshold == 0 {
+		return false
+	}
+	b.errors = b.expire(at)
+	if len(b.errors) < b.options.threshold {
+		b.signaled = false
+		return false
+	}
+	return true
+}
+
+// expire drops the errors out of the window, without a window they are kept until the next invocation.
+// Only the last threshold errors are kept, the older ones can't make a difference.
+func (b *errorBudget) expire(at time.Time) []time.Time {
+	if b.options.window == 0 {
+		return b.errors
+	}
+	i := 0
+	for i < len(b.errors) && at.Sub(b.errors[i]) >= b.options.window {
+		i++
+	}
+	return b.errors[i:]
+}
+
+func (b *errorBudget) resetInvocation() {
+	b.Lock()
+	defer b.Unlock()
+	if b.options.window == 0 {
+		b.errors = nil
+		b.signaled = false
+	}
+}
@@ -0,0 +1,91 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrorBudgetPerInvocation(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration(
+		"INFO",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithErrorBudget(2, 0))
+	var exhausted []int
+	log.OnErrorBudgetExceeded(func(errors int) {
+		exhausted = append(exhausted, errors)
+	})
+
+	log.Warn("Not counted")
+	log.Error("Dependency down")
+	assert.False(t, log.ErrorBudgetExceeded())
+	log.Error("Dependency down")
+	assert.True(t, log.ErrorBudgetExceeded())
+	log.Error("Dependency down")
+
+	assert.Equal(t, 1, strings.Count(buffer.String(), "Error budget exceeded"))
+	assert.Contains(t, buffer.String(), `"Body.errorBudget.errors":2`)
+	assert.Contains(t, buffer.String(), `"Body.errorBudget.threshold":2`)
+	assert.Equal(t, []int{2}, exhausted)
+
+	log.ResetInvocation()
+	assert.False(t, log.ErrorBudgetExceeded())
+}
+
+func TestErrorBudgetAcrossRecords(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithErrorBudget(2, 0))
+	defer log.ResetInvocation()
+	event := events.SQSEvent{Records: []events.SQSMessage{{MessageId: "1"}, {MessageId: "2"}}}
+
+	ctx := log.SetUpSqs(context.Background(), event)
+	for _, message := range event.Records {
+		log.FromContext(log.SetUpSqsRecord(ctx, message)).Error("Dependency down")
+	}
+
+	assert.True(t, log.ErrorBudgetExceeded())
+}
+
+func TestErrorBudgetWindow(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithoutSampling().
+		WithErrorBudget(2, 50*time.Millisecond))
+
+	log.Error("Dependency down")
+	log.ResetInvocation()
+	log.Error("Dependency down")
+	assert.True(t, log.ErrorBudgetExceeded())
+
+	time.Sleep(60 * time.Millisecond)
+	assert.False(t, log.ErrorBudgetExceeded())
+	log.Error("Dependency down")
+	log.Error("Dependency down")
+	assert.True(t, log.ErrorBudgetExceeded())
+	assert.Equal(t, 2, strings.Count(buffer.String(), "Error budget exceeded"))
+}
+
+func TestErrorBudgetDisabled(t *testing.T) {
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&bytes.Buffer{})))
+
+	log.Error("Dependency down")
+
+	assert.False(t, log.ErrorBudgetExceeded())
+	assert.Error(t, log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").WithErrorBudget(-1, 0).Validate())
+}
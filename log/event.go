@@ -2,50 +2,254 @@ package log
 
 import (
 	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/cfn"
 	"github.com/aws/aws-lambda-go/events"
+	"strings"
 )
 
-func SetUpSns(ctx context.Context, event events.SNSEvent) {
-	SetupTraceIds(ctx)
+// SetUpSns also validates the message of every record, see RegisterValidator.
+func SetUpSns(ctx context.Context, event events.SNSEvent) context.Context {
+	ctx = SetupTraceIds(ctx)
 	if IsDebugEnabled() {
 		DebugW("Got event",
 			EventSource, "sns",
-			EventBody, ToString(event))
+			LazyJSON(EventBody, event))
 	}
+	for _, record := range event.Records {
+		ValidatePayload(ctx, record.EventSource, []byte(record.SNS.Message), SnsMessageId, record.SNS.MessageID)
+	}
+	return ctx
 }
 
-func SetUpSnsRecord(ctx context.Context, event events.SNSEventRecord) {
-	SetupTraceIds(ctx)
+// SetUpSnsRecord takes the trace and correlation ids from the message attributes when present, see TraceContextFromSns.
+// The SnsMessageId, Attempt and LogicalMessageId of the message are attached, so the records of all the attempts
+// to process it can be grouped, and the message is validated, see RegisterValidator.
+func SetUpSnsRecord(ctx context.Context, event events.SNSEventRecord) context.Context {
+	ctx = setupMessageTraceIds(snsMessageContext(ctx, event.SNS))
+	ctx = withSetUpFields(ctx, snsAttemptFields(event.SNS)...)
 	if IsDebugEnabled() {
 		DebugW("Got event",
 			EventSource, event.EventSource,
-			EventBody, ToString(event))
+			LazyJSON(EventBody, event))
 	}
+	ValidatePayload(ctx, event.EventSource, []byte(event.SNS.Message), SnsMessageId, event.SNS.MessageID)
+	return ctx
 }
 
-func SetUpSqs(ctx context.Context, event events.SQSEvent) {
-	SetupTraceIds(ctx)
+// SetUpSqs also validates the body of every message, see RegisterValidator.
+func SetUpSqs(ctx context.Context, event events.SQSEvent) context.Context {
+	ctx = SetupTraceIds(ctx)
 	if IsDebugEnabled() {
 		DebugW("Got event",
 			EventSource, "sqs",
-			EventBody, ToString(event))
+			LazyJSON(EventBody, event))
+	}
+	for _, message := range event.Records {
+		ValidatePayload(ctx, message.EventSource, []byte(message.Body), MessageId, message.MessageId)
+	}
+	return ctx
+}
+
+// SetUpSqsRecord takes the trace and correlation ids from the message attributes when present, see TraceContextFromSqs.
+// The MessageId, ReceiveCount, Attempt and LogicalMessageId of the message are attached, so the records of all the
+// attempts to process it can be grouped by LogicalMessageId in CloudWatch Logs Insights, each with the AwsRequestId
+// of its invocation, and the body is validated, see RegisterValidator.
+func SetUpSqsRecord(ctx context.Context, event events.SQSMessage) context.Context {
+	ctx = setupMessageTraceIds(sqsMessageContext(ctx, event))
+	ctx = withSetUpFields(ctx, sqsAttemptFields(event)...)
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, event.EventSource,
+			LazyJSON(EventBody, event))
+	}
+	ValidatePayload(ctx, event.EventSource, []byte(event.Body), MessageId, event.MessageId)
+	return ctx
+}
+
+func SetUpDynamoStream(ctx context.Context, event events.DynamoDBEvent) context.Context {
+	ctx = SetupTraceIds(ctx)
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, "dynamodb",
+			LazyJSON(EventBody, event))
+	}
+	return ctx
+}
+
+// SetUpDynamoRecord also logs the attributes changed by the record when WithDynamoChanges is enabled.
+func SetUpDynamoRecord(ctx context.Context, event events.DynamoDBEventRecord) context.Context {
+	ctx = setupRecordTraceIds(ctx)
+	ctx = withSetUpFields(ctx,
+		EventName, event.EventName,
+		SequenceNumber, event.Change.SequenceNumber)
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, event.EventSource,
+			LazyJSON(EventBody, event))
+	}
+	if GetConfiguration().dynamoChanges {
+		logDynamoChanges(event)
+	}
+	return ctx
+}
+
+// SetUpKinesis also validates the data of every record, see RegisterValidator.
+func SetUpKinesis(ctx context.Context, event events.KinesisEvent) context.Context {
+	ctx = SetupTraceIds(ctx)
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, "kinesis",
+			LazyJSON(EventBody, event))
 	}
+	for _, record := range event.Records {
+		ValidatePayload(ctx, record.EventSource, record.Kinesis.Data, SequenceNumber, record.Kinesis.SequenceNumber)
+	}
+	return ctx
 }
 
-func SetUpSqsRecord(ctx context.Context, event events.SQSMessage) {
-	SetupTraceIds(ctx)
+// SetUpKinesisRecord also validates the data of the record, see RegisterValidator.
+func SetUpKinesisRecord(ctx context.Context, event events.KinesisEventRecord) context.Context {
+	ctx = setupRecordTraceIds(ctx)
+	ctx = withSetUpFields(ctx,
+		PartitionKey, event.Kinesis.PartitionKey,
+		SequenceNumber, event.Kinesis.SequenceNumber,
+		ShardId, kinesisShardId(event),
+		ArrivalTime, event.Kinesis.ApproximateArrivalTimestamp.UTC())
 	if IsDebugEnabled() {
 		DebugW("Got event",
 			EventSource, event.EventSource,
-			EventBody, ToString(event))
+			LazyJSON(EventBody, event))
 	}
+	ValidatePayload(ctx, event.EventSource, event.Kinesis.Data)
+	return ctx
 }
 
-func SetUpDynamoRecord(ctx context.Context, event events.DynamoDBEventRecord) {
-	SetupTraceIds(ctx)
+func SetUpFirehose(ctx context.Context, event events.KinesisFirehoseEvent) context.Context {
+	ctx = SetupTraceIds(ctx)
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, "firehose",
+			LazyJSON(EventBody, event))
+	}
+	return ctx
+}
+
+func SetUpFirehoseRecord(ctx context.Context, event events.KinesisFirehoseEventRecord) context.Context {
+	ctx = setupRecordTraceIds(ctx)
+	ctx = withSetUpFields(ctx,
+		PartitionKey, event.KinesisFirehoseRecordMetadata.PartitionKey,
+		SequenceNumber, event.KinesisFirehoseRecordMetadata.SequenceNumber,
+		ShardId, event.KinesisFirehoseRecordMetadata.ShardID,
+		ArrivalTime, event.ApproximateArrivalTimestamp.UTC())
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, "firehose",
+			LazyJSON(EventBody, event))
+	}
+	return ctx
+}
+
+// Kinesis event ids have the form "shardId-000000000000:sequenceNumber"
+func kinesisShardId(event events.KinesisEventRecord) string {
+	if i := strings.Index(event.EventID, ":"); i >= 0 {
+		return event.EventID[:i]
+	}
+	return event.EventID
+}
+
+func SetUpS3(ctx context.Context, event events.S3Event) context.Context {
+	ctx = SetupTraceIds(ctx)
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, "s3",
+			LazyJSON(EventBody, event))
+	}
+	return ctx
+}
+
+func SetUpS3Record(ctx context.Context, event events.S3EventRecord) context.Context {
+	ctx = setupRecordTraceIds(ctx)
+	ctx = withSetUpFields(ctx,
+		EventName, event.EventName,
+		BucketName, event.S3.Bucket.Name,
+		ObjectKey, s3ObjectKey(event.S3.Object),
+		ObjectSize, event.S3.Object.Size,
+		ObjectVersionId, event.S3.Object.VersionID)
 	if IsDebugEnabled() {
 		DebugW("Got event",
 			EventSource, event.EventSource,
-			EventBody, ToString(event))
+			LazyJSON(EventBody, event))
+	}
+	return ctx
+}
+
+// URLDecodedKey is only filled when the event was unmarshalled from json
+func s3ObjectKey(object events.S3Object) string {
+	if object.URLDecodedKey != "" {
+		return object.URLDecodedKey
+	}
+	return object.Key
+}
+
+// SetUp calls the SetUp* helper matching the type of event, falling back to SetupTraceIds for unknown types.
+// Like the helpers it returns a copy of ctx carrying a logger with the fields of the event, e.g. ctx = log.SetUp(ctx, record),
+// which keeps them when records are processed concurrently, unlike the package logger.
+func SetUp(ctx context.Context, event interface{}) context.Context {
+	switch e := event.(type) {
+	case events.SNSEvent:
+		return SetUpSns(ctx, e)
+	case events.SNSEventRecord:
+		return SetUpSnsRecord(ctx, e)
+	case events.SQSEvent:
+		return SetUpSqs(ctx, e)
+	case events.SQSMessage:
+		return SetUpSqsRecord(ctx, e)
+	case events.DynamoDBEvent:
+		return SetUpDynamoStream(ctx, e)
+	case events.DynamoDBEventRecord:
+		return SetUpDynamoRecord(ctx, e)
+	case events.KinesisEvent:
+		return SetUpKinesis(ctx, e)
+	case events.KinesisEventRecord:
+		return SetUpKinesisRecord(ctx, e)
+	case events.KafkaEvent:
+		return SetUpKafka(ctx, e)
+	case events.KafkaRecord:
+		return SetUpKafkaRecord(ctx, e)
+	case events.KinesisFirehoseEvent:
+		return SetUpFirehose(ctx, e)
+	case events.S3Event:
+		return SetUpS3(ctx, e)
+	case events.S3EventRecord:
+		return SetUpS3Record(ctx, e)
+	case events.SimpleEmailEvent:
+		return SetUpSes(ctx, e)
+	case events.SimpleEmailRecord:
+		return SetUpSesRecord(ctx, e)
+	case events.CloudWatchEvent:
+		return SetUpEventBridge(ctx, e)
+	case events.CognitoEventUserPoolsPreSignup:
+		return SetUpCognitoPreSignUp(ctx, e)
+	case events.CognitoEventUserPoolsPostConfirmation:
+		return SetUpCognitoPostConfirmation(ctx, e)
+	case events.CognitoEventUserPoolsPreTokenGen:
+		return SetUpCognitoPreTokenGeneration(ctx, e)
+	case events.CognitoEventUserPoolsCustomMessage:
+		return SetUpCognitoCustomMessage(ctx, e)
+	case cfn.Event:
+		return SetUpCfnCustomResource(ctx, e)
+	case StepFunctionsContext:
+		return SetUpStepFunctions(ctx, e)
+	case events.APIGatewayProxyRequest:
+		return SetUpApiGateway(ctx, e)
+	case events.APIGatewayV2HTTPRequest:
+		return SetUpApiGatewayV2(ctx, e)
+	case events.ALBTargetGroupRequest:
+		return SetUpALB(ctx, e)
+	case json.RawMessage:
+		return SetUpRaw(ctx, e)
+	default:
+		return SetupTraceIds(ctx)
 	}
 }
@@ -0,0 +1,115 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func initDebugLogger() {
+	log.Init(log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix"))
+}
+
+//doesn't assert anything because we have no method output, it's only to check if log format is valid
+func TestSetUpKinesisRecord(t *testing.T) {
+	initDebugLogger()
+	record := events.KinesisEventRecord{
+		EventID:     "shardId-000000000000:49545115243490985018280067714973144582180062593244200961",
+		EventSource: "aws:kinesis",
+		Kinesis: events.KinesisRecord{
+			ApproximateArrivalTimestamp: events.SecondsEpochTime{Time: time.Unix(1428537600, 0)},
+			PartitionKey:                "partitionKey-3",
+			SequenceNumber:              "49545115243490985018280067714973144582180062593244200961",
+		},
+	}
+	log.SetUpKinesisRecord(context.Background(), record)
+	log.Info("Info msg with kinesis record fields")
+	log.ResetInvocation()
+}
+
+func TestSetUpS3Record(t *testing.T) {
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "testPrefix"))
+	recorder := logtest.Capture(t)
+	record := events.S3EventRecord{
+		EventSource: "aws:s3",
+		EventName:   "ObjectCreated:Put",
+		S3: events.S3Entity{
+			Bucket: events.S3Bucket{Name: "bookings"},
+			Object: events.S3Object{
+				Key:           "reports/2024+01.csv",
+				URLDecodedKey: "reports/2024 01.csv",
+				Size:          1024,
+				VersionID:     "096fKKXTRTtl3on89fVO.nfljtsv6qko",
+			},
+		},
+	}
+	log.SetUpS3Record(context.Background(), record)
+	log.Info("Info msg with s3 record fields")
+	log.ResetInvocation()
+
+	entries := recorder.Entries()
+	assert.NotEmpty(t, entries)
+	fields := entries[len(entries)-1].Fields
+	assert.Equal(t, "ObjectCreated:Put", fields[log.EventName])
+	assert.Equal(t, "bookings", fields[log.BucketName])
+	assert.Equal(t, "reports/2024 01.csv", fields[log.ObjectKey])
+	assert.EqualValues(t, 1024, fields[log.ObjectSize])
+	assert.Equal(t, "096fKKXTRTtl3on89fVO.nfljtsv6qko", fields[log.ObjectVersionId])
+}
+
+func TestSetUpReturnsRecordContext(t *testing.T) {
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "testPrefix"))
+	recorder := logtest.Capture(t)
+	record := func(sequenceNumber string) events.KinesisEventRecord {
+		return events.KinesisEventRecord{
+			EventID:     "shardId-000000000000:" + sequenceNumber,
+			EventSource: "aws:kinesis",
+			Kinesis:     events.KinesisRecord{SequenceNumber: sequenceNumber},
+		}
+	}
+
+	first := log.SetUpKinesisRecord(context.Background(), record("1"))
+	second := log.SetUp(context.Background(), record("2"))
+	recorder.Reset()
+	log.InfoCtxW(first, "Info msg of the first record")
+	log.InfoCtxW(second, "Info msg of the second record")
+	log.Info("Info msg of the last record set up")
+	log.ResetInvocation()
+
+	entries := recorder.Entries()
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "1", entries[0].Fields[log.SequenceNumber])
+	assert.Equal(t, "2", entries[1].Fields[log.SequenceNumber])
+	assert.Equal(t, "2", entries[2].Fields[log.SequenceNumber])
+}
+
+func TestSetUpEventBridgeWithDetail(t *testing.T) {
+	initDebugLogger()
+	event := events.CloudWatchEvent{
+		Source:     "test.source",
+		DetailType: "TestDetailType",
+		AccountID:  "123456789012",
+		Region:     "eu-west-1",
+		Resources:  []string{"arn:aws:test:eu-west-1:123456789012:resource"},
+		Detail:     []byte(`{"id":"test-id"}`),
+	}
+	var detail struct {
+		Id string `json:"id"`
+	}
+
+	err := log.SetUpEventBridgeWithDetail(context.Background(), event, &detail)
+	log.ResetInvocation()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test-id", detail.Id)
+}
@@ -0,0 +1,50 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// SetUpEventBridge also validates the detail of the event, see RegisterValidator.
+func SetUpEventBridge(ctx context.Context, event events.CloudWatchEvent) context.Context {
+	ctx = setUpEventBridgeFields(ctx, event)
+	if IsDebugEnabled() {
+		DebugW("Got event", LazyJSON(EventBody, event))
+	}
+	ValidatePayload(ctx, event.Source, event.Detail)
+	return ctx
+}
+
+// SetUpEventBridgeWithDetail behaves like SetUpEventBridge but also unmarshals the event detail into detail,
+// so the debug dump shows the decoded payload. The unmarshal error is logged and returned to the caller.
+func SetUpEventBridgeWithDetail(ctx context.Context, event events.CloudWatchEvent, detail interface{}) error {
+	_, err := SetUpEventBridgeWithDetailCtx(ctx, event, detail)
+	return err
+}
+
+// SetUpEventBridgeWithDetailCtx behaves like SetUpEventBridgeWithDetail but also returns the enriched context,
+// like SetUpEventBridge.
+func SetUpEventBridgeWithDetailCtx(ctx context.Context, event events.CloudWatchEvent, detail interface{}) (context.Context, error) {
+	ctx = setUpEventBridgeFields(ctx, event)
+	ValidatePayload(ctx, event.Source, event.Detail)
+	if err := json.Unmarshal(event.Detail, detail); err != nil {
+		WarnW("Unable to unmarshal event detail", "error", err.Error(), LazyJSON(EventBody, event))
+		return ctx, err
+	}
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventDetail, ToString(detail),
+			LazyJSON(EventBody, event))
+	}
+	return ctx, nil
+}
+
+func setUpEventBridgeFields(ctx context.Context, event events.CloudWatchEvent) context.Context {
+	return withSetUpFields(SetupTraceIds(ctx),
+		EventSource, event.Source,
+		DetailType, event.DetailType,
+		Account, event.AccountID,
+		Region, event.Region,
+		Resources, event.Resources)
+}
@@ -0,0 +1,39 @@
+package log
+
+// FieldNames renames in the output the keys of this package, indexed by their constant, e.g. FieldNames{Timestamp: "@timestamp"}.
+type FieldNames map[string]string
+
+// WithFieldNames renames keys in the output to match the schema of a log platform, on top of the names of the profile.
+// Like with a Profile, the key constants keep their value, so hooks, redaction rules and other cores are not affected.
+func (c Configuration) WithFieldNames(names FieldNames) Configuration {
+	merged := FieldNames{}
+	for key, name := range c.fieldNames {
+		merged[key] = name
+	}
+	for key, name := range names {
+		merged[key] = name
+	}
+	c.fieldNames = merged
+	return c
+}
+
+// FieldName returns the key written to the output for one of the key constants of this package.
+func (c Configuration) FieldName(key string) string {
+	return c.outputProfile().FieldName(key)
+}
+
+// outputProfile returns the profile with the names overridden by WithFieldNames.
+func (c Configuration) outputProfile() Profile {
+	if len(c.fieldNames) == 0 {
+		return c.profile
+	}
+	profile := c.profile
+	profile.fieldNames = map[string]string{}
+	for key, name := range c.profile.fieldNames {
+		profile.fieldNames[key] = name
+	}
+	for key, name := range c.fieldNames {
+		profile.fieldNames[key] = name
+	}
+	return profile
+}
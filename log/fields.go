@@ -0,0 +1,47 @@
+package log
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go.uber.org/zap"
+)
+
+// appendFields returns a copy of fields extended with keysAndValues, which follow the SugaredLogger conventions
+// (zap.Field values or key-value pairs). A field whose key is already present replaces the existing one, so
+// re-attaching a key never produces duplicated keys in the output.
+func appendFields(fields []zap.Field, keysAndValues []interface{}) []zap.Field {
+	merged := append(make([]zap.Field, 0, len(fields)+len(keysAndValues)), fields...)
+	for i := 0; i < len(keysAndValues); i++ {
+		var field zap.Field
+		if f, ok := keysAndValues[i].(zap.Field); ok {
+			field = f
+		} else if i+1 < len(keysAndValues) {
+			field = zap.Any(fmt.Sprint(keysAndValues[i]), keysAndValues[i+1])
+			i++
+		} else {
+			continue
+		}
+		merged = replaceField(merged, field)
+	}
+	return merged
+}
+
+func replaceField(fields []zap.Field, field zap.Field) []zap.Field {
+	for i := range fields {
+		if fields[i].Key == field.Key {
+			fields[i] = field
+			return fields
+		}
+	}
+	return append(fields, field)
+}
+
+// hashValue returns the hex encoded sha256 of value, for personal data that must be correlated but never logged.
+func hashValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
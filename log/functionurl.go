@@ -0,0 +1,66 @@
+package log
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+)
+
+// FunctionURLRequest is the request of a Lambda function URL invocation, whose payload is the 2.0 version
+// of the API Gateway HTTP payload. SetUp can't tell it apart and treats it as an API Gateway request.
+type FunctionURLRequest = events.APIGatewayV2HTTPRequest
+
+// FunctionURLResponse is the response of a Lambda function URL invocation.
+type FunctionURLResponse = events.APIGatewayV2HTTPResponse
+
+// SetUpFunctionURL attaches the request id, method, path, source ip and user agent of request to the invocation,
+// and logs it with its body at DEBUG level, see BodyField. Bodies are redacted like every record.
+func SetUpFunctionURL(ctx context.Context, request FunctionURLRequest) context.Context {
+	ctx = SetupTraceIdsFromHeaders(ctx, request.Headers)
+	ctx = withSetUpFields(ctx,
+		RequestId, request.RequestContext.RequestID,
+		RequestMethod, request.RequestContext.HTTP.Method,
+		RequestPath, request.RawPath,
+		RequestSourceIp, request.RequestContext.HTTP.SourceIP,
+		RequestUserAgent, request.RequestContext.HTTP.UserAgent)
+	if IsDebugEnabled() {
+		fields := []interface{}{EventSource, "lambda-url", RequestQuery, request.QueryStringParameters}
+		if request.Body != "" {
+			fields = append(fields, BodyField(RequestBody, request.Body, request.IsBase64Encoded, headerValue(request.Headers, ContentTypeHeader)))
+		}
+		DebugW("Got request", fields...)
+	}
+	return ctx
+}
+
+// LogFunctionURLResponse logs a "Function URL response" record with the status and size of response,
+// at WARN level for server errors and INFO otherwise. The body is logged too when DEBUG is enabled, see BodyField.
+func LogFunctionURLResponse(ctx context.Context, response FunctionURLResponse) {
+	logger := enrichedLogger(ctx)
+	status := response.StatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	size := len(response.Body)
+	if response.IsBase64Encoded {
+		if decoded, err := base64.StdEncoding.DecodeString(response.Body); err == nil {
+			size = len(decoded)
+		}
+	}
+	fields := []interface{}{
+		ResponseStatusCode, status,
+		BytesField(ResponseSize, int64(size)),
+	}
+	if logger.Desugar().Core().Enabled(zapcore.DebugLevel) {
+		if response.Body != "" {
+			fields = append(fields, BodyField(ResponseBody, response.Body, response.IsBase64Encoded, headerValue(response.Headers, ContentTypeHeader)))
+		}
+	}
+	if status >= http.StatusInternalServerError {
+		logger.Warnw("Function URL response", fields...)
+		return
+	}
+	logger.Infow("Function URL response", fields...)
+}
@@ -0,0 +1,72 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func functionURLConfig(level string, buffer *bytes.Buffer) log.Configuration {
+	return log.NewConfiguration(
+		level,
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(buffer)).
+		WithRedaction(log.RedactEmails())
+}
+
+func TestSetUpFunctionURL(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(functionURLConfig("DEBUG", &buffer))
+	request := log.FunctionURLRequest{
+		RawPath:         "/tools/users",
+		Body:            base64.StdEncoding.EncodeToString([]byte(`{"email":"jane@example.com"}`)),
+		IsBase64Encoded: true,
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			RequestID: "request-id",
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method:    "POST",
+				SourceIP:  "10.0.0.1",
+				UserAgent: "curl/7.79.1",
+			},
+		},
+	}
+
+	log.SetUpFunctionURL(context.Background(), request)
+	log.LogFunctionURLResponse(context.Background(), log.FunctionURLResponse{StatusCode: 201, Body: `{"id":"1"}`})
+	log.ResetInvocation()
+
+	output := buffer.String()
+	assert.Contains(t, output, `"Body.context.origin.request.method":"POST"`)
+	assert.Contains(t, output, `"Body.context.origin.request.path":"/tools/users"`)
+	assert.Contains(t, output, `"Body.context.origin.request.userAgent":"curl/7.79.1"`)
+	assert.Contains(t, output, `"Body.context.origin.request.body":"{\"email\":\"[REDACTED]\"}"`)
+	assert.Contains(t, output, `"Body.context.origin.response.status":201`)
+	assert.Contains(t, output, `"Body.context.origin.response.size":10`)
+	assert.Contains(t, output, `"Body.context.origin.response.body":"{\"id\":\"1\"}"`)
+	assert.NotContains(t, output, "jane@example.com")
+}
+
+func TestLogFunctionURLResponseWithoutDebug(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(functionURLConfig("INFO", &buffer))
+
+	log.SetUpFunctionURL(context.Background(), log.FunctionURLRequest{Body: "request body"})
+	log.LogFunctionURLResponse(context.Background(), log.FunctionURLResponse{StatusCode: 502, Body: "upstream down"})
+	log.ResetInvocation()
+
+	output := buffer.String()
+	assert.Contains(t, output, `"SeverityText":"WARN"`)
+	assert.Contains(t, output, `"Body.context.origin.response.status":502`)
+	assert.Contains(t, output, `"Body.context.origin.response.size":13`)
+	assert.NotContains(t, output, "request body")
+	assert.NotContains(t, output, "upstream down")
+}
@@ -0,0 +1,41 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"os"
+)
+
+const gelfVersion = "1.1"
+
+// GELFProfile writes the records as GELF 1.1 messages for Graylog: the message is the short_message, the stack
+// trace the full_message, the level is the syslog severity, see WithSyslog, the timestamp is in seconds since
+// the epoch and every other field is an additional field, prefixed by an underscore.
+// host is the source of the messages, the hostname when empty.
+// GELF over TCP delimits the messages by a null byte, ship the output through a collector reading json lines.
+func GELFProfile(host string) Profile {
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	return Profile{
+		fieldNames: map[string]string{
+			Timestamp:  "timestamp",
+			Level:      "level",
+			Message:    "short_message",
+			StackTrace: "full_message",
+			Caller:     "_caller",
+			Namespace:  "_logger",
+			// the fields of the GELF payload itself keep their name
+			"version": "version",
+			"host":    "host",
+		},
+		levelEncoder: gelfLevelEncoder,
+		timeEncoder:  zapcore.EpochTimeEncoder,
+		staticFields: []zap.Field{zap.String("version", gelfVersion), zap.String("host", host)},
+		keyPrefix:    "_",
+	}
+}
+
+func gelfLevelEncoder(level zapcore.Level, encoder zapcore.PrimitiveArrayEncoder) {
+	encoder.AppendInt(syslogSeverity(level))
+}
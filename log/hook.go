@@ -0,0 +1,104 @@
+package log
+
+import (
+	"github.com/Ryanair/gofrlib/errorUtils"
+	"go.uber.org/zap/zapcore"
+	"sync"
+)
+
+// Hook is called for every record once it's written, with the fields added by With and the ones of the log call,
+// both already redacted.
+type Hook func(entry zapcore.Entry, fields []zapcore.Field) error
+
+type registeredHook struct {
+	hook Hook
+}
+
+var hooks struct {
+	sync.RWMutex
+	registered []*registeredHook
+}
+
+// RegisterHook adds hook to the records of every logger of this package, including the ones built before,
+// e.g. to count errors for a metric or forward some records to another destination. Hooks run in registration
+// order after the record is written, only for records passing the level, sampling and deduplication,
+// and their errors are reported on stderr. The returned function unregisters hook.
+func RegisterHook(hook Hook) (unregister func()) {
+	registered := &registeredHook{hook: hook}
+	hooks.Lock()
+	hooks.registered = append(hooks.registered, registered)
+	hooks.Unlock()
+	return func() {
+		hooks.Lock()
+		defer hooks.Unlock()
+		for i, h := range hooks.registered {
+			if h == registered {
+				hooks.registered = append(hooks.registered[:i:i], hooks.registered[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func registeredHooks() []*registeredHook {
+	hooks.RLock()
+	defer hooks.RUnlock()
+	return hooks.registered
+}
+
+// hookCore runs the hooks after its core writes a record, keeping track of the fields added by With.
+// Checked records get a hookRunner after the cores of its core, wrapping cores write to it directly.
+type hookCore struct {
+	zapcore.Core
+	fields []zapcore.Field
+}
+
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	return &hookCore{Core: c.Core.With(fields), fields: append(c.fields[:len(c.fields):len(c.fields)], fields...)}
+}
+
+func (c *hookCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	downstream := c.Core.Check(entry, checked)
+	if downstream != nil && len(registeredHooks()) > 0 {
+		return downstream.AddCore(entry, &hookRunner{fields: c.fields})
+	}
+	return downstream
+}
+
+func (c *hookCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(entry, fields)
+	if hookErr := (&hookRunner{fields: c.fields}).Write(entry, fields); hookErr != nil {
+		return errorUtils.MergeErrors([]error{err, hookErr})
+	}
+	return err
+}
+
+// hookRunner only runs the hooks, the record is written by the cores checked before it.
+type hookRunner struct {
+	fields []zapcore.Field
+}
+
+func (r *hookRunner) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (r *hookRunner) With(fields []zapcore.Field) zapcore.Core {
+	return &hookRunner{fields: append(r.fields[:len(r.fields):len(r.fields)], fields...)}
+}
+
+func (r *hookRunner) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checked.AddCore(entry, r)
+}
+
+func (r *hookRunner) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	all := append(r.fields[:len(r.fields):len(r.fields)], fields...)
+	var errs []error
+	for _, registered := range registeredHooks() {
+		errs = append(errs, registered.hook(entry, all))
+	}
+	return errorUtils.MergeErrors(errs)
+}
+
+func (r *hookRunner) Sync() error {
+	return nil
+}
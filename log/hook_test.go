@@ -0,0 +1,91 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+	"time"
+)
+
+func TestRegisterHook(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration(
+		"INFO",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithRedaction(log.RedactFields("password")))
+	log.With("stage", "test")
+	var entries []zapcore.Entry
+	var fields []map[string]interface{}
+	unregister := log.RegisterHook(func(entry zapcore.Entry, entryFields []zapcore.Field) error {
+		encoder := zapcore.NewMapObjectEncoder()
+		for _, field := range entryFields {
+			field.AddTo(encoder)
+		}
+		entries = append(entries, entry)
+		fields = append(fields, encoder.Fields)
+		return nil
+	})
+
+	log.Debug("Not enabled")
+	log.ErrorW("Login failed", "user", "test-user", "password", "secret")
+	log.Named("repository").Info("Saved")
+	unregister()
+	log.Info("After unregister")
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, zapcore.ErrorLevel, entries[0].Level)
+	assert.Equal(t, "Login failed", entries[0].Message)
+	assert.Equal(t, "test", fields[0]["stage"])
+	assert.Equal(t, "TEST-APPLICATION", fields[0][log.Application])
+	assert.Equal(t, "test-user", fields[0]["user"])
+	assert.Equal(t, log.Redacted, fields[0]["password"])
+	assert.Equal(t, "repository", entries[1].LoggerName)
+	assert.Contains(t, buffer.String(), "After unregister")
+}
+
+func TestRegisterHookError(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)))
+	var calls []string
+	defer log.RegisterHook(func(entry zapcore.Entry, fields []zapcore.Field) error {
+		calls = append(calls, "first")
+		return errors.New("hook failed")
+	})()
+	defer log.RegisterHook(func(entry zapcore.Entry, fields []zapcore.Field) error {
+		calls = append(calls, "second")
+		return nil
+	})()
+
+	log.Info("Info msg")
+
+	assert.Equal(t, []string{"first", "second"}, calls)
+	assert.Contains(t, buffer.String(), "Info msg")
+}
+
+func TestRegisterHookDeduplication(t *testing.T) {
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&bytes.Buffer{})).
+		WithDeduplication(time.Minute))
+	var messages []string
+	defer log.RegisterHook(func(entry zapcore.Entry, fields []zapcore.Field) error {
+		messages = append(messages, entry.Message)
+		return nil
+	})()
+
+	log.Info("Repeated")
+	log.Info("Repeated")
+	log.Info("Repeated")
+
+	assert.Equal(t, []string{"Repeated"}, messages)
+	assert.NoError(t, log.Flush())
+	assert.Equal(t, []string{"Repeated", "Repeated"}, messages)
+}
@@ -36,9 +36,37 @@ func (hi headerItem) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
 	return nil
 }
 
-func SetUpAPIRequest(ctx context.Context, request events.APIGatewayProxyRequest) {
-	SetupTraceIds(ctx)
+// allowedHeaders returns a copy of headers without the blacklisted ones, for the event dumps.
+func allowedHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	allowed := make(map[string]string, len(headers))
+	for key, value := range headers {
+		if _, exists := blackListHeader[strings.ToLower(key)]; !exists {
+			allowed[key] = value
+		}
+	}
+	return allowed
+}
+
+func allowedMultiValueHeaders(headers map[string][]string) map[string][]string {
+	if headers == nil {
+		return nil
+	}
+	allowed := make(map[string][]string, len(headers))
+	for key, values := range headers {
+		if _, exists := blackListHeader[strings.ToLower(key)]; !exists {
+			allowed[key] = values
+		}
+	}
+	return allowed
+}
+
+func SetUpAPIRequest(ctx context.Context, request events.APIGatewayProxyRequest) context.Context {
+	ctx = SetupTraceIdsFromHeaders(ctx, request.Headers)
 	ReportAPIRequest(request)
+	return ctx
 }
 
 func ReportAPIRequest(request events.APIGatewayProxyRequest) {
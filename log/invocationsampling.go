@@ -0,0 +1,85 @@
+package log
+
+import (
+	"context"
+	"go.uber.org/zap/zapcore"
+	"hash/fnv"
+	"math/rand"
+)
+
+// invocationSamplingBuckets is the resolution of the rate of WithInvocationSampling.
+const invocationSamplingBuckets = 10000
+
+// WithInvocationSampling logs a fraction rate of the invocations fully at DEBUG, e.g. 0.01 for 1%, the others at
+// the configured level. The decision is made by SetupTraceIds at the start of the invocation from the trace id,
+// so the services of a trace sharing the rate log its invocations the same way, or at random without a trace id.
+// The records of a sampled invocation hold DebugSampled true, they skip the namespace levels down to DEBUG.
+func (c Configuration) WithInvocationSampling(rate float64) Configuration {
+	c.invocationSampling = rate
+	return c
+}
+
+// debugSampled reports whether the invocation of traceContext is logged at DEBUG for rate.
+func debugSampled(rate float64, traceContext TraceContext, traced bool) bool {
+	if rate <= 0 {
+		return false
+	}
+	bucket := uint64(rand.Intn(invocationSamplingBuckets))
+	if traced && traceContext.TraceId != "" {
+		hash := fnv.New64a()
+		_, _ = hash.Write([]byte(traceContext.TraceId))
+		bucket = hash.Sum64() % invocationSamplingBuckets
+	}
+	return float64(bucket) < rate*invocationSamplingBuckets
+}
+
+// invocationSamplingFields returns DebugSampled when the invocation of ctx is logged at DEBUG.
+func invocationSamplingFields(ctx context.Context) []interface{} {
+	traceContext, traced := TraceContextFromContext(ctx)
+	if !debugSampled(packageState().config.invocationSampling, traceContext, traced) {
+		return nil
+	}
+	return []interface{}{DebugSampled, true}
+}
+
+// debugEnabler enables DEBUG on top of enabler, for the cores below an invocationSamplingCore.
+type debugEnabler struct {
+	zapcore.LevelEnabler
+}
+
+func (e debugEnabler) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.DebugLevel || e.LevelEnabler.Enabled(level)
+}
+
+// invocationSamplingCore writes the records below the configured level to unfiltered once DebugSampled is added
+// by With, its core filters the records of the other invocations by the configured level and the namespace levels.
+type invocationSamplingCore struct {
+	zapcore.Core
+	unfiltered zapcore.Core
+	sampled    bool
+}
+
+func (c *invocationSamplingCore) Enabled(level zapcore.Level) bool {
+	return c.sampled && level >= zapcore.DebugLevel || c.Core.Enabled(level)
+}
+
+func (c *invocationSamplingCore) namespaceEnabled(name string, level zapcore.Level) bool {
+	return c.sampled && level >= zapcore.DebugLevel || namespaceEnabled(c.Core, name, level)
+}
+
+func (c *invocationSamplingCore) With(fields []zapcore.Field) zapcore.Core {
+	sampled := c.sampled
+	for _, field := range fields {
+		if field.Key == DebugSampled && field.Type == zapcore.BoolType {
+			sampled = field.Integer == 1
+		}
+	}
+	return &invocationSamplingCore{Core: c.Core.With(fields), unfiltered: c.unfiltered.With(fields), sampled: sampled}
+}
+
+func (c *invocationSamplingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.sampled && entry.Level >= zapcore.DebugLevel {
+		return c.unfiltered.Check(entry, checked)
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -0,0 +1,68 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+)
+
+func TestInvocationSampling(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithInvocationSampling(1).
+		WithNamespaceLevels(map[string]string{"repository": "warn"}).
+		WithWriters(zapcore.AddSync(&buffer)))
+	defer log.ResetInvocation()
+	ctx := log.ContextWithTraceContext(context.Background(), log.TraceContext{TraceId: "4bf92f3577b34da6a3ce929d0e0e4736"})
+
+	ctx = log.SetupTraceIds(ctx)
+	log.Debug("Package debug record")
+	log.FromContext(ctx).Debug("Context debug record")
+	log.FromContext(ctx).Named("repository").Info("Repository info record")
+	log.Tracef("Trace record")
+	log.ResetInvocation()
+	log.Debug("Debug record after the invocation")
+
+	output := buffer.String()
+	assert.Contains(t, output, `"Body.message":"Package debug record"`)
+	assert.Contains(t, output, `"Body.message":"Context debug record"`)
+	assert.Contains(t, output, `"Body.message":"Repository info record"`)
+	assert.Contains(t, output, `"`+log.DebugSampled+`":true`)
+	assert.NotContains(t, output, "Trace record")
+	assert.NotContains(t, output, "after the invocation")
+}
+
+func TestInvocationSamplingIsSeededByTraceId(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithInvocationSampling(0.5).
+		WithWriters(zapcore.AddSync(&buffer)))
+	defer log.ResetInvocation()
+
+	traceIds := []string{"4bf92f3577b34da6a3ce929d0e0e4736", "1-5759e988-bd862e3fe1be46a994272793"}
+	sampled := map[string]int{}
+	for _, traceId := range traceIds {
+		for i := 0; i < 10; i++ {
+			buffer.Reset()
+			log.SetupTraceIds(log.ContextWithTraceContext(context.Background(), log.TraceContext{TraceId: traceId}))
+			log.Debug("Debug record")
+			if strings.Contains(buffer.String(), "Debug record") {
+				sampled[traceId]++
+			}
+		}
+	}
+
+	for _, traceId := range traceIds {
+		assert.Contains(t, []int{0, 10}, sampled[traceId], traceId)
+	}
+}
+
+func TestInvocationSamplingRateIsValidated(t *testing.T) {
+	err := log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").WithInvocationSampling(2).Validate()
+
+	assert.EqualError(t, err, "invalid log configuration:\ninvocation sampling rate must be between 0 and 1, got 2")
+}
@@ -0,0 +1,88 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultJournaldSocket is the socket of the native protocol of systemd-journald.
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+// WithJournald sends every record, besides writing it to the regular output, to systemd-journald through its native
+// protocol at socket, DefaultJournaldSocket when empty. The json record is the MESSAGE of the journal entry,
+// its PRIORITY is the syslog severity of the level, see WithSyslog, and SYSLOG_IDENTIFIER is the application.
+// Records larger than a datagram of the socket are dropped.
+func (c Configuration) WithJournald(socket string) Configuration {
+	if socket == "" {
+		socket = DefaultJournaldSocket
+	}
+	c.journaldSocket = socket
+	return c
+}
+
+type journaldSink struct {
+	sync.Mutex
+	socket     string
+	identifier string
+	conn       net.Conn
+}
+
+func newJournaldSink(socket, application string) *journaldSink {
+	return &journaldSink{socket: socket, identifier: application}
+}
+
+func (s *journaldSink) writeRecord(entry zapcore.Entry, record []byte) error {
+	var message bytes.Buffer
+	appendJournalField(&message, "MESSAGE", record)
+	appendJournalField(&message, "PRIORITY", []byte(strconv.Itoa(syslogSeverity(entry.Level))))
+	appendJournalField(&message, "SYSLOG_IDENTIFIER", []byte(s.identifier))
+	appendJournalField(&message, "SYSLOG_PID", []byte(strconv.Itoa(os.Getpid())))
+	if entry.Caller.Defined {
+		appendJournalField(&message, "CODE_FILE", []byte(entry.Caller.File))
+		appendJournalField(&message, "CODE_LINE", []byte(strconv.Itoa(entry.Caller.Line)))
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.conn == nil {
+		conn, err := net.DialTimeout("unixgram", s.socket, sinkWriteTimeout)
+		if err != nil {
+			return fmt.Errorf("unable to connect to journald: %v", err)
+		}
+		s.conn = conn
+	}
+	_ = s.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+	if _, err := s.conn.Write(message.Bytes()); err != nil {
+		// the socket is reopened on the next record, journald may have been restarted
+		_ = s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("unable to write to journald: %v", err)
+	}
+	return nil
+}
+
+func (s *journaldSink) sync() error {
+	return nil
+}
+
+// appendJournalField appends a field in the native journal format, values with new lines are length prefixed.
+func appendJournalField(message *bytes.Buffer, name string, value []byte) {
+	if bytes.IndexByte(value, '\n') < 0 {
+		message.WriteString(name)
+		message.WriteByte('=')
+		message.Write(value)
+		message.WriteByte('\n')
+		return
+	}
+	message.WriteString(name)
+	message.WriteByte('\n')
+	_ = binary.Write(message, binary.LittleEndian, uint64(len(value)))
+	message.Write(value)
+	message.WriteByte('\n')
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/aws/aws-lambda-go/events"
+	"unicode/utf8"
+)
+
+func SetUpKafka(ctx context.Context, event events.KafkaEvent) context.Context {
+	ctx = SetupTraceIds(ctx)
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, event.EventSource,
+			LazyJSON(EventBody, event))
+	}
+	return ctx
+}
+
+// SetUpKafkaRecord attaches the topic, partition, offset, timestamp and key of the record to the invocation,
+// taking the trace ids and the baggage from its headers when the record has them, see SetupTraceIdsFromHeaders.
+func SetUpKafkaRecord(ctx context.Context, record events.KafkaRecord) context.Context {
+	ctx = setupRecordTraceIds(headersContext(ctx, KafkaHeaders(record)))
+	ctx = withSetUpFields(ctx,
+		Topic, record.Topic,
+		Partition, record.Partition,
+		Offset, record.Offset,
+		RecordTimestamp, record.Timestamp.UTC(),
+		RecordKey, KafkaRecordKey(record))
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, "kafka",
+			LazyJSON(EventBody, record))
+	}
+	return ctx
+}
+
+// KafkaRecordKey decodes the base64 key of record, keeping it encoded when it isn't text.
+func KafkaRecordKey(record events.KafkaRecord) string {
+	key, err := base64.StdEncoding.DecodeString(record.Key)
+	if err != nil || !utf8.Valid(key) {
+		return record.Key
+	}
+	return string(key)
+}
+
+// KafkaHeaders flattens the headers of record, the last value wins when a header is repeated.
+func KafkaHeaders(record events.KafkaRecord) map[string]string {
+	headers := map[string]string{}
+	for _, header := range record.Headers {
+		for key, value := range header {
+			headers[key] = string(value)
+		}
+	}
+	return headers
+}
+
+// KafkaCorrelationSource reads the X-Correlation-Id or CorrelationId header of record.
+func KafkaCorrelationSource(record events.KafkaRecord) CorrelationSource {
+	return func() string {
+		headers := KafkaHeaders(record)
+		if correlationId := headerValue(headers, CorrelationIdHeader); correlationId != "" {
+			return correlationId
+		}
+		return headerValue(headers, CorrelationIdAttribute)
+	}
+}
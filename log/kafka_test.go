@@ -0,0 +1,53 @@
+package log_test
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func kafkaRecord() events.KafkaRecord {
+	return events.KafkaRecord{
+		Topic:     "bookings",
+		Partition: 3,
+		Offset:    42,
+		Timestamp: events.MilliSecondsEpochTime{Time: time.Unix(1600000000, 0)},
+		Key:       base64.StdEncoding.EncodeToString([]byte("booking-1")),
+		Value:     base64.StdEncoding.EncodeToString([]byte(`{"id":"booking-1"}`)),
+		Headers: []map[string][]byte{
+			{"traceparent": []byte("00-5759e988bd862e3fe1be46a994272793-53995c3f42cd8ad8-01")},
+			{"x-correlation-id": []byte("correlation-1")},
+		},
+	}
+}
+
+func TestSetUpKafkaRecord(t *testing.T) {
+	recorder := logtest.Capture(t)
+	record := kafkaRecord()
+	log.SetUp(context.Background(), record)
+	ctx, correlationId := log.EnsureCorrelationId(context.Background(), log.KafkaCorrelationSource(record))
+	log.FromContext(ctx).Info("Processing record")
+	log.ResetInvocation()
+
+	assert.Equal(t, "correlation-1", correlationId)
+	recorder.AssertField(log.Topic, "bookings")
+	recorder.AssertField(log.Partition, 3)
+	recorder.AssertField(log.Offset, 42)
+	recorder.AssertField(log.RecordKey, "booking-1")
+	recorder.AssertField(log.TraceId, "5759e988bd862e3fe1be46a994272793")
+	recorder.AssertField(log.CorrelationId, "correlation-1")
+}
+
+//doesn't assert anything because we have no method output, it's only to check if log format is valid
+func TestSetUpKafka(t *testing.T) {
+	initDebugLogger()
+	log.SetUpKafka(context.Background(), events.KafkaEvent{
+		EventSource: "aws:kafka",
+		Records:     map[string][]events.KafkaRecord{"bookings-3": {kafkaRecord()}},
+	})
+}
@@ -0,0 +1,32 @@
+package log
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"time"
+)
+
+// lambdaContextFields returns the fields describing the invocation carried by ctx, nothing when ctx wasn't created by the Lambda runtime,
+// and whether the invocation is seen for the first time and is the cold start one.
+func lambdaContextFields(ctx context.Context) ([]interface{}, bool) {
+	lambdaContext, ok := lambdacontext.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	cold, first := coldStart.observe(lambdaContext.AwsRequestID)
+	fields := []interface{}{
+		AwsRequestId, lambdaContext.AwsRequestID,
+		FunctionArn, lambdaContext.InvokedFunctionArn,
+		IsColdStart, cold,
+	}
+	if lambdacontext.FunctionVersion != "" {
+		fields = append(fields, FunctionVersion, lambdacontext.FunctionVersion)
+	}
+	if lambdacontext.MemoryLimitInMB > 0 {
+		fields = append(fields, FunctionMemory, lambdacontext.MemoryLimitInMB)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		fields = append(fields, RemainingTime, time.Until(deadline))
+	}
+	return fields, first
+}
@@ -0,0 +1,49 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSetUpWithLambdaContext(t *testing.T) {
+	recorder := logtest.Capture(t)
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{
+		AwsRequestID:       "test-request-id",
+		InvokedFunctionArn: "arn:aws:lambda:eu-west-1:123456789012:function:test-function",
+	})
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	log.SetUp(ctx, events.SQSEvent{})
+	defer log.ResetInvocation()
+	log.Info("Info msg with lambda context")
+
+	entries := recorder.Entries()
+	entry := entries[len(entries)-1]
+	assert.Equal(t, "test-request-id", entry.Fields[log.AwsRequestId])
+	assert.Equal(t, "arn:aws:lambda:eu-west-1:123456789012:function:test-function", entry.Fields[log.FunctionArn])
+	remaining, ok := entry.Fields[log.RemainingTime].(time.Duration)
+	assert.True(t, ok)
+	assert.True(t, remaining > 0 && remaining <= time.Minute)
+
+	recorder.Reset()
+	ctx = log.SetupTraceIds(ctx)
+	log.FromContext(ctx).Info("Info msg from context logger")
+	recorder.AssertField(log.AwsRequestId, "test-request-id")
+}
+
+func TestSetUpWithoutLambdaContext(t *testing.T) {
+	recorder := logtest.Capture(t)
+	log.SetUp(context.Background(), events.SQSEvent{})
+	defer log.ResetInvocation()
+	log.Info("Info msg without lambda context")
+
+	entries := recorder.Entries()
+	assert.NotContains(t, entries[len(entries)-1].Fields, log.AwsRequestId)
+}
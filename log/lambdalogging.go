@@ -0,0 +1,52 @@
+package log
+
+import (
+	"go.uber.org/zap/zapcore"
+	"os"
+	"time"
+)
+
+// Environment of the advanced logging controls of Lambda.
+const (
+	LambdaLogFormatEnv = "AWS_LAMBDA_LOG_FORMAT"
+	LambdaLogLevelEnv  = "AWS_LAMBDA_LOG_LEVEL"
+
+	lambdaJSONFormat = "JSON"
+)
+
+// LambdaProfile writes the timestamp, level, message and request id under the keys the JSON log format of Lambda
+// expects, so the application log level of the function filters the records of this package and they are shown
+// like the native ones. The levels are among TRACE, DEBUG, INFO, WARN, ERROR and FATAL, the only ones Lambda knows:
+// DPANIC records are ERROR and PANIC ones FATAL.
+// NewConfigurationFromEnv selects it when the function has the JSON log format, see LambdaLogFormatEnv.
+var LambdaProfile = Profile{
+	fieldNames: map[string]string{
+		Timestamp:    "timestamp",
+		Level:        "level",
+		Message:      "message",
+		AwsRequestId: "requestId",
+	},
+	levelEncoder: lambdaLevelEncoder,
+	timeEncoder:  lambdaTimeEncoder,
+}
+
+// LambdaJSONFormat reports whether the function has the JSON log format of the advanced logging controls.
+func LambdaJSONFormat() bool {
+	return os.Getenv(LambdaLogFormatEnv) == lambdaJSONFormat
+}
+
+func lambdaLevelEncoder(level zapcore.Level, encoder zapcore.PrimitiveArrayEncoder) {
+	switch level {
+	case zapcore.DPanicLevel:
+		encoder.AppendString("ERROR")
+	case zapcore.PanicLevel, zapcore.FatalLevel:
+		encoder.AppendString("FATAL")
+	default:
+		encoder.AppendString(levelString(level))
+	}
+}
+
+// Lambda parses RFC3339 timestamps, the ISO8601 ones of zap have no colon in the offset
+func lambdaTimeEncoder(t time.Time, encoder zapcore.PrimitiveArrayEncoder) {
+	encoder.AppendString(t.UTC().Format("2006-01-02T15:04:05.000Z07:00"))
+}
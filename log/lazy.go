@@ -0,0 +1,31 @@
+package log
+
+import (
+	"go.uber.org/zap"
+)
+
+// DebugLazy logs msg with the fields returned by fn, which is only called when the record is actually written,
+// so expensive serializations aren't paid for when DEBUG is disabled or the record is sampled out.
+func DebugLazy(msg string, fn func() []interface{}) {
+	if checked := logger().Desugar().Check(zap.DebugLevel, msg); checked != nil {
+		checked.Write(appendFields(nil, fn())...)
+	}
+}
+
+type stringerFunc func() string
+
+func (f stringerFunc) String() string {
+	return f()
+}
+
+// LazyString returns a field whose value is computed by fn when the record is encoded.
+func LazyString(key string, fn func() string) zap.Field {
+	return zap.Stringer(key, stringerFunc(fn))
+}
+
+// LazyJSON returns a field holding the json of value, see ToString, computed when the record is encoded.
+func LazyJSON(key string, value interface{}) zap.Field {
+	return LazyString(key, func() string {
+		return ToString(value)
+	})
+}
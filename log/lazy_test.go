@@ -0,0 +1,41 @@
+package log_test
+
+import (
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func TestDebugLazy(t *testing.T) {
+	recorder := logtest.Capture(t)
+	calls := 0
+	fields := func() []interface{} {
+		calls++
+		return []interface{}{"orderId", "1"}
+	}
+
+	log.DebugLazy("Debug msg with lazy fields", fields)
+	recorder.AssertLogged(zapcore.DebugLevel, "Debug msg with lazy fields")
+	recorder.AssertField("orderId", "1")
+
+	log.SetLevel("INFO")
+	log.DebugLazy("Debug msg not logged", fields)
+	assert.Equal(t, 1, calls)
+}
+
+func TestLazyJSON(t *testing.T) {
+	recorder := logtest.Capture(t)
+	evaluated := false
+	log.SetLevel("INFO")
+
+	log.DebugW("Debug msg not logged", log.LazyString("lazy", func() string {
+		evaluated = true
+		return "value"
+	}))
+	assert.False(t, evaluated)
+
+	log.InfoW("Info msg with lazy json", log.LazyJSON("order", map[string]string{"id": "1"}))
+	recorder.AssertField("order", `{"id":"1"}`)
+}
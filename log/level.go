@@ -0,0 +1,64 @@
+package log
+
+import (
+	"net/http"
+	"os"
+	"time"
+)
+
+// SetLevel changes the level of the logger built by Init without rebuilding it.
+func SetLevel(level string) error {
+	l, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	packageState().level.SetLevel(l)
+	return nil
+}
+
+func GetLevel() string {
+	return levelString(packageState().level.Level())
+}
+
+// LevelHandler serves the current level on GET and changes it on PUT with a {"level":"debug"} body.
+// It follows the logger of the last Init.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		packageState().level.ServeHTTP(w, r)
+	})
+}
+
+// WatchLevel polls source every interval and applies the returned level when it changes.
+// Empty values are ignored. The returned function stops the watcher.
+func WatchLevel(interval time.Duration, source func() string) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		last := ""
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				level := source()
+				if level == "" || level == last {
+					continue
+				}
+				last = level
+				if err := SetLevel(level); err != nil {
+					Warn("malformed log level: %+v", level)
+				}
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+func EnvLevelSource(name string) func() string {
+	return func() string {
+		return os.Getenv(name)
+	}
+}
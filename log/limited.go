@@ -0,0 +1,181 @@
+package log
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"hash"
+	"io"
+	"reflect"
+	"sort"
+	"unicode/utf8"
+)
+
+// ToStringLimited behaves like ToString but keeps only the first maxBytes of the json. A longer json is cut at a rune
+// boundary and followed by "...(<size> bytes, sha256:<hash>)", the size and hash of the whole json, so the payload
+// can be matched later with the one stored elsewhere. The json is hashed while it's encoded and only its first maxBytes
+// are kept. Slices, arrays and maps with string keys are encoded element by element, so only the json of one element
+// is held in memory at a time, e.g. for a batch of records; other values are marshalled whole before being cut.
+func ToStringLimited(value interface{}, maxBytes int) string {
+	writer := newLimitedWriter(maxBytes)
+	if err := encodeIncrementally(writer, reflect.ValueOf(value)); err != nil {
+		writer = newLimitedWriter(maxBytes)
+		_, _ = writer.Write([]byte(fmt.Sprintf("%+v\n", value)))
+		return writer.String()
+	}
+	_, _ = writer.Write([]byte("\n"))
+	return writer.String()
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// encodeIncrementally writes the json of value to writer like json.Marshal, walking the slices, arrays and maps
+// with string keys to marshal their elements one at a time.
+func encodeIncrementally(writer io.Writer, value reflect.Value) error {
+	for value.IsValid() && (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && !value.IsNil() && !marshalsItself(value.Type()) {
+		value = value.Elem()
+	}
+	if !value.IsValid() || marshalsItself(value.Type()) {
+		return marshalTo(writer, value)
+	}
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && (value.IsNil() || value.Type().Elem().Kind() == reflect.Uint8) {
+			return marshalTo(writer, value)
+		}
+		if _, err := writer.Write([]byte("[")); err != nil {
+			return err
+		}
+		for i := 0; i < value.Len(); i++ {
+			if i > 0 {
+				if _, err := writer.Write([]byte(",")); err != nil {
+					return err
+				}
+			}
+			if err := encodeIncrementally(writer, value.Index(i)); err != nil {
+				return err
+			}
+		}
+		_, err := writer.Write([]byte("]"))
+		return err
+	case reflect.Map:
+		if value.IsNil() || value.Type().Key().Kind() != reflect.String {
+			return marshalTo(writer, value)
+		}
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		if _, err := writer.Write([]byte("{")); err != nil {
+			return err
+		}
+		for i, key := range keys {
+			if i > 0 {
+				if _, err := writer.Write([]byte(",")); err != nil {
+					return err
+				}
+			}
+			if err := marshalTo(writer, reflect.ValueOf(key.String())); err != nil {
+				return err
+			}
+			if _, err := writer.Write([]byte(":")); err != nil {
+				return err
+			}
+			if err := encodeIncrementally(writer, value.MapIndex(key)); err != nil {
+				return err
+			}
+		}
+		_, err := writer.Write([]byte("}"))
+		return err
+	default:
+		return marshalTo(writer, value)
+	}
+}
+
+// marshalsItself reports whether the json of t, or of *t, is up to a json.Marshaler or an encoding.TextMarshaler.
+func marshalsItself(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		reflect.PtrTo(t).Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)
+}
+
+func marshalTo(writer io.Writer, value reflect.Value) error {
+	var marshalled []byte
+	var err error
+	switch {
+	case value.CanAddr():
+		// like json.Marshal, the methods of the pointer are used for the elements of the slices
+		marshalled, err = json.Marshal(value.Addr().Interface())
+	case value.IsValid():
+		marshalled, err = json.Marshal(value.Interface())
+	default:
+		marshalled, err = json.Marshal(nil)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(marshalled)
+	return err
+}
+
+// LimitedJSON returns a field holding ToStringLimited(value, maxBytes), computed when the record is encoded.
+func LimitedJSON(key string, value interface{}, maxBytes int) zap.Field {
+	return LazyString(key, func() string {
+		return ToStringLimited(value, maxBytes)
+	})
+}
+
+// limitedWriter keeps the first max bytes written to it and hashes all of them but the last one,
+// the trailing new line written after every value.
+type limitedWriter struct {
+	max    int
+	prefix []byte
+	hash   hash.Hash
+	size   int
+	last   byte
+	held   bool
+}
+
+func newLimitedWriter(max int) *limitedWriter {
+	if max < 0 {
+		max = 0
+	}
+	return &limitedWriter{max: max, hash: sha256.New()}
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if w.held {
+		w.consume([]byte{w.last})
+	}
+	w.consume(p[:len(p)-1])
+	w.last, w.held = p[len(p)-1], true
+	return len(p), nil
+}
+
+func (w *limitedWriter) consume(p []byte) {
+	_, _ = w.hash.Write(p)
+	w.size += len(p)
+	if room := w.max + 1 - len(w.prefix); room > 0 {
+		if len(p) > room {
+			p = p[:room]
+		}
+		w.prefix = append(w.prefix, p...)
+	}
+}
+
+func (w *limitedWriter) String() string {
+	if w.size <= w.max {
+		return string(w.prefix)
+	}
+	limit := w.max
+	for limit > 0 && !utf8.RuneStart(w.prefix[limit]) {
+		limit--
+	}
+	return fmt.Sprintf("%s...(%d bytes, sha256:%s)", w.prefix[:limit], w.size, hex.EncodeToString(w.hash.Sum(nil)))
+}
@@ -0,0 +1,60 @@
+package log_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestToStringLimited(t *testing.T) {
+	value := map[string]string{"data": strings.Repeat("é", 100)}
+	full := log.ToString(value)
+	hash := sha256.Sum256([]byte(full))
+
+	assert.Equal(t, full, log.ToStringLimited(value, len(full)))
+	limited := log.ToStringLimited(value, 20)
+	assert.Equal(t, fmt.Sprintf(`{"data":"ééééé...(%d bytes, sha256:%s)`, len(full), hex.EncodeToString(hash[:])), limited)
+	assert.Equal(t, limited, log.ToStringLimited(value, 20))
+}
+
+type pointerMarshaler struct {
+	id int
+}
+
+func (m *pointerMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"id-%d"`, m.id)), nil
+}
+
+func TestToStringLimitedEncodesLikeToString(t *testing.T) {
+	type record struct {
+		Id    string            `json:"id"`
+		Tags  []string          `json:"tags,omitempty"`
+		Attrs map[string]string `json:"attrs"`
+	}
+	var nilSlice []record
+	for _, value := range []interface{}{
+		[]record{{Id: "<1>", Tags: []string{"a"}}, {Id: "2", Attrs: map[string]string{"b": "&", "a": "é"}}},
+		map[string][]int{"z": {1, 2}, "a": nil, "m": {}},
+		[2]interface{}{nil, []byte("data")},
+		&[]pointerMarshaler{{1}, {2}},
+		[]*pointerMarshaler{{3}, nil},
+		nilSlice,
+		map[int]string{2: "b", 1: "a"},
+	} {
+		assert.Equal(t, log.ToString(value), log.ToStringLimited(value, 1000))
+	}
+
+	records := []record{{Id: "1"}, {Id: strings.Repeat("2", 100)}}
+	full := log.ToString(records)
+	hash := sha256.Sum256([]byte(full))
+	assert.Equal(t, fmt.Sprintf(`[{"id":"1","att...(%d bytes, sha256:%s)`, len(full), hex.EncodeToString(hash[:])), log.ToStringLimited(records, 15))
+}
+
+func TestToStringLimitedNotMarshalable(t *testing.T) {
+	assert.Equal(t, log.ToString(struct{ Fn func() }{}), log.ToStringLimited(struct{ Fn func() }{}, 100))
+	assert.True(t, strings.HasPrefix(log.ToStringLimited(struct{ Fn func() }{}, 4), "{Fn:...("))
+}
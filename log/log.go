@@ -5,21 +5,57 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/aws/aws-lambda-go/lambdacontext"
-	"github.com/aws/aws-xray-sdk-go/header"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"os"
+	"sync/atomic"
+	"time"
 )
 
-var log *zap.SugaredLogger
-var logConfig Configuration
-
 type Configuration struct {
 	logLevel               string
 	application            string
 	project                string
 	projectGroup           string
 	version                string
+	gitSha                 string
+	deploymentId           string
 	customAttributesPrefix string
+	otelProvider           OTelLoggerProvider
+	redaction              []RedactionRule
+	outputPaths            []string
+	writers                []zapcore.WriteSyncer
+	development            bool
+	profile                Profile
+	sampling               samplingOptions
+	deduplication          time.Duration
+	maxEntrySize           int
+	core                   zapcore.Core
+	dynamoChanges          bool
+	timeoutWarning         float64
+	buffering              bufferOptions
+	fieldNames             FieldNames
+	tenantAllowlist        map[string]bool
+	audit                  auditOptions
+	namespaceLevels        map[string]string
+	errorBudget            errorBudgetOptions
+	caller                 callerOptions
+	stacktrace             stacktraceOptions
+	runtimeStats           runtimeStatsOptions
+	schema                 Schema
+	clock                  Clock
+	rotatingFiles          []rotatingFileOptions
+	syslog                 *SyslogOptions
+	journaldSocket         string
+	wideEvents             WideEventMode
+	nestedKeys             bool
+	invocationSampling     float64
+	tailSize               int
+	xrayAnnotations        map[string]bool
+	xrayMetadata           map[string]bool
+	tracing                TracingProvider
+	tracePropagation       []TracePropagation
+	baggageFields          map[string]bool
 }
 
 func NewConfiguration(logLevel, application, project, projectGroup, version, customAttributesPrefix string) Configuration {
@@ -34,120 +70,388 @@ func NewConfiguration(logLevel, application, project, projectGroup, version, cus
 		projectGroup:           projectGroup,
 		version:                v,
 		customAttributesPrefix: customAttributesPrefix,
+		development:            os.Getenv(samLocalEnv) == "true",
+	}
+}
+
+func (c Configuration) LogLevel() string {
+	return c.logLevel
+}
+
+func (c Configuration) Application() string {
+	return c.application
+}
+
+func (c Configuration) Project() string {
+	return c.project
+}
+
+func (c Configuration) ProjectGroup() string {
+	return c.projectGroup
+}
+
+func (c Configuration) Version() string {
+	return c.version
+}
+
+func (c Configuration) CustomAttributesPrefix() string {
+	return c.customAttributesPrefix
+}
+
+// GetConfiguration returns the configuration passed to the last Init
+func GetConfiguration() Configuration {
+	if current := loadState(); current != nil {
+		return current.config
 	}
+	return Configuration{}
 }
 
 //Customizes logger to unify log format with ec2 application loggers
+//An invalid configuration is reported on stdout and replaced by sane defaults, use InitE to fail instead.
 func Init(config Configuration) {
-	logConfig = config
-	var logLevel zap.AtomicLevel
-	if err := logLevel.UnmarshalText([]byte(config.logLevel)); err != nil {
-		fmt.Printf("malformed log level: %+v\n", config.logLevel)
-		logLevel = zap.NewAtomicLevelAt(zap.InfoLevel)
-	}
-
-	rawLogger, _ := zap.Config{
-		Level:       logLevel,
-		Development: false,
-		Encoding:    "json",
-		Sampling: &zap.SamplingConfig{
-			Initial:    100,
-			Thereafter: 100,
-		},
-		EncoderConfig: zapcore.EncoderConfig{
-			TimeKey:        Timestamp,
-			LevelKey:       Level,
-			NameKey:        "logger",
-			CallerKey:      Logger,
-			MessageKey:     Message,
-			StacktraceKey:  StackTrace,
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeLevel:    zapcore.CapitalLevelEncoder,
-			EncodeTime:     zapcore.ISO8601TimeEncoder,
-			EncodeDuration: zapcore.SecondsDurationEncoder,
-			EncodeCaller:   zapcore.ShortCallerEncoder,
-		},
-		ErrorOutputPaths: []string{"stderr"},
-		OutputPaths:      []string{"stderr"},
-	}.Build()
+	if err := InitE(config); err != nil {
+		fmt.Printf("%+v\n", err)
+		logLevel := zap.NewAtomicLevelAt(zap.InfoLevel)
+		if level, err := parseLevel(config.logLevel); err == nil {
+			logLevel.SetLevel(level)
+		}
+		output, err := openOutput(config)
+		if err != nil {
+			output = zapcore.Lock(os.Stderr)
+		}
+		auditOutput, _ := openAuditOutput(config)
+		initLogger(config, logLevel, output, auditOutput)
+		setInitialized()
+	}
+}
+
+// InitE validates config and initializes the logger with it, the current logger is kept when it fails.
+func InitE(config Configuration) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	level, err := parseLevel(config.logLevel)
+	if err != nil {
+		return err
+	}
+	logLevel := zap.NewAtomicLevelAt(level)
+	output, err := openOutput(config)
+	if err != nil {
+		return fmt.Errorf("unable to open log output: %v", err)
+	}
+	auditOutput, err := openAuditOutput(config)
+	if err != nil {
+		return fmt.Errorf("unable to open audit output: %v", err)
+	}
+	initLogger(config, logLevel, output, auditOutput)
+	setInitialized()
+	return nil
+}
+
+// IsInitialized reports whether Init or InitE was called, until then a default logger writing INFO json records
+// to stderr is used.
+func IsInitialized() bool {
+	return atomic.LoadInt32(&initialized) == 1
+}
+
+// logger returns the package logger, initializing the default one when Init wasn't called yet.
+func logger() *zap.SugaredLogger {
+	return packageState().logger
+}
+
+func baseLogger() *zap.SugaredLogger {
+	return packageState().base
+}
+
+func initLogger(config Configuration, logLevel zap.AtomicLevel, output, auditOutput zapcore.WriteSyncer) {
+	replaceOutput(output)
+	rawLogger := newLogger(config, output, logLevel)
 
 	defer rawLogger.Sync()
 
-	log = rawLogger.
-		WithOptions(zap.AddCallerSkip(1)).
-		With(zap.String(Application, config.application)).
-		With(zap.String(Project, config.project)).
-		With(zap.String(ProjectGroup, config.projectGroup)).
-		With(zap.String(Version, config.version)).
+	base := rawLogger.
+		WithOptions(zap.AddCallerSkip(1 + config.caller.skip)).
+		With(resourceFields(config)...).
 		Sugar()
+	next := &loggerState{
+		base:   base,
+		emit:   newEmitLogger(config, output),
+		audit:  newAuditLogger(config, output, auditOutput),
+		config: config,
+		level:  logLevel,
+	}
+	next.setLogger(base)
+	storeState(next)
+	initErrorBudget(config.errorBudget)
+	initTail(config.tailSize)
+	initXRayMirror(config)
+	initTracePropagation(config.tracePropagation)
+	initRuntimeStats(config.runtimeStats)
+	initSchema(config.schema)
 
-	setUpXRay()
+	SetUpTracing(config.tracing)
 }
 
-func SetupTraceIds(ctx context.Context) {
-	if traceHeader := getTraceHeaderFromContext(ctx); traceHeader != nil {
-		log = log.
-			With(TraceId, traceHeader.TraceID).
-			With(CorrelationId, traceHeader.TraceID).
-			With(SpanId, traceHeader.ParentID).
-			With(TraceFlags, traceHeader.SamplingDecision == header.Sampled)
+func newEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        Timestamp,
+		LevelKey:       Level,
+		NameKey:        Namespace,
+		CallerKey:      Caller,
+		MessageKey:     Message,
+		StacktraceKey:  StackTrace,
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.CapitalLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
+// newLogger builds the same logger zap.Config would for a production json configuration,
+// but writing to output so custom write syncers can be used, with the caller and stack trace options of config.
+// FATAL records flush every logger before exiting.
+func newLogger(config Configuration, output zapcore.WriteSyncer, logLevel zap.AtomicLevel) *zap.Logger {
+	core := &fatalSyncCore{Core: newCore(config, newEncoder(config), output, logLevel)}
+	options := append(config.callerOptions(), zap.ErrorOutput(zapcore.Lock(os.Stderr)))
+	return zap.New(core, options...)
+}
+
+// fatalSyncCore flushes every logger once its core writes a FATAL record, before zap exits.
+// Checked FATAL records get it after the cores of its core, so its Write only syncs.
+type fatalSyncCore struct {
+	zapcore.Core
+}
+
+func (c *fatalSyncCore) With(fields []zapcore.Field) zapcore.Core {
+	return &fatalSyncCore{Core: c.Core.With(fields)}
+}
+
+func (c *fatalSyncCore) namespaceEnabled(name string, level zapcore.Level) bool {
+	return namespaceEnabled(c.Core, name, level)
+}
+
+func (c *fatalSyncCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	downstream := c.Core.Check(entry, checked)
+	if downstream != nil && entry.Level == zapcore.FatalLevel {
+		return downstream.AddCore(entry, c)
+	}
+	return downstream
+}
+
+func (c *fatalSyncCore) Write(zapcore.Entry, []zapcore.Field) error {
+	current := packageState()
+	_ = current.emit.Sync()
+	_ = current.audit.Sync()
+	return c.Core.Sync()
+}
+
+func resourceFields(config Configuration) []zap.Field {
+	fields := []zap.Field{
+		zap.String(Application, config.application),
+		zap.String(Project, config.project),
+		zap.String(ProjectGroup, config.projectGroup),
+		zap.String(Version, config.version),
+	}
+	if config.gitSha != "" {
+		fields = append(fields, zap.String(GitSha, config.gitSha))
+	}
+	if config.deploymentId != "" {
+		fields = append(fields, zap.String(DeploymentId, config.deploymentId))
+	}
+	fields = append(fields, zap.Int(SchemaVersion, LogSchemaVersion))
+	return append(fields, config.profile.staticFields...)
+}
+
+// SetupTraceIds replaces the invocation fields of the package logger with the trace fields and the Lambda context
+// (request id, function arn, version, memory limit, remaining time and cold start flag) found in ctx, and returns a copy of ctx carrying a logger scoped to them.
+// The logger of the returned context doesn't depend on the package logger, so it keeps the fields of this invocation
+// while other records are set up concurrently.
+// The first invocation of the execution environment also logs a cold start record, see ColdStart.
+func SetupTraceIds(ctx context.Context) context.Context {
+	return setupTraceIds(ctx, resetInvocation())
+}
+
+// setupRecordTraceIds behaves like SetupTraceIds for a record of the batch of the invocation, the SetUp*Record helpers
+// call it: the fields of the record are set up on the invocation fields set up before the first record, so none of
+// the previous record is left, and the attributes, the error budget and the tail of the invocation are kept.
+func setupRecordTraceIds(ctx context.Context) context.Context {
+	return setupTraceIds(ctx, resetRecord())
+}
+
+func setupTraceIds(ctx context.Context, bag *attrBag) context.Context {
+	lambdaFields, firstInvocation := lambdaContextFields(ctx)
+	fields := append(append(append(lambdaFields, traceIdFields(ctx)...), invocationSamplingFields(ctx)...), baggageFields(ctx)...)
+	if len(fields) == 0 {
+		return ctx
+	}
+	withInvocationFields(fields...)
+	if firstInvocation {
+		logColdStart()
+	}
+	return context.WithValue(ctx, contextKey{}, invocationLogger(ctx).with(append([]interface{}{attrsField(bag)}, fields...)))
+}
+
+// ResetInvocation drops the fields attached by SetupTraceIds and the SetUp* helpers, it should be deferred at the end of every invocation.
+func ResetInvocation() {
+	resetInvocation()
+}
+
+// resetInvocation returns the attribute bag of the new invocation.
+func resetInvocation() *attrBag {
+	budget.resetInvocation()
+	tail.resetInvocation()
+	bag := newAttrBag()
+	updateState(func(next *loggerState) {
+		// a new attribute bag, the loggers of the previous invocation keep theirs
+		next.invocationFields = []zap.Field{attrsField(bag)}
+		next.recordBase = nil
+		next.withLogger()
+	})
+	return bag
+}
+
+// resetRecord restores the invocation fields set up before the first record, keeping them on the first one,
+// and returns the attribute bag of the invocation, adding one when it wasn't set up.
+func resetRecord() *attrBag {
+	var bag *attrBag
+	updateState(func(next *loggerState) {
+		if next.recordBase == nil {
+			if findAttrs(next.invocationFields) == nil {
+				next.invocationFields = appendFields(next.invocationFields, []interface{}{attrsField(newAttrBag())})
+			}
+			next.recordBase = next.invocationFields
+		}
+		bag = findAttrs(next.recordBase)
+		next.invocationFields = next.recordBase
+		next.withLogger()
+	})
+	return bag
+}
+
+// invocationLogger returns the logger the fields of an invocation set up from ctx are added to: the one carried by ctx,
+// e.g. set up from the batch of the record, or the package logger without invocation fields.
+func invocationLogger(ctx context.Context) *contextLogger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey{}).(*contextLogger); ok {
+			return logger
+		}
+	}
+	base := packageState().base.Desugar().WithOptions(zap.AddCallerSkip(-1))
+	return &contextLogger{base: base, logger: base.Sugar()}
+}
+
+// withInvocationFields attaches fields that live until the next ResetInvocation, replacing any field with the same key.
+func withInvocationFields(keysAndValues ...interface{}) {
+	updateState(func(next *loggerState) {
+		next.invocationFields = appendFields(next.invocationFields, keysAndValues)
+		next.withLogger()
+	})
+}
+
+// withSetUpFields attaches fields to the invocation like withInvocationFields and returns a copy of ctx whose logger has them too.
+func withSetUpFields(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	withInvocationFields(keysAndValues...)
+	mirrorFields(ctx, keysAndValues)
+	return context.WithValue(ctx, contextKey{}, invocationLogger(ctx).with(keysAndValues))
+}
+
+func traceIdFields(ctx context.Context) []interface{} {
+	traceContext, ok := TraceContextFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	correlationId := CorrelationIdFromContext(ctx)
+	if correlationId == "" {
+		correlationId = traceContext.TraceId
+	}
+	return []interface{}{
+		TraceId, traceContext.TraceId,
+		CorrelationId, correlationId,
+		SpanId, traceContext.SpanId,
+		TraceFlags, traceContext.Sampled,
 	}
 }
 
 func Flush() error {
-	return log.Sync()
+	current := packageState()
+	_ = current.emit.Sync()
+	_ = current.audit.Sync()
+	return current.logger.Sync()
 }
 
 func Debug(template string, args ...interface{}) {
-	log.Debugf(template, args...)
+	logger().Debugf(template, args...)
 }
 
 func DebugW(msg string, keysAndValues ...interface{}) {
-	log.Debugw(msg, keysAndValues...)
+	logger().Debugw(msg, keysAndValues...)
 }
 
 func Info(template string, args ...interface{}) {
-	log.Infof(template, args...)
+	logger().Infof(template, args...)
 }
 
 func InfoW(msg string, keysAndValues ...interface{}) {
-	log.Infow(msg, keysAndValues...)
+	logger().Infow(msg, keysAndValues...)
 }
 
 func Warn(template string, args ...interface{}) {
-	log.Warnf(template, args...)
+	logger().Warnf(template, args...)
 }
 
 func WarnW(msg string, keysAndValues ...interface{}) {
-	log.Warnw(msg, keysAndValues...)
+	logger().Warnw(msg, keysAndValues...)
 }
 
 func Error(template string, args ...interface{}) {
-	log.Errorf(template, args...)
+	logger().Errorf(template, args...)
 }
 
 func ErrorW(msg string, keysAndValues ...interface{}) {
-	log.Errorw(msg, keysAndValues...)
+	logger().Errorw(msg, keysAndValues...)
+}
+
+// Fatal logs the message, flushes the logger and exits the process with status 1.
+func Fatal(template string, args ...interface{}) {
+	logger().Fatalf(template, args...)
+}
+
+func FatalW(msg string, keysAndValues ...interface{}) {
+	logger().Fatalw(msg, keysAndValues...)
 }
 
+// With adds fields to the package logger for the rest of the execution environment, it's safe for concurrent use.
 func With(args ...interface{}) {
-	log = log.With(args...)
+	mirror.with(args)
+	updateState(func(next *loggerState) {
+		next.base = next.base.With(args...)
+		next.withLogger()
+	})
 }
 
+// WithCustomAttr attaches Body.<custom attributes prefix>.key to every record of the execution environment.
+//
+// Deprecated: the attribute outlives the invocation, use PutAttr.
 func WithCustomAttr(key string, value interface{}) {
-	log = log.With(fmt.Sprintf("Body.%s.%s", logConfig.customAttributesPrefix, key), value)
+	With(fmt.Sprintf("Body.%s.%s", GetConfiguration().customAttributesPrefix, key), value)
+}
+
+// levelEnabled reports whether level is enabled for the package logger, whatever the levels of the namespaces.
+func levelEnabled(level zapcore.Level) bool {
+	return namespaceEnabled(logger().Desugar().Core(), "", level)
 }
 
 func IsDebugEnabled() bool {
-	return log.Desugar().Check(zapcore.DebugLevel, "") != nil
+	return levelEnabled(zapcore.DebugLevel)
 }
 
 func IsInfoEnabled() bool {
-	return log.Desugar().Check(zapcore.InfoLevel, "") != nil
+	return levelEnabled(zapcore.InfoLevel)
 }
 
 func IsWarnEnabled() bool {
-	return log.Desugar().Check(zapcore.WarnLevel, "") != nil
+	return levelEnabled(zapcore.WarnLevel)
 }
 
 func ToString(value interface{}) string {
@@ -3,8 +3,11 @@ package log_test
 import (
 	"context"
 	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
 	"github.com/aws/aws-xray-sdk-go/xray"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
 	"testing"
 )
 
@@ -104,3 +107,117 @@ func TestLogTraceIds(t *testing.T) {
 	log.SetupTraceIds(ctx)
 	log.Debug("Debug msg with value in context")
 }
+
+func TestContextLoggerDoesNotLeakFields(t *testing.T) {
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix")
+	log.Init(config)
+	ctx := log.NewContext(context.Background(), "test-key-1", "test-value-1")
+
+	assert.NotNil(t, log.FromContext(context.Background()))
+	assert.NotEqual(t, log.FromContext(context.Background()), log.FromContext(ctx))
+	assert.Equal(t, log.FromContext(ctx), log.FromContext(ctx))
+	log.FromContext(ctx).Debug("Debug msg with value in context logger")
+	log.Debug("Debug msg without value in context")
+}
+
+func TestResetInvocationClearsTraceIds(t *testing.T) {
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"",
+		"testPrefix")
+	log.Init(config)
+	recorder := logtest.Capture(t)
+	log.With("test-key-1", "test-value-1")
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Sampled=1;Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8")
+	scoped := log.SetupTraceIds(ctx)
+	log.Debug("Debug msg with trace ids")
+	log.FromContext(scoped).Debug("Debug msg with trace ids from context logger")
+	log.ResetInvocation()
+	log.Debug("Debug msg without trace ids")
+
+	entries := map[string]logtest.Entry{}
+	for _, entry := range recorder.Entries() {
+		entries[entry.Message] = entry
+	}
+	for _, message := range []string{"Debug msg with trace ids", "Debug msg with trace ids from context logger"} {
+		assert.Contains(t, entries[message].Fields, log.TraceId, message)
+		assert.Contains(t, entries[message].Fields, log.SpanId, message)
+	}
+	assert.Contains(t, entries, "Debug msg without trace ids")
+	assert.NotContains(t, entries["Debug msg without trace ids"].Fields, log.TraceId)
+	assert.NotContains(t, entries["Debug msg without trace ids"].Fields, log.SpanId)
+	assert.Equal(t, context.Background(), log.SetupTraceIds(context.Background()))
+}
+
+func TestSetLevel(t *testing.T) {
+	config := log.NewConfiguration(
+		"WARN",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix")
+	log.Init(config)
+	assert.False(t, log.IsDebugEnabled())
+
+	assert.NoError(t, log.SetLevel("DEBUG"))
+	assert.True(t, log.IsDebugEnabled())
+	assert.Equal(t, "DEBUG", log.GetLevel())
+
+	assert.Error(t, log.SetLevel("NOT-A-LEVEL"))
+	assert.True(t, log.IsDebugEnabled())
+}
+
+func TestInitE(t *testing.T) {
+	assert.NoError(t, log.InitE(log.NewConfiguration("INFO", "", "", "", "", "")))
+
+	config := log.NewConfiguration(
+		"INFO",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix")
+	assert.NoError(t, log.InitE(config))
+
+	err := log.InitE(log.NewConfiguration("NOT-A-LEVEL", "", "", "", "", "").
+		WithSampling(0, 10).
+		WithMaxEntrySize(10))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid log level "NOT-A-LEVEL"`)
+	assert.Contains(t, err.Error(), "sampling initial and thereafter must be positive")
+	assert.Contains(t, err.Error(), "max entry size must be at least")
+	assert.Equal(t, "TEST-APPLICATION", log.GetConfiguration().Application())
+	assert.False(t, log.IsDebugEnabled())
+}
+
+func TestDefaultLogger(t *testing.T) {
+	if os.Getenv("GOFRLIB_DEFAULT_LOGGER") == "true" {
+		log.Debug("Debug msg before init")
+		log.Info("Info msg before init")
+		log.FromContext(context.Background()).Info("Context msg before init")
+		if log.IsInitialized() {
+			os.Exit(1)
+		}
+		return
+	}
+	command := exec.Command(os.Args[0], "-test.run=^TestDefaultLogger$")
+	command.Env = append(os.Environ(), "GOFRLIB_DEFAULT_LOGGER=true")
+	output, err := command.CombinedOutput()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(output), "Debug msg before init")
+	assert.Contains(t, string(output), `"Body.message":"Info msg before init"`)
+	assert.Contains(t, string(output), "Context msg before init")
+
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", ""))
+	assert.True(t, log.IsInitialized())
+}
@@ -0,0 +1,172 @@
+package log
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"sync"
+)
+
+// StructuredLogger is the logging API of this package as an interface, so it can be injected into services and mocked in tests.
+type StructuredLogger interface {
+	Debug(template string, args ...interface{})
+	DebugW(msg string, keysAndValues ...interface{})
+	Info(template string, args ...interface{})
+	InfoW(msg string, keysAndValues ...interface{})
+	Warn(template string, args ...interface{})
+	WarnW(msg string, keysAndValues ...interface{})
+	Error(template string, args ...interface{})
+	ErrorW(msg string, keysAndValues ...interface{})
+	// With returns a child logger with the given fields, the receiver is not modified.
+	With(keysAndValues ...interface{}) StructuredLogger
+	// Named returns a child logger whose namespace is name appended to the namespace of the receiver, separated by a dot.
+	Named(name string) StructuredLogger
+}
+
+// NewLogger returns a StructuredLogger configured by config that is independent of the package logger,
+// Init, SetLevel, SetupTraceIds and the SetUp* helpers don't affect it.
+func NewLogger(config Configuration) (StructuredLogger, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	level, err := parseLevel(config.logLevel)
+	if err != nil {
+		return nil, err
+	}
+	logLevel := zap.NewAtomicLevelAt(level)
+	output, err := openOutput(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open log output: %v", err)
+	}
+	rawLogger := newLogger(config, output, logLevel).
+		WithOptions(zap.AddCallerSkip(1 + config.caller.skip)).
+		With(resourceFields(config)...)
+	return zapLogger{rawLogger.Sugar()}, nil
+}
+
+// Default returns the StructuredLogger the package level functions write to, it follows Init, SetupTraceIds and the SetUp* helpers.
+func Default() StructuredLogger {
+	return packageLogger{}
+}
+
+// Named returns a child of the package logger writing name in the Namespace field, e.g. "repository.orders",
+// so the layers of a Lambda can be filtered independently. The package logger is not modified.
+// It can be created once at start up and used in every invocation.
+func Named(name string) StructuredLogger {
+	return packageLogger{name: name, cache: &loggerCache{}}
+}
+
+type zapLogger struct {
+	logger *zap.SugaredLogger
+}
+
+func (l zapLogger) With(keysAndValues ...interface{}) StructuredLogger {
+	return zapLogger{l.logger.With(keysAndValues...)}
+}
+
+func (l zapLogger) Named(name string) StructuredLogger {
+	return zapLogger{l.logger.Named(name)}
+}
+
+func (l zapLogger) Debug(template string, args ...interface{}) {
+	l.logger.Debugf(template, args...)
+}
+
+func (l zapLogger) DebugW(msg string, keysAndValues ...interface{}) {
+	l.logger.Debugw(msg, keysAndValues...)
+}
+
+func (l zapLogger) Info(template string, args ...interface{}) {
+	l.logger.Infof(template, args...)
+}
+
+func (l zapLogger) InfoW(msg string, keysAndValues ...interface{}) {
+	l.logger.Infow(msg, keysAndValues...)
+}
+
+func (l zapLogger) Warn(template string, args ...interface{}) {
+	l.logger.Warnf(template, args...)
+}
+
+func (l zapLogger) WarnW(msg string, keysAndValues ...interface{}) {
+	l.logger.Warnw(msg, keysAndValues...)
+}
+
+func (l zapLogger) Error(template string, args ...interface{}) {
+	l.logger.Errorf(template, args...)
+}
+
+func (l zapLogger) ErrorW(msg string, keysAndValues ...interface{}) {
+	l.logger.Errorw(msg, keysAndValues...)
+}
+
+// packageLogger is a child of the package logger, it rebuilds its zap logger whenever the package logger changes.
+type packageLogger struct {
+	name   string
+	fields []interface{}
+	cache  *loggerCache
+}
+
+// loggerCache keeps the zap logger derived from the package logger until the package logger changes.
+type loggerCache struct {
+	sync.Mutex
+	parent *zap.SugaredLogger
+	logger *zap.SugaredLogger
+}
+
+func (l packageLogger) Named(name string) StructuredLogger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	return packageLogger{name: name, fields: l.fields, cache: &loggerCache{}}
+}
+
+func (l packageLogger) With(keysAndValues ...interface{}) StructuredLogger {
+	fields := append(append([]interface{}{}, l.fields...), keysAndValues...)
+	return packageLogger{name: l.name, fields: fields, cache: &loggerCache{}}
+}
+
+func (l packageLogger) logger() *zap.SugaredLogger {
+	parent := logger()
+	if l.cache == nil {
+		return parent.Named(l.name).With(l.fields...)
+	}
+	l.cache.Lock()
+	defer l.cache.Unlock()
+	if l.cache.parent != parent {
+		l.cache.parent = parent
+		l.cache.logger = parent.Named(l.name).With(l.fields...)
+	}
+	return l.cache.logger
+}
+
+func (l packageLogger) Debug(template string, args ...interface{}) {
+	l.logger().Debugf(template, args...)
+}
+
+func (l packageLogger) DebugW(msg string, keysAndValues ...interface{}) {
+	l.logger().Debugw(msg, keysAndValues...)
+}
+
+func (l packageLogger) Info(template string, args ...interface{}) {
+	l.logger().Infof(template, args...)
+}
+
+func (l packageLogger) InfoW(msg string, keysAndValues ...interface{}) {
+	l.logger().Infow(msg, keysAndValues...)
+}
+
+func (l packageLogger) Warn(template string, args ...interface{}) {
+	l.logger().Warnf(template, args...)
+}
+
+func (l packageLogger) WarnW(msg string, keysAndValues ...interface{}) {
+	l.logger().Warnw(msg, keysAndValues...)
+}
+
+func (l packageLogger) Error(template string, args ...interface{}) {
+	l.logger().Errorf(template, args...)
+}
+
+func (l packageLogger) ErrorW(msg string, keysAndValues ...interface{}) {
+	l.logger().Errorw(msg, keysAndValues...)
+}
@@ -0,0 +1,87 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+)
+
+func TestNamed(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer))
+	repository := log.Named("repository").Named("orders").With("table", "orders")
+	log.Init(config)
+	log.With("stage", "test")
+
+	repository.InfoW("Order saved", "orderId", "1")
+	log.Info("Handler done")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 2)
+	var named, root map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &named))
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &root))
+
+	assert.Equal(t, "repository.orders", named[log.Namespace])
+	assert.Equal(t, "orders", named["table"])
+	assert.Equal(t, "1", named["orderId"])
+	assert.Equal(t, "TEST-APPLICATION", named[log.Application])
+	assert.Equal(t, "test", named["stage"])
+	assert.Contains(t, named[log.Caller], "logger_test.go")
+
+	assert.NotContains(t, root, log.Namespace)
+	assert.NotContains(t, root, "table")
+}
+
+func TestNewLogger(t *testing.T) {
+	var packageBuffer, buffer bytes.Buffer
+	log.Init(log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&packageBuffer)))
+	logger, err := log.NewLogger(log.NewConfiguration(
+		"WARN",
+		"OTHER-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)))
+	assert.NoError(t, err)
+
+	logger.Info("Not enabled")
+	logger.Named("client").With("host", "example.com").WarnW("Slow response")
+	log.Default().Info("Package msg")
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(bytes.TrimSpace(buffer.Bytes()), &entry))
+	assert.Equal(t, "Slow response", entry[log.Message])
+	assert.Equal(t, "client", entry[log.Namespace])
+	assert.Equal(t, "example.com", entry["host"])
+	assert.Equal(t, "OTHER-APPLICATION", entry[log.Application])
+	assert.Contains(t, entry[log.Caller], "logger_test.go")
+
+	assert.Contains(t, packageBuffer.String(), "Package msg")
+	assert.NotContains(t, packageBuffer.String(), "Slow response")
+}
+
+func TestNewLoggerInvalidConfiguration(t *testing.T) {
+	logger, err := log.NewLogger(log.NewConfiguration("LOUD", "TEST-APPLICATION", "", "", "", ""))
+	assert.Error(t, err)
+	assert.Nil(t, logger)
+}
@@ -0,0 +1,174 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WithNamespaceLevels overrides the level of the loggers whose namespace, see Named, matches a pattern,
+// e.g. {"repository.*": "debug", "client.payments": "warn"}. A pattern ending with ".*" matches the namespace
+// before it and all its children, "*" matches every named logger. The most specific pattern wins: an exact match,
+// then the longest prefix. Other loggers keep following the level of the configuration and SetLevel.
+func (c Configuration) WithNamespaceLevels(levels map[string]string) Configuration {
+	merged := make(map[string]string, len(c.namespaceLevels)+len(levels))
+	for pattern, level := range c.namespaceLevels {
+		merged[pattern] = level
+	}
+	for pattern, level := range levels {
+		merged[pattern] = level
+	}
+	c.namespaceLevels = merged
+	return c
+}
+
+// parseNamespaceLevels parses a "pattern=level" comma separated list, like "repository.*=debug,client.payments=warn".
+func parseNamespaceLevels(value string) (map[string]string, error) {
+	levels := map[string]string{}
+	for _, override := range strings.Split(value, ",") {
+		if strings.TrimSpace(override) == "" {
+			continue
+		}
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("malformed namespace level %q, expected pattern=level", strings.TrimSpace(override))
+		}
+		levels[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return levels, nil
+}
+
+func (c Configuration) validateNamespaceLevels() []error {
+	patterns := make([]string, 0, len(c.namespaceLevels))
+	for pattern := range c.namespaceLevels {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	var errs []error
+	for _, pattern := range patterns {
+		if pattern == "" {
+			errs = append(errs, errors.New("namespace level pattern can't be empty"))
+		} else if _, err := parseLevel(c.namespaceLevels[pattern]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid level %q of namespace %s", c.namespaceLevels[pattern], pattern))
+		}
+	}
+	return errs
+}
+
+type namespaceLevel struct {
+	prefix string
+	exact  bool
+	level  zapcore.Level
+}
+
+func (n namespaceLevel) matches(name string) bool {
+	if n.exact {
+		return name == n.prefix
+	}
+	return n.prefix == "" || name == n.prefix || strings.HasPrefix(name, n.prefix+".")
+}
+
+// namespaceLevels resolves the level of every logger name, caching the result as names are few and fixed.
+type namespaceLevels struct {
+	logLevel zap.AtomicLevel
+	rules    []namespaceLevel
+	min      zapcore.Level
+	resolved sync.Map
+}
+
+// newNamespaceLevels parses levels ignoring the invalid ones, it returns nil when there is none left.
+func newNamespaceLevels(levels map[string]string, logLevel zap.AtomicLevel) *namespaceLevels {
+	n := &namespaceLevels{logLevel: logLevel, min: zapcore.FatalLevel}
+	for pattern, value := range levels {
+		level, err := parseLevel(value)
+		if pattern == "" || err != nil {
+			continue
+		}
+		rule := namespaceLevel{prefix: pattern, exact: true, level: level}
+		if pattern == "*" {
+			rule = namespaceLevel{level: level}
+		} else if strings.HasSuffix(pattern, ".*") {
+			rule = namespaceLevel{prefix: strings.TrimSuffix(pattern, ".*"), level: level}
+		}
+		n.rules = append(n.rules, rule)
+		if level < n.min {
+			n.min = level
+		}
+	}
+	if len(n.rules) == 0 {
+		return nil
+	}
+	// exact matches first, then the longest prefixes
+	sort.Slice(n.rules, func(i, j int) bool {
+		if n.rules[i].exact != n.rules[j].exact {
+			return n.rules[i].exact
+		}
+		return len(n.rules[i].prefix) > len(n.rules[j].prefix)
+	})
+	return n
+}
+
+// Enabled reports whether level is enabled for some logger, so the cores below keep every record that could be written.
+func (n *namespaceLevels) Enabled(level zapcore.Level) bool {
+	return level >= n.min || n.logLevel.Enabled(level)
+}
+
+func (n *namespaceLevels) enabled(name string, level zapcore.Level) bool {
+	if name == "" {
+		return n.logLevel.Enabled(level)
+	}
+	if resolved, ok := n.resolved.Load(name); ok {
+		if resolved == nil {
+			return n.logLevel.Enabled(level)
+		}
+		return resolved.(zapcore.Level).Enabled(level)
+	}
+	for _, rule := range n.rules {
+		if rule.matches(name) {
+			n.resolved.Store(name, rule.level)
+			return rule.level.Enabled(level)
+		}
+	}
+	n.resolved.Store(name, nil)
+	return n.logLevel.Enabled(level)
+}
+
+// namespaceEnabler is implemented by the cores resolving the level of the namespace of the records,
+// and by the cores wrapping them.
+type namespaceEnabler interface {
+	namespaceEnabled(name string, level zapcore.Level) bool
+}
+
+// namespaceEnabled reports whether level is enabled for the loggers named name, core.Enabled is true when it is
+// enabled for any of them.
+func namespaceEnabled(core zapcore.Core, name string, level zapcore.Level) bool {
+	if enabler, ok := core.(namespaceEnabler); ok {
+		return enabler.namespaceEnabled(name, level)
+	}
+	return core.Enabled(level)
+}
+
+// namespaceLevelCore drops the records below the level of the namespace of their logger.
+type namespaceLevelCore struct {
+	zapcore.Core
+	levels *namespaceLevels
+}
+
+func (c *namespaceLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &namespaceLevelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *namespaceLevelCore) namespaceEnabled(name string, level zapcore.Level) bool {
+	return c.levels.enabled(name, level) && namespaceEnabled(c.Core, name, level)
+}
+
+func (c *namespaceLevelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.levels.enabled(entry.LoggerName, entry.Level) {
+		return c.Core.Check(entry, checked)
+	}
+	return checked
+}
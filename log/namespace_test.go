@@ -0,0 +1,103 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+)
+
+func TestNamespaceLevels(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration(
+		"INFO",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithNamespaceLevels(map[string]string{
+			"repository.*":    "debug",
+			"client.payments": "warn",
+			"client.*":        "error",
+		}))
+
+	log.Debug("Root debug")
+	log.Info("Root info")
+	log.Named("repository").Debug("Repository debug")
+	log.Named("repository.orders").Debug("Orders debug")
+	log.Named("repositoryx").Debug("Other debug")
+	log.Named("client.payments").Info("Payments info")
+	log.Named("client.payments").Warn("Payments warn")
+	log.Named("client.bookings").Warn("Bookings warn")
+	log.Named("client.bookings").Error("Bookings error")
+
+	var messages []string
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		messages = append(messages, entry[log.Message].(string))
+	}
+	assert.Equal(t, []string{"Root info", "Repository debug", "Orders debug", "Payments warn", "Bookings error"}, messages)
+}
+
+func TestNamespaceLevelsDontEnableThePackageLogger(t *testing.T) {
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&bytes.Buffer{})).
+		WithNamespaceLevels(map[string]string{"repository.*": "debug"}))
+
+	assert.False(t, log.IsDebugEnabled())
+	assert.True(t, log.IsInfoEnabled())
+
+	assert.NoError(t, log.SetLevel("DEBUG"))
+	assert.True(t, log.IsDebugEnabled())
+}
+
+func TestNamespaceLevelsFollowSetLevel(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithNamespaceLevels(map[string]string{"client.*": "warn"}))
+
+	assert.NoError(t, log.SetLevel("DEBUG"))
+	log.Debug("Root debug")
+	log.Named("client").Info("Client info")
+
+	assert.Contains(t, buffer.String(), "Root debug")
+	assert.NotContains(t, buffer.String(), "Client info")
+}
+
+func TestNamespaceLevelsInvalid(t *testing.T) {
+	err := log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithNamespaceLevels(map[string]string{"client.*": "loud"}).
+		Validate()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid level "loud" of namespace client.*`)
+}
+
+func TestNamespaceLevelsFromEnv(t *testing.T) {
+	var buffer bytes.Buffer
+	setEnv(t, map[string]string{
+		log.ApplicationEnv:     "TEST-APPLICATION",
+		log.NamespaceLevelsEnv: "repository.*=debug, client.payments=warn",
+	})
+	config, err := log.NewConfigurationFromEnv()
+	assert.NoError(t, err)
+	log.Init(config.WithWriters(zapcore.AddSync(&buffer)))
+
+	log.Named("repository.orders").Debug("Orders debug")
+	log.Named("client.payments").Info("Payments info")
+
+	assert.Contains(t, buffer.String(), "Orders debug")
+	assert.NotContains(t, buffer.String(), "Payments info")
+
+	setEnv(t, map[string]string{log.NamespaceLevelsEnv: "repository.*"})
+	_, err = log.NewConfigurationFromEnv()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `LOG_NAMESPACE_LEVELS: malformed namespace level "repository.*", expected pattern=level`)
+}
@@ -0,0 +1,133 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	"strings"
+)
+
+// nestedValueKey holds the value of a key that also has nested keys
+const nestedValueKey = "_value"
+
+var nestedPool = buffer.NewPool()
+
+// WithNestedKeys writes the dotted keys of the records, e.g. Body.prefix.key of WithCustomAttr or Resource.application,
+// as nested json objects, {"Body":{"prefix":{"key":...}}}, for the stores treating the dots of the keys inconsistently.
+// The key constants of this package keep their dotted value, hooks and redaction rules are not affected.
+// A key holding a value besides nested keys, e.g. Body.error next to Body.error.kind, keeps it under Body.error._value.
+// Records are encoded flat then nested, a second pass over every record. The development console output stays flat.
+func (c Configuration) WithNestedKeys() Configuration {
+	c.nestedKeys = true
+	return c
+}
+
+func wrapNesting(config Configuration, encoder zapcore.Encoder) zapcore.Encoder {
+	if !config.nestedKeys {
+		return encoder
+	}
+	return &nestingEncoder{Encoder: encoder}
+}
+
+type nestingEncoder struct {
+	zapcore.Encoder
+}
+
+func (e *nestingEncoder) Clone() zapcore.Encoder {
+	return &nestingEncoder{Encoder: e.Encoder.Clone()}
+}
+
+func (e *nestingEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	encoded, err := e.Encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return encoded, err
+	}
+	record := bytes.TrimRight(encoded.Bytes(), " \r\n")
+	root, err := parseNested(record)
+	if err != nil {
+		// kept flat, the record is still valid json lines
+		return encoded, nil
+	}
+	nested := nestedPool.Get()
+	root.writeTo(nested)
+	_, _ = nested.Write(encoded.Bytes()[len(record):])
+	encoded.Free()
+	return nested, nil
+}
+
+type nestedNode struct {
+	keys     []string
+	children map[string]*nestedNode
+	value    json.RawMessage
+}
+
+func newNestedNode() *nestedNode {
+	return &nestedNode{children: map[string]*nestedNode{}}
+}
+
+// parseNested reads the top level keys of the json object record, in order, into a tree split on the dots of the keys.
+func parseNested(record []byte) (*nestedNode, error) {
+	decoder := json.NewDecoder(bytes.NewReader(record))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	root := newNestedNode()
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+		node := root
+		for _, part := range strings.Split(key, ".") {
+			node = node.child(part)
+		}
+		node.value = value
+	}
+	return root, nil
+}
+
+func (n *nestedNode) child(key string) *nestedNode {
+	child, ok := n.children[key]
+	if !ok {
+		child = newNestedNode()
+		n.children[key] = child
+		n.keys = append(n.keys, key)
+	}
+	return child
+}
+
+func (n *nestedNode) writeTo(out *buffer.Buffer) {
+	if len(n.keys) == 0 {
+		_, _ = out.Write(n.value)
+		return
+	}
+	out.AppendByte('{')
+	if n.value != nil {
+		writeNestedKey(out, nestedValueKey)
+		_, _ = out.Write(n.value)
+		out.AppendByte(',')
+	}
+	for i, key := range n.keys {
+		if i > 0 {
+			out.AppendByte(',')
+		}
+		writeNestedKey(out, key)
+		n.children[key].writeTo(out)
+	}
+	out.AppendByte('}')
+}
+
+func writeNestedKey(out *buffer.Buffer, key string) {
+	var quoted bytes.Buffer
+	encoder := json.NewEncoder(&quoted)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(key)
+	_, _ = out.Write(bytes.TrimRight(quoted.Bytes(), "\n"))
+	out.AppendByte(':')
+}
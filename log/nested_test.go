@@ -0,0 +1,39 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+)
+
+func TestNestedKeys(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithNestedKeys()
+	log.Init(config)
+	log.WithCustomAttr("channel", "web")
+	log.InfoW("Info msg with nested keys", "Body.error", "conflicting value", log.ErrorKind, "test-kind", "count", 3)
+
+	assert.True(t, strings.HasSuffix(buffer.String(), "}\n"))
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+	body := record["Body"].(map[string]interface{})
+	assert.Equal(t, "Info msg with nested keys", body["message"])
+	assert.Equal(t, map[string]interface{}{"channel": "web"}, body["testPrefix"])
+	assert.Equal(t, map[string]interface{}{"_value": "conflicting value", "kind": "test-kind"}, body["error"])
+	assert.Equal(t, "TEST-APPLICATION", record["Resource"].(map[string]interface{})["application"])
+	assert.Equal(t, float64(3), record["count"])
+	assert.Equal(t, "INFO", record[log.Level])
+	assert.NotContains(t, record, log.Message)
+}
@@ -0,0 +1,124 @@
+package log
+
+import (
+	"context"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"time"
+)
+
+// OTelLoggerProvider mirrors the LoggerProvider of the OpenTelemetry Logs Bridge API, so any SDK version
+// can be plugged in with a small adapter. NewOTLPProvider is a ready to use implementation shipping via OTLP/HTTP.
+type OTelLoggerProvider interface {
+	Logger(name string) OTelLogger
+}
+
+type OTelLogger interface {
+	Emit(ctx context.Context, record OTelRecord)
+}
+
+type OTelRecord struct {
+	Timestamp      time.Time
+	SeverityNumber int
+	SeverityText   string
+	Body           string
+	Attributes     map[string]interface{}
+	TraceId        string
+	SpanId         string
+	TraceFlags     byte
+}
+
+// WithOTelBridge forwards every record, besides writing it to the regular output, to the given provider.
+func (c Configuration) WithOTelBridge(provider OTelLoggerProvider) Configuration {
+	c.otelProvider = provider
+	return c
+}
+
+type otelCore struct {
+	zapcore.LevelEnabler
+	logger OTelLogger
+	fields []zapcore.Field
+}
+
+func newOTelCore(logger OTelLogger, enabler zapcore.LevelEnabler) zapcore.Core {
+	return &otelCore{LevelEnabler: enabler, logger: logger}
+}
+
+func (c *otelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &otelCore{
+		LevelEnabler: c.LevelEnabler,
+		logger:       c.logger,
+		fields:       append(append([]zapcore.Field{}, c.fields...), fields...),
+	}
+}
+
+func (c *otelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *otelCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(encoder)
+	}
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	record := OTelRecord{
+		Timestamp:      entry.Time,
+		SeverityNumber: otelSeverity(entry.Level),
+		SeverityText:   levelString(entry.Level),
+		Body:           entry.Message,
+		Attributes:     encoder.Fields,
+	}
+	if traceId, ok := encoder.Fields[TraceId].(string); ok {
+		record.TraceId = strings.ToLower(w3cTraceId(traceId))
+		delete(encoder.Fields, TraceId)
+	}
+	if spanId, ok := encoder.Fields[SpanId].(string); ok {
+		record.SpanId = spanId
+		delete(encoder.Fields, SpanId)
+	}
+	if sampled, ok := encoder.Fields[TraceFlags].(bool); ok {
+		if sampled {
+			record.TraceFlags = 1
+		}
+		delete(encoder.Fields, TraceFlags)
+	}
+	if entry.Caller.Defined {
+		record.Attributes[Caller] = entry.Caller.TrimmedPath()
+	}
+	if entry.Stack != "" {
+		record.Attributes[StackTrace] = entry.Stack
+	}
+	c.logger.Emit(context.Background(), record)
+	return nil
+}
+
+func (c *otelCore) Sync() error {
+	if flusher, ok := c.logger.(interface{ Flush() error }); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
+
+// Severity numbers as defined by the OpenTelemetry log data model
+func otelSeverity(level zapcore.Level) int {
+	switch level {
+	case TraceLevel:
+		return 1
+	case zapcore.DebugLevel:
+		return 5
+	case zapcore.InfoLevel:
+		return 9
+	case zapcore.WarnLevel:
+		return 13
+	case zapcore.ErrorLevel:
+		return 17
+	default:
+		return 21
+	}
+}
@@ -0,0 +1,137 @@
+package log_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOTLPBridge(t *testing.T) {
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var request map[string]interface{}
+		assert.NoError(t, json.Unmarshal(body, &request))
+		requests = append(requests, request)
+	}))
+	defer server.Close()
+
+	config := log.NewConfiguration(
+		"INFO",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithOTelBridge(log.NewOTLPProvider(server.URL, "TEST-APPLICATION"))
+	log.Init(config)
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Sampled=1;Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8")
+	log.SetupTraceIds(ctx)
+	log.Info("Info msg shipped to collector")
+	log.Debug("Debug msg not shipped")
+	log.ResetInvocation()
+
+	log.Flush()
+	assert.Len(t, requests, 1)
+	record := requests[0]["resourceLogs"].([]interface{})[0].(map[string]interface{})["scopeLogs"].([]interface{})[0].(map[string]interface{})["logRecords"].([]interface{})
+	assert.Len(t, record, 1)
+	assert.Equal(t, "Info msg shipped to collector", record[0].(map[string]interface{})["body"].(map[string]interface{})["stringValue"])
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793", record[0].(map[string]interface{})["traceId"])
+	assert.Equal(t, "53995c3f42cd8ad8", record[0].(map[string]interface{})["spanId"])
+}
+
+func TestOTLPBatchesAreExportedInBackground(t *testing.T) {
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	var batches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		mutex.Lock()
+		batches++
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	config := log.NewConfiguration(
+		"INFO",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithOTelBridge(log.NewOTLPProvider(server.URL, "TEST-APPLICATION").WithBatchSize(1))
+	log.Init(config)
+
+	logged := make(chan struct{})
+	go func() {
+		log.Info("first batch")
+		log.Info("second batch")
+		close(logged)
+	}()
+	select {
+	case <-logged:
+	case <-time.After(time.Second):
+		t.Fatal("logging waited for the collector")
+	}
+
+	close(release)
+	log.Flush()
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, 2, batches)
+}
+
+func TestOTLPProviderClose(t *testing.T) {
+	var mutex sync.Mutex
+	var exported int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mutex.Lock()
+		exported += strings.Count(string(body), `"severityText"`)
+		mutex.Unlock()
+	}))
+	defer server.Close()
+	provider := log.NewOTLPProvider(server.URL, "TEST-APPLICATION").WithBatchSize(2)
+	logger := provider.Logger("TEST-APPLICATION")
+
+	logger.Emit(context.Background(), log.OTelRecord{SeverityText: "INFO", Body: "Exported by Close"})
+	closed := make(chan error)
+	go func() {
+		closed <- provider.Close()
+	}()
+	select {
+	case err := <-closed:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Close didn't stop the export")
+	}
+
+	logger.Emit(context.Background(), log.OTelRecord{SeverityText: "INFO", Body: "Dropped"})
+	logger.Emit(context.Background(), log.OTelRecord{SeverityText: "INFO", Body: "Dropped"})
+	assert.Error(t, provider.Flush())
+	assert.NoError(t, provider.Close())
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, 1, exported)
+}
+
+type recordingProvider struct {
+	records []log.OTelRecord
+}
+
+func (p *recordingProvider) Logger(string) log.OTelLogger {
+	return p
+}
+
+func (p *recordingProvider) Emit(_ context.Context, record log.OTelRecord) {
+	p.records = append(p.records, record)
+}
@@ -0,0 +1,96 @@
+package log
+
+import "context"
+
+// OTelTracer mirrors the Tracer of the OpenTelemetry tracing API, with the trace.SpanFromContext function,
+// so any SDK version, e.g. the one of ADOT, can be plugged in with a small adapter.
+type OTelTracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, OTelSpan)
+	// SpanFromContext returns the active span of ctx, nil when there is none.
+	SpanFromContext(ctx context.Context) OTelSpan
+}
+
+type OTelSpan interface {
+	SpanContext() OTelSpanContext
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// OTelSpanContext holds the ids of a span, as lower case hex strings.
+type OTelSpanContext struct {
+	TraceId string
+	SpanId  string
+	Sampled bool
+	State   string
+}
+
+type otelTracing struct {
+	tracer OTelTracer
+}
+
+// OTelTracing traces with the OpenTelemetry SDK tracer, getting the trace context from the active span.
+// Spans are only started as children of an active span, the metadata are attributes named namespace.key.
+func OTelTracing(tracer OTelTracer) TracingProvider {
+	return otelTracing{tracer: tracer}
+}
+
+func (otelTracing) SetUp() error {
+	return nil
+}
+
+func (t otelTracing) TraceContext(ctx context.Context) (TraceContext, bool) {
+	span := t.tracer.SpanFromContext(ctx)
+	if span == nil || span.SpanContext().TraceId == "" {
+		return TraceContext{}, false
+	}
+	spanContext := span.SpanContext()
+	return TraceContext{
+		TraceId: spanContext.TraceId,
+		SpanId:  spanContext.SpanId,
+		Sampled: spanContext.Sampled,
+		State:   spanContext.State,
+	}, true
+}
+
+func (t otelTracing) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	if parent := t.tracer.SpanFromContext(ctx); parent == nil || parent.SpanContext().TraceId == "" {
+		return ctx, nil
+	}
+	spanCtx, span := t.tracer.Start(ctx, name)
+	return spanCtx, otelSpan{span}
+}
+
+func (t otelTracing) SpanFromContext(ctx context.Context) Span {
+	if span := t.tracer.SpanFromContext(ctx); span != nil && span.SpanContext().TraceId != "" {
+		return otelSpan{span}
+	}
+	return nil
+}
+
+type otelSpan struct {
+	span OTelSpan
+}
+
+func (s otelSpan) Id() string {
+	return s.span.SpanContext().SpanId
+}
+
+func (s otelSpan) Annotate(key string, value interface{}) {
+	s.span.SetAttribute(key, value)
+}
+
+func (s otelSpan) AddMetadata(namespace, key string, value interface{}) {
+	if text, ok := value.(string); ok {
+		s.span.SetAttribute(namespace+"."+key, text)
+		return
+	}
+	s.span.SetAttribute(namespace+"."+key, ToString(value))
+}
+
+func (s otelSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+	}
+	s.span.End()
+}
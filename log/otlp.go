@@ -0,0 +1,300 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOTLPBatchSize = 100
+	// the full batches waiting for the export, the ones beyond are dropped
+	otlpQueueSize = 4
+)
+
+// OTLPProvider ships records to an OpenTelemetry collector using OTLP/HTTP with JSON encoding.
+// Full batches are exported in the background, so a slow collector doesn't slow down the logging calls,
+// and the rest on Flush, which Init's logger calls on Sync, and Close. Export errors are reported on stderr.
+type OTLPProvider struct {
+	endpoint  string
+	client    *http.Client
+	batchSize int
+	resource  []otlpAttribute
+
+	mutex   sync.Mutex
+	records map[string][]otlpRecord
+	closed  bool
+	// sending counts the batches being sent to queue, Close closes it once they are sent
+	sending sync.WaitGroup
+	queue   chan otlpExport
+	started sync.Once
+	stopped chan struct{}
+}
+
+var errOTLPClosed = errors.New("otlp provider is closed")
+
+// otlpExport is a batch to export, done receives the result of the synchronous ones.
+type otlpExport struct {
+	records map[string][]otlpRecord
+	done    chan error
+}
+
+// NewOTLPProvider creates a provider posting to endpoint, e.g. "http://localhost:4318/v1/logs".
+func NewOTLPProvider(endpoint, serviceName string) *OTLPProvider {
+	return &OTLPProvider{
+		endpoint:  endpoint,
+		client:    &http.Client{Timeout: 5 * time.Second},
+		batchSize: defaultOTLPBatchSize,
+		resource:  []otlpAttribute{{Key: "service.name", Value: otlpValue(serviceName)}},
+		records:   map[string][]otlpRecord{},
+		queue:     make(chan otlpExport, otlpQueueSize),
+		stopped:   make(chan struct{}),
+	}
+}
+
+func (p *OTLPProvider) WithBatchSize(batchSize int) *OTLPProvider {
+	p.batchSize = batchSize
+	return p
+}
+
+func (p *OTLPProvider) WithHTTPClient(client *http.Client) *OTLPProvider {
+	p.client = client
+	return p
+}
+
+func (p *OTLPProvider) Logger(name string) OTelLogger {
+	return &otlpLogger{provider: p, scope: name}
+}
+
+// Flush exports the buffered records after the full batches waiting for the export, and waits for the result.
+// It fails once the provider is closed.
+func (p *OTLPProvider) Flush() error {
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return errOTLPClosed
+	}
+	records := p.take()
+	p.sending.Add(1)
+	p.mutex.Unlock()
+	defer p.sending.Done()
+	return p.exportAndWait(records, false)
+}
+
+// Close exports the buffered records and stops the background export, the records emitted afterwards are dropped.
+func (p *OTLPProvider) Close() error {
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return nil
+	}
+	p.closed = true
+	records := p.take()
+	p.mutex.Unlock()
+	// no batch is sent to queue once the ones in progress are
+	p.sending.Wait()
+	return p.exportAndWait(records, true)
+}
+
+// take swaps the buffered records, the caller holds the mutex.
+func (p *OTLPProvider) take() map[string][]otlpRecord {
+	records := p.records
+	p.records = map[string][]otlpRecord{}
+	return records
+}
+
+// exportAndWait exports records after the batches waiting for the export, closing the queue when last is set,
+// and waits for the result.
+func (p *OTLPProvider) exportAndWait(records map[string][]otlpRecord, last bool) error {
+	p.started.Do(p.start)
+	done := make(chan error, 1)
+	p.queue <- otlpExport{records: records, done: done}
+	if last {
+		close(p.queue)
+		defer func() { <-p.stopped }()
+	}
+	return <-done
+}
+
+func (p *OTLPProvider) emit(scope string, record otlpRecord) {
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return
+	}
+	p.records[scope] = append(p.records[scope], record)
+	if len(p.records[scope]) < p.batchSize {
+		p.mutex.Unlock()
+		return
+	}
+	records := p.take()
+	p.sending.Add(1)
+	p.mutex.Unlock()
+	defer p.sending.Done()
+	p.started.Do(p.start)
+	select {
+	case p.queue <- otlpExport{records: records}:
+	default:
+		fmt.Fprintf(os.Stderr, "dropped %d otlp log records, %d batches waiting for the export\n", otlpCount(records), otlpQueueSize)
+	}
+}
+
+// start launches the background export on the first full batch or Flush
+func (p *OTLPProvider) start() {
+	go func() {
+		defer close(p.stopped)
+		for batch := range p.queue {
+			err := p.export(batch.records)
+			if batch.done != nil {
+				batch.done <- err
+			} else if err != nil {
+				fmt.Fprintf(os.Stderr, "unable to export logs: %+v\n", err)
+			}
+		}
+	}()
+}
+
+func otlpCount(records map[string][]otlpRecord) int {
+	count := 0
+	for _, scopeRecords := range records {
+		count += len(scopeRecords)
+	}
+	return count
+}
+
+func (p *OTLPProvider) export(records map[string][]otlpRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	resourceLogs := otlpResourceLogs{Resource: otlpResource{Attributes: p.resource}}
+	for scope, scopeRecords := range records {
+		resourceLogs.ScopeLogs = append(resourceLogs.ScopeLogs, otlpScopeLogs{
+			Scope:      otlpScope{Name: scope},
+			LogRecords: scopeRecords,
+		})
+	}
+	body, err := json.Marshal(otlpRequest{ResourceLogs: []otlpResourceLogs{resourceLogs}})
+	if err != nil {
+		return err
+	}
+	response, err := p.client.Post(p.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("otlp export failed with status %d", response.StatusCode)
+	}
+	return nil
+}
+
+type otlpLogger struct {
+	provider *OTLPProvider
+	scope    string
+}
+
+func (l *otlpLogger) Emit(_ context.Context, record OTelRecord) {
+	l.provider.emit(l.scope, toOTLPRecord(record))
+}
+
+func (l *otlpLogger) Flush() error {
+	return l.provider.Flush()
+}
+
+func toOTLPRecord(record OTelRecord) otlpRecord {
+	keys := make([]string, 0, len(record.Attributes))
+	for key := range record.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	attributes := make([]otlpAttribute, 0, len(keys))
+	for _, key := range keys {
+		attributes = append(attributes, otlpAttribute{Key: key, Value: otlpValue(record.Attributes[key])})
+	}
+	return otlpRecord{
+		TimeUnixNano:   strconv.FormatInt(record.Timestamp.UnixNano(), 10),
+		SeverityNumber: record.SeverityNumber,
+		SeverityText:   record.SeverityText,
+		Body:           otlpValue(record.Body),
+		Attributes:     attributes,
+		TraceId:        record.TraceId,
+		SpanId:         record.SpanId,
+		Flags:          int(record.TraceFlags),
+	}
+}
+
+func otlpValue(value interface{}) otlpAnyValue {
+	switch v := value.(type) {
+	case string:
+		return otlpAnyValue{StringValue: &v}
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		i := fmt.Sprint(v)
+		return otlpAnyValue{IntValue: &i}
+	case float32:
+		f := float64(v)
+		return otlpAnyValue{DoubleValue: &f}
+	case float64:
+		return otlpAnyValue{DoubleValue: &v}
+	case fmt.Stringer:
+		s := v.String()
+		return otlpAnyValue{StringValue: &s}
+	default:
+		s := ToString(v)
+		return otlpAnyValue{StringValue: &s}
+	}
+}
+
+type otlpRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope    `json:"scope"`
+	LogRecords []otlpRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpRecord struct {
+	TimeUnixNano   string          `json:"timeUnixNano"`
+	SeverityNumber int             `json:"severityNumber"`
+	SeverityText   string          `json:"severityText"`
+	Body           otlpAnyValue    `json:"body"`
+	Attributes     []otlpAttribute `json:"attributes,omitempty"`
+	TraceId        string          `json:"traceId,omitempty"`
+	SpanId         string          `json:"spanId,omitempty"`
+	Flags          int             `json:"flags,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
@@ -0,0 +1,83 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	Stdout = "stdout"
+	Stderr = "stderr"
+)
+
+// WithOutputPaths replaces the default stderr output with the given paths, which can be Stdout, Stderr,
+// file paths or any url supported by zap.Open. Records are written to all of them.
+func (c Configuration) WithOutputPaths(paths ...string) Configuration {
+	c.outputPaths = append(append([]string{}, c.outputPaths...), paths...)
+	return c
+}
+
+// WithWriters adds custom write syncers to the outputs. When no output path is configured
+// records are written only to these writers.
+func (c Configuration) WithWriters(writers ...zapcore.WriteSyncer) Configuration {
+	c.writers = append(append([]zapcore.WriteSyncer{}, c.writers...), writers...)
+	return c
+}
+
+func openOutput(config Configuration) (zapcore.WriteSyncer, error) {
+	paths := config.outputPaths
+	if len(paths) == 0 && len(config.writers) == 0 && len(config.rotatingFiles) == 0 {
+		paths = []string{Stderr}
+	}
+	writers := make([]zapcore.WriteSyncer, 0, len(config.writers)+1)
+	if len(paths) > 0 {
+		sink, _, err := zap.Open(paths...)
+		if err != nil {
+			return nil, err
+		}
+		writers = append(writers, sink)
+	}
+	for _, file := range config.rotatingFiles {
+		rotating, err := openRotatingFile(file.path, file.options)
+		if err != nil {
+			return nil, err
+		}
+		writers = append(writers, rotating)
+	}
+	for _, writer := range config.writers {
+		writers = append(writers, zapcore.Lock(writer))
+	}
+	output := zapcore.NewMultiWriteSyncer(writers...)
+	if config.buffering.enabled {
+		return newBufferedWriteSyncer(output, config.buffering), nil
+	}
+	return output, nil
+}
+
+// WithCore writes records to core instead of the outputs, once they went through the level, sampling
+// and redaction of the configuration. It is meant for tests recording entries, see the logtest package.
+func (c Configuration) WithCore(core zapcore.Core) Configuration {
+	c.core = core
+	return c
+}
+
+// levelFilterCore restricts core to the levels of enabler on top of its own ones.
+type levelFilterCore struct {
+	zapcore.Core
+	enabler zapcore.LevelEnabler
+}
+
+func (c *levelFilterCore) Enabled(level zapcore.Level) bool {
+	return c.enabler.Enabled(level) && c.Core.Enabled(level)
+}
+
+func (c *levelFilterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelFilterCore{Core: c.Core.With(fields), enabler: c.enabler}
+}
+
+func (c *levelFilterCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enabler.Enabled(entry.Level) {
+		return c.Core.Check(entry, checked)
+	}
+	return checked
+}
@@ -0,0 +1,74 @@
+package log_test
+
+import (
+	"bytes"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMultipleOutputs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.log")
+	var buffer bytes.Buffer
+
+	config := log.NewConfiguration(
+		"INFO",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithOutputPaths(log.Stderr, path).
+		WithWriters(zapcore.AddSync(&buffer))
+	log.Init(config)
+	log.Info("Info msg written to every output")
+
+	content, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "Info msg written to every output")
+	assert.Contains(t, buffer.String(), "Info msg written to every output")
+}
+
+func TestWritersReplaceDefaultOutput(t *testing.T) {
+	var buffer bytes.Buffer
+
+	config := log.NewConfiguration(
+		"INFO",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer))
+	log.Init(config)
+	log.Info("Info msg written to buffer only")
+
+	assert.Contains(t, buffer.String(), `"Body.message":"Info msg written to buffer only"`)
+}
+
+func TestDevelopmentConsoleEncoding(t *testing.T) {
+	var buffer bytes.Buffer
+
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithDevelopment(true)
+	log.Init(config)
+	log.DebugW("Debug msg in console format", "test-key", "test-value")
+
+	assert.Contains(t, buffer.String(), "\x1b[35mDEBUG\x1b[0m")
+	assert.Contains(t, buffer.String(), "Debug msg in console format")
+	assert.Contains(t, buffer.String(), `"test-key": "test-value"`)
+}
@@ -0,0 +1,242 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FieldError is a problem found in a field of a payload, Field is its json path like "items[0].quantity",
+// empty when the payload as a whole is malformed.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// Validator checks a payload, returning every problem found.
+type Validator func(payload []byte) []FieldError
+
+// InvalidPayloadHandler receives the payloads failing validation, e.g. to send them to a dead-letter queue.
+type InvalidPayloadHandler func(ctx context.Context, source string, payload []byte, errs []FieldError)
+
+var validation struct {
+	sync.RWMutex
+	validators map[string]Validator
+	handler    InvalidPayloadHandler
+}
+
+// RegisterValidator validates the payloads of source in the SetUp* helpers: the body of "aws:sqs" messages,
+// the message of "aws:sns" records, the data of "aws:kinesis" records and the detail of EventBridge events,
+// registered by their source like "com.example.orders". Invalid payloads are logged at WARN with
+// their ValidationErrors and handed to the OnInvalidPayload handler. A nil validator removes the one of source.
+func RegisterValidator(source string, validator Validator) {
+	validation.Lock()
+	defer validation.Unlock()
+	if validator == nil {
+		delete(validation.validators, source)
+		return
+	}
+	if validation.validators == nil {
+		validation.validators = map[string]Validator{}
+	}
+	validation.validators[source] = validator
+}
+
+// OnInvalidPayload sets the handler of the payloads failing the validators of RegisterValidator.
+func OnInvalidPayload(handler InvalidPayloadHandler) {
+	validation.Lock()
+	defer validation.Unlock()
+	validation.handler = handler
+}
+
+// ValidatePayload checks payload with the validator of source, logging and handing it to the OnInvalidPayload
+// handler when invalid. Payloads of sources without validator are valid. The SetUp* helpers call it,
+// keysAndValues identify the payload in the log record.
+func ValidatePayload(ctx context.Context, source string, payload []byte, keysAndValues ...interface{}) bool {
+	validation.RLock()
+	validator, ok := validation.validators[source]
+	handler := validation.handler
+	validation.RUnlock()
+	if !ok {
+		return true
+	}
+	errs := validator(payload)
+	if len(errs) == 0 {
+		return true
+	}
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	enrichedLogger(ctx).Warnw("Invalid event payload", append([]interface{}{
+		EventSource, source,
+		ValidationErrors, messages,
+		EventBody, string(payload),
+	}, keysAndValues...)...)
+	if handler != nil {
+		handler(ctx, source, payload, errs)
+	}
+	return false
+}
+
+// StructValidator returns a Validator unmarshalling payloads into a new value of the type of prototype,
+// a struct or a pointer to one, and checking it with ValidateStruct.
+func StructValidator(prototype interface{}) Validator {
+	structType := reflect.TypeOf(prototype)
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	return func(payload []byte) []FieldError {
+		value := reflect.New(structType)
+		if err := json.Unmarshal(payload, value.Interface()); err != nil {
+			var typeError *json.UnmarshalTypeError
+			if errors.As(err, &typeError) {
+				return []FieldError{{Field: typeError.Field, Message: fmt.Sprintf("must be %s, got %s", typeError.Type, typeError.Value)}}
+			}
+			return []FieldError{{Message: "malformed json: " + err.Error()}}
+		}
+		return ValidateStruct(value.Interface())
+	}
+}
+
+// ValidateStruct checks the `validate` tags of the fields of value, a struct or a pointer to one, and of the structs
+// it holds. Rules are comma separated:
+//
+//	required  the field can't be the zero value, or empty for slices and maps
+//	min=n     numbers can't be lower than n, strings, slices and maps can't be shorter
+//	max=n     numbers can't be greater than n, strings, slices and maps can't be longer
+//	oneof=a b the field must be one of the space separated values
+//
+// Fields are named after their json tags.
+func ValidateStruct(value interface{}) []FieldError {
+	var errs []FieldError
+	validateValue(reflect.ValueOf(value), "", &errs)
+	return errs
+}
+
+func validateValue(value reflect.Value, path string, errs *[]FieldError) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			fieldPath := joinPath(path, jsonName(field))
+			if rules := field.Tag.Get("validate"); rules != "" {
+				for _, rule := range strings.Split(rules, ",") {
+					if message := checkRule(value.Field(i), rule); message != "" {
+						*errs = append(*errs, FieldError{Field: fieldPath, Message: message})
+					}
+				}
+			}
+			validateValue(value.Field(i), fieldPath, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			validateValue(value.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func checkRule(value reflect.Value, rule string) string {
+	name, argument := rule, ""
+	if i := strings.Index(rule, "="); i >= 0 {
+		name, argument = rule[:i], rule[i+1:]
+	}
+	switch name {
+	case "required":
+		if isEmpty(value) {
+			return "is required"
+		}
+	case "min", "max":
+		limit, err := strconv.ParseFloat(argument, 64)
+		if err != nil {
+			return fmt.Sprintf("malformed rule %q", rule)
+		}
+		size, subject, ok := measure(value)
+		if !ok {
+			return ""
+		}
+		if name == "min" && size < limit {
+			return fmt.Sprintf("%s must be at least %s, got %v", subject, argument, size)
+		}
+		if name == "max" && size > limit {
+			return fmt.Sprintf("%s must be at most %s, got %v", subject, argument, size)
+		}
+	case "oneof":
+		actual := fmt.Sprint(indirect(value).Interface())
+		for _, allowed := range strings.Fields(argument) {
+			if actual == allowed {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of %s, got %q", strings.Join(strings.Fields(argument), ", "), actual)
+	default:
+		return fmt.Sprintf("unknown rule %q", rule)
+	}
+	return ""
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && !value.IsNil() {
+		value = value.Elem()
+	}
+	return value
+}
+
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+// measure returns the number min and max compare, the length of strings, slices and maps, and what it is.
+func measure(value reflect.Value) (float64, string, bool) {
+	value = indirect(value)
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return float64(value.Len()), "length", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), "value", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), "value", true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), "value", true
+	}
+	return 0, "", false
+}
+
+func jsonName(field reflect.StructField) string {
+	if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+		return tag
+	}
+	return field.Name
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
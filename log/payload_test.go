@@ -0,0 +1,95 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+type orderItem struct {
+	Sku      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1,max=10"`
+}
+
+type order struct {
+	Id     string      `json:"id" validate:"required"`
+	Status string      `json:"status" validate:"oneof=NEW PAID"`
+	Items  []orderItem `json:"items" validate:"required,max=2"`
+	Note   *string     `json:"note" validate:"max=5"`
+}
+
+func TestValidateStruct(t *testing.T) {
+	note := "too long"
+	errs := log.ValidateStruct(order{
+		Status: "SHIPPED",
+		Items:  []orderItem{{Sku: "A", Quantity: 1}, {Quantity: 11}},
+		Note:   &note,
+	})
+
+	assert.Equal(t, []log.FieldError{
+		{Field: "id", Message: "is required"},
+		{Field: "status", Message: `must be one of NEW, PAID, got "SHIPPED"`},
+		{Field: "items[1].sku", Message: "is required"},
+		{Field: "items[1].quantity", Message: "value must be at most 10, got 11"},
+		{Field: "note", Message: "length must be at most 5, got 8"},
+	}, errs)
+	assert.Empty(t, log.ValidateStruct(&order{Id: "1", Status: "NEW", Items: []orderItem{{Sku: "A", Quantity: 1}}}))
+}
+
+func TestStructValidator(t *testing.T) {
+	validator := log.StructValidator(&order{})
+
+	assert.Empty(t, validator([]byte(`{"id":"1","status":"PAID","items":[{"sku":"A","quantity":2}]}`)))
+	assert.Equal(t, []log.FieldError{{Field: "items", Message: "is required"}},
+		validator([]byte(`{"id":"1","status":"PAID"}`)))
+	assert.Equal(t, []log.FieldError{{Field: "id", Message: "must be string, got number"}},
+		validator([]byte(`{"id":1}`)))
+	assert.Equal(t, "malformed json: unexpected end of JSON input", validator([]byte(`{"id":`))[0].Error())
+}
+
+func TestSetUpSqsRecordValidatesBody(t *testing.T) {
+	recorder := logtest.Capture(t)
+	log.RegisterValidator("aws:sqs", log.StructValidator(order{}))
+	var deadLetters []string
+	log.OnInvalidPayload(func(ctx context.Context, source string, payload []byte, errs []log.FieldError) {
+		deadLetters = append(deadLetters, string(payload))
+	})
+	defer log.OnInvalidPayload(nil)
+	defer log.RegisterValidator("aws:sqs", nil)
+
+	log.SetUpSqsRecord(context.Background(), events.SQSMessage{
+		MessageId:   "test-message-id",
+		EventSource: "aws:sqs",
+		Body:        `{"id":"1","status":"PAID","items":[{"sku":"A","quantity":2}]}`,
+	})
+	assert.Empty(t, deadLetters)
+	log.SetUpSqs(context.Background(), events.SQSEvent{Records: []events.SQSMessage{{
+		MessageId:   "test-message-id",
+		EventSource: "aws:sqs",
+		Body:        `{"id":"1","status":"LOST","items":[{"sku":"A","quantity":2}]}`,
+	}}})
+
+	assert.Equal(t, []string{`{"id":"1","status":"LOST","items":[{"sku":"A","quantity":2}]}`}, deadLetters)
+	recorder.AssertLogged(zapcore.WarnLevel, "Invalid event payload")
+	recorder.AssertField(log.ValidationErrors, []interface{}{`status: must be one of NEW, PAID, got "LOST"`})
+	recorder.AssertField(log.MessageId, "test-message-id")
+	recorder.AssertField(log.EventSource, "aws:sqs")
+}
+
+func TestSetUpEventBridgeValidatesDetail(t *testing.T) {
+	recorder := logtest.Capture(t)
+	log.RegisterValidator("com.example.orders", log.StructValidator(order{}))
+	defer log.RegisterValidator("com.example.orders", nil)
+
+	log.SetUpEventBridge(context.Background(), events.CloudWatchEvent{
+		Source: "com.example.orders",
+		Detail: []byte(`{"status":"NEW","items":[{"sku":"A","quantity":1}]}`),
+	})
+
+	recorder.AssertLogged(zapcore.WarnLevel, "Invalid event payload")
+	recorder.AssertField(log.ValidationErrors, []interface{}{"id: is required"})
+}
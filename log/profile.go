@@ -0,0 +1,230 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+// Profile renames the keys of the records written to the output to match the schema of a log platform.
+// Key constants of this package keep their value, so hooks, redaction rules and other cores are not affected.
+type Profile struct {
+	fieldNames   map[string]string
+	valueMappers map[string]func(string) string
+	levelEncoder zapcore.LevelEncoder
+	timeEncoder  zapcore.TimeEncoder
+	staticFields []zap.Field
+	// keyPrefix is prepended to the top level keys without a name in fieldNames
+	keyPrefix string
+}
+
+var DefaultProfile = Profile{}
+
+// ECSProfile maps the fields to Elastic Common Schema names.
+var ECSProfile = Profile{
+	fieldNames: map[string]string{
+		Timestamp:       "@timestamp",
+		Level:           "log.level",
+		Message:         "message",
+		StackTrace:      "error.stack_trace",
+		Caller:          "log.origin.file.name",
+		Namespace:       "log.logger",
+		TraceId:         "trace.id",
+		SpanId:          "span.id",
+		CorrelationId:   "labels.correlation_id",
+		TraceFlags:      "labels.trace_sampled",
+		Application:     "service.name",
+		Version:         "service.version",
+		Project:         "labels.project",
+		ProjectGroup:    "labels.project_group",
+		ErrorKind:       "error.type",
+		ErrorMessage:    "error.message",
+		ErrorStack:      "error.stack_trace",
+		EventSource:     "event.provider",
+		EventBody:       "event.original",
+		RequestId:       "http.request.id",
+		RequestMethod:   "http.request.method",
+		RequestRoute:    "url.path",
+		RequestSourceIp: "client.ip",
+		Region:          "cloud.region",
+		Account:         "cloud.account.id",
+	},
+	levelEncoder: zapcore.LowercaseLevelEncoder,
+	staticFields: []zap.Field{zap.String("ecs.version", "1.6.0")},
+}
+
+func (c Configuration) WithProfile(profile Profile) Configuration {
+	c.profile = profile
+	return c
+}
+
+// FieldName returns the key written to the output for one of the key constants of this package.
+func (p Profile) FieldName(key string) string {
+	if name, ok := p.fieldNames[key]; ok {
+		return name
+	}
+	return p.keyPrefix + key
+}
+
+func (p Profile) apply(encoderConfig zapcore.EncoderConfig) zapcore.EncoderConfig {
+	encoderConfig.TimeKey = p.FieldName(encoderConfig.TimeKey)
+	encoderConfig.LevelKey = p.FieldName(encoderConfig.LevelKey)
+	encoderConfig.NameKey = p.FieldName(encoderConfig.NameKey)
+	encoderConfig.CallerKey = p.FieldName(encoderConfig.CallerKey)
+	encoderConfig.MessageKey = p.FieldName(encoderConfig.MessageKey)
+	encoderConfig.StacktraceKey = p.FieldName(encoderConfig.StacktraceKey)
+	if p.levelEncoder != nil {
+		encoderConfig.EncodeLevel = p.levelEncoder
+	}
+	if p.timeEncoder != nil {
+		encoderConfig.EncodeTime = p.timeEncoder
+	}
+	return encoderConfig
+}
+
+func (p Profile) wrap(encoder zapcore.Encoder) zapcore.Encoder {
+	if len(p.fieldNames) == 0 && len(p.valueMappers) == 0 && p.keyPrefix == "" {
+		return encoder
+	}
+	return &renamingEncoder{Encoder: encoder, names: p.fieldNames, mappers: p.valueMappers, prefix: p.keyPrefix}
+}
+
+// renamingEncoder renames top level keys, and converts the string values of some of them,
+// before handing them to the wrapped encoder.
+type renamingEncoder struct {
+	zapcore.Encoder
+	names   map[string]string
+	mappers map[string]func(string) string
+	prefix  string
+}
+
+func (e *renamingEncoder) value(key, value string) string {
+	if mapper, ok := e.mappers[key]; ok {
+		return mapper(value)
+	}
+	return value
+}
+
+func (e *renamingEncoder) name(key string) string {
+	if name, ok := e.names[key]; ok {
+		return name
+	}
+	return e.prefix + key
+}
+
+func (e *renamingEncoder) Clone() zapcore.Encoder {
+	return &renamingEncoder{Encoder: e.Encoder.Clone(), names: e.names, mappers: e.mappers, prefix: e.prefix}
+}
+
+func (e *renamingEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	renamed := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		if field.Type == zapcore.StringType {
+			field.String = e.value(field.Key, field.String)
+		}
+		field.Key = e.name(field.Key)
+		renamed[i] = field
+	}
+	return e.Encoder.EncodeEntry(entry, renamed)
+}
+
+func (e *renamingEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	return e.Encoder.AddArray(e.name(key), marshaler)
+}
+
+func (e *renamingEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	return e.Encoder.AddObject(e.name(key), marshaler)
+}
+
+func (e *renamingEncoder) AddBinary(key string, value []byte) {
+	e.Encoder.AddBinary(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddByteString(key string, value []byte) {
+	e.Encoder.AddByteString(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddBool(key string, value bool) {
+	e.Encoder.AddBool(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddComplex128(key string, value complex128) {
+	e.Encoder.AddComplex128(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddComplex64(key string, value complex64) {
+	e.Encoder.AddComplex64(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddDuration(key string, value time.Duration) {
+	e.Encoder.AddDuration(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddFloat64(key string, value float64) {
+	e.Encoder.AddFloat64(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddFloat32(key string, value float32) {
+	e.Encoder.AddFloat32(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddInt(key string, value int) {
+	e.Encoder.AddInt(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddInt64(key string, value int64) {
+	e.Encoder.AddInt64(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddInt32(key string, value int32) {
+	e.Encoder.AddInt32(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddInt16(key string, value int16) {
+	e.Encoder.AddInt16(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddInt8(key string, value int8) {
+	e.Encoder.AddInt8(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddString(key, value string) {
+	e.Encoder.AddString(e.name(key), e.value(key, value))
+}
+
+func (e *renamingEncoder) AddTime(key string, value time.Time) {
+	e.Encoder.AddTime(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddUint(key string, value uint) {
+	e.Encoder.AddUint(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddUint64(key string, value uint64) {
+	e.Encoder.AddUint64(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddUint32(key string, value uint32) {
+	e.Encoder.AddUint32(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddUint16(key string, value uint16) {
+	e.Encoder.AddUint16(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddUint8(key string, value uint8) {
+	e.Encoder.AddUint8(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddUintptr(key string, value uintptr) {
+	e.Encoder.AddUintptr(e.name(key), value)
+}
+
+func (e *renamingEncoder) AddReflected(key string, value interface{}) error {
+	return e.Encoder.AddReflected(e.name(key), value)
+}
+
+func (e *renamingEncoder) OpenNamespace(key string) {
+	e.Encoder.OpenNamespace(e.name(key))
+}
@@ -0,0 +1,173 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+	"time"
+)
+
+func TestECSProfile(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithProfile(log.ECSProfile)
+	log.Init(config)
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Sampled=1;Root=TraceIdValue;Parent=ParentIdValue")
+	log.SetupTraceIds(ctx)
+	log.InfoW("Info msg in ECS format", "test-key", "test-value")
+	log.ResetInvocation()
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+	assert.Equal(t, "Info msg in ECS format", record["message"])
+	assert.Equal(t, "info", record["log.level"])
+	assert.Equal(t, "TEST-APPLICATION", record["service.name"])
+	assert.Equal(t, "TraceIdValue", record["trace.id"])
+	assert.Equal(t, "1.6.0", record["ecs.version"])
+	assert.Equal(t, "test-value", record["test-key"])
+	assert.Contains(t, record, "@timestamp")
+	assert.NotContains(t, record, log.Message)
+}
+
+func TestDatadogProfile(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithProfile(log.DatadogProfile("test"))
+	log.Init(config)
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Sampled=1;Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8")
+	log.SetupTraceIds(ctx)
+	log.Info("Info msg in Datadog format")
+	log.ResetInvocation()
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+	assert.Equal(t, "16266516598257821587", record["dd.trace_id"])
+	assert.Equal(t, "6023947403358210776", record["dd.span_id"])
+	assert.Equal(t, "TEST-APPLICATION", record["service"])
+	assert.Equal(t, "1.0.0", record["version"])
+	assert.Equal(t, "test", record["env"])
+	assert.Equal(t, "info", record["status"])
+}
+
+func TestDatadogIdsConversion(t *testing.T) {
+	assert.Equal(t, "16266516598257821587", log.DatadogTraceId("5759e988bd862e3fe1be46a994272793"))
+	assert.Equal(t, "not-a-trace-id", log.DatadogTraceId("not-a-trace-id"))
+	assert.Equal(t, "6023947403358210776", log.DatadogSpanId("53995c3f42cd8ad8"))
+}
+
+func TestFieldNames(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithProfile(log.ECSProfile).
+		WithFieldNames(log.FieldNames{log.Timestamp: "time", log.EventName: "event.action"})
+	log.Init(config)
+	log.InfoW("Info msg with renamed fields", log.EventName, "INSERT")
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+	assert.Contains(t, record, "time")
+	assert.NotContains(t, record, "@timestamp")
+	assert.Equal(t, "INSERT", record["event.action"])
+	assert.Equal(t, "Info msg with renamed fields", record["message"])
+	assert.Equal(t, "time", config.FieldName(log.Timestamp))
+	assert.Equal(t, "service.name", config.FieldName(log.Application))
+	assert.Equal(t, log.TraceFlags, log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").FieldName(log.TraceFlags))
+
+	assert.Error(t, config.WithFieldNames(log.FieldNames{log.Message: ""}).Validate())
+}
+
+func TestGELFProfile(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithProfile(log.GELFProfile("test-host"))
+	log.Init(config)
+	log.WarnW("Warn msg in GELF format", "test-key", "test-value")
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+	assert.Equal(t, "1.1", record["version"])
+	assert.Equal(t, "test-host", record["host"])
+	assert.Equal(t, "Warn msg in GELF format", record["short_message"])
+	assert.Equal(t, float64(4), record["level"])
+	assert.IsType(t, float64(0), record["timestamp"])
+	assert.Equal(t, "TEST-APPLICATION", record["_"+log.Application])
+	assert.Equal(t, "1.0.0", record["_"+log.Version])
+	assert.Equal(t, "test-value", record["_test-key"])
+	assert.NotContains(t, record, log.Message)
+}
+
+func TestGELFProfileTraceLevel(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"TRACE",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithProfile(log.GELFProfile("test-host"))
+	log.Init(config)
+	log.Tracef("Trace msg in GELF format")
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+	assert.Equal(t, float64(7), record["level"])
+}
+
+func TestLambdaProfile(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithProfile(log.LambdaProfile)
+	log.Init(config)
+	log.WarnW("Warn msg in Lambda format", log.AwsRequestId, "test-request-id")
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+	assert.Equal(t, "Warn msg in Lambda format", record["message"])
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "test-request-id", record["requestId"])
+	timestamp, err := time.Parse(time.RFC3339, record["timestamp"].(string))
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), timestamp, time.Minute)
+}
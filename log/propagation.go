@@ -0,0 +1,158 @@
+package log
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snsTypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqsTypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/aws-xray-sdk-go/header"
+)
+
+// AWSTraceHeaderAttribute is the SQS system attribute carrying the X-Ray trace header of the producer.
+const AWSTraceHeaderAttribute = "AWSTraceHeader"
+
+// TraceContextFromSqs reads the traceparent and tracestate message attributes of message,
+// falling back to its AWSTraceHeader system attribute.
+func TraceContextFromSqs(message events.SQSMessage) (TraceContext, bool) {
+	traceParent := sqsStringAttribute(message, TraceParentHeader)
+	if traceContext, err := ParseTraceParent(traceParent, sqsStringAttribute(message, TraceStateHeader)); err == nil {
+		return traceContext, true
+	}
+	return traceContextFromXRayHeader(message.Attributes[AWSTraceHeaderAttribute])
+}
+
+// TraceContextFromSns reads the traceparent and tracestate message attributes of entity,
+// falling back to an AWSTraceHeader message attribute.
+func TraceContextFromSns(entity events.SNSEntity) (TraceContext, bool) {
+	traceParent := snsStringAttribute(entity, TraceParentHeader)
+	if traceContext, err := ParseTraceParent(traceParent, snsStringAttribute(entity, TraceStateHeader)); err == nil {
+		return traceContext, true
+	}
+	return traceContextFromXRayHeader(snsStringAttribute(entity, AWSTraceHeaderAttribute))
+}
+
+// InjectTraceSqs sets the traceparent, tracestate, CorrelationId and baggage message attributes and the AWSTraceHeader
+// system attribute of an outgoing message from the trace and correlation ids and the baggage of ctx.
+func InjectTraceSqs(ctx context.Context, input *sqs.SendMessageInput) {
+	InjectCorrelationIdSqs(ctx, input)
+	InjectBaggageSqs(ctx, input)
+	traceContext, ok := TraceContextFromContext(ctx)
+	if !ok || traceContext.SpanId == "" {
+		return
+	}
+	if input.MessageAttributes == nil {
+		input.MessageAttributes = map[string]sqsTypes.MessageAttributeValue{}
+	}
+	for name, value := range traceAttributes(traceContext) {
+		input.MessageAttributes[name] = sqsTypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+	if input.MessageSystemAttributes == nil {
+		input.MessageSystemAttributes = map[string]sqsTypes.MessageSystemAttributeValue{}
+	}
+	input.MessageSystemAttributes[AWSTraceHeaderAttribute] = sqsTypes.MessageSystemAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(xRayTraceHeader(traceContext)),
+	}
+}
+
+// InjectTraceSns sets the traceparent, tracestate, CorrelationId and baggage message attributes of an outgoing message
+// from the trace and correlation ids and the baggage of ctx.
+func InjectTraceSns(ctx context.Context, input *sns.PublishInput) {
+	InjectCorrelationIdSns(ctx, input)
+	InjectBaggageSns(ctx, input)
+	traceContext, ok := TraceContextFromContext(ctx)
+	if !ok || traceContext.SpanId == "" {
+		return
+	}
+	if input.MessageAttributes == nil {
+		input.MessageAttributes = map[string]snsTypes.MessageAttributeValue{}
+	}
+	for name, value := range traceAttributes(traceContext) {
+		input.MessageAttributes[name] = snsTypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+}
+
+func traceAttributes(traceContext TraceContext) map[string]string {
+	attributes := map[string]string{TraceParentHeader: traceContext.TraceParent()}
+	if traceContext.State != "" {
+		attributes[TraceStateHeader] = traceContext.State
+	}
+	return attributes
+}
+
+func xRayTraceHeader(traceContext TraceContext) string {
+	decision := header.NotSampled
+	if traceContext.Sampled {
+		decision = header.Sampled
+	}
+	return header.Header{
+		TraceID:          xRayTraceId(traceContext.TraceId),
+		ParentID:         traceContext.SpanId,
+		SamplingDecision: decision,
+	}.String()
+}
+
+func traceContextFromXRayHeader(value string) (TraceContext, bool) {
+	if value == "" {
+		return TraceContext{}, false
+	}
+	traceHeader := header.FromString(value)
+	if traceHeader.TraceID == "" {
+		return TraceContext{}, false
+	}
+	return xRayTraceContext(traceHeader), true
+}
+
+func sqsMessageContext(ctx context.Context, message events.SQSMessage) context.Context {
+	if traceContext, ok := TraceContextFromSqs(message); ok {
+		ctx = ContextWithTraceContext(ctx, traceContext)
+	}
+	ctx = ContextWithBaggage(ctx, BaggageFromSqs(message))
+	return withCorrelationId(ctx, SqsCorrelationSource(message)())
+}
+
+func snsMessageContext(ctx context.Context, entity events.SNSEntity) context.Context {
+	if traceContext, ok := TraceContextFromSns(entity); ok {
+		ctx = ContextWithTraceContext(ctx, traceContext)
+	}
+	ctx = ContextWithBaggage(ctx, BaggageFromSns(entity))
+	return withCorrelationId(ctx, SnsCorrelationSource(entity)())
+}
+
+func withCorrelationId(ctx context.Context, correlationId string) context.Context {
+	if correlationId == "" || CorrelationIdFromContext(ctx) != "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationIdKey{}, correlationId)
+}
+
+// setupMessageTraceIds behaves like setupRecordTraceIds but also attaches a correlation id without a trace id.
+func setupMessageTraceIds(ctx context.Context) context.Context {
+	ctx = setupRecordTraceIds(ctx)
+	if correlationId := CorrelationIdFromContext(ctx); correlationId != "" {
+		ctx = withSetUpFields(ctx, CorrelationId, correlationId)
+	}
+	return ctx
+}
+
+func sqsStringAttribute(message events.SQSMessage, name string) string {
+	if attribute, exists := message.MessageAttributes[name]; exists && attribute.StringValue != nil {
+		return *attribute.StringValue
+	}
+	return ""
+}
+
+func snsStringAttribute(entity events.SNSEntity, name string) string {
+	attribute, _ := entity.MessageAttributes[name].(map[string]interface{})
+	value, _ := attribute["Value"].(string)
+	return value
+}
@@ -0,0 +1,69 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetUpSqsRecordWithTraceAttributes(t *testing.T) {
+	recorder := logtest.Capture(t)
+	log.SetUpSqsRecord(context.Background(), events.SQSMessage{
+		EventSource: "aws:sqs",
+		Attributes: map[string]string{
+			"AWSTraceHeader": "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1",
+		},
+		MessageAttributes: map[string]events.SQSMessageAttribute{
+			"CorrelationId": {StringValue: aws.String("correlation-1"), DataType: "String"},
+		},
+	})
+	log.Info("Processing message")
+	log.ResetInvocation()
+
+	recorder.AssertField(log.TraceId, "1-5759e988-bd862e3fe1be46a994272793")
+	recorder.AssertField(log.SpanId, "53995c3f42cd8ad8")
+	recorder.AssertField(log.CorrelationId, "correlation-1")
+}
+
+func TestSetUpSnsRecordWithTraceAttributes(t *testing.T) {
+	recorder := logtest.Capture(t)
+	log.SetUpSnsRecord(context.Background(), events.SNSEventRecord{
+		EventSource: "aws:sns",
+		SNS: events.SNSEntity{
+			MessageAttributes: map[string]interface{}{
+				"traceparent": map[string]interface{}{"Type": "String", "Value": "00-5759e988bd862e3fe1be46a994272793-53995c3f42cd8ad8-01"},
+			},
+		},
+	})
+	log.Info("Processing message")
+	log.ResetInvocation()
+
+	recorder.AssertField(log.TraceId, "5759e988bd862e3fe1be46a994272793")
+	recorder.AssertField(log.CorrelationId, "5759e988bd862e3fe1be46a994272793")
+}
+
+func TestInjectTrace(t *testing.T) {
+	ctx := log.ContextWithTraceContext(context.Background(), log.TraceContext{
+		TraceId: "5759e988bd862e3fe1be46a994272793",
+		SpanId:  "53995c3f42cd8ad8",
+		Sampled: true,
+	})
+	ctx, _ = log.EnsureCorrelationId(ctx)
+
+	sqsInput := &sqs.SendMessageInput{}
+	log.InjectTraceSqs(ctx, sqsInput)
+	assert.Equal(t, "00-5759e988bd862e3fe1be46a994272793-53995c3f42cd8ad8-01", *sqsInput.MessageAttributes["traceparent"].StringValue)
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793", *sqsInput.MessageAttributes["CorrelationId"].StringValue)
+	assert.Equal(t, "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1", *sqsInput.MessageSystemAttributes["AWSTraceHeader"].StringValue)
+
+	snsInput := &sns.PublishInput{}
+	log.InjectTraceSns(ctx, snsInput)
+	assert.Equal(t, "00-5759e988bd862e3fe1be46a994272793-53995c3f42cd8ad8-01", *snsInput.MessageAttributes["traceparent"].StringValue)
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793", *snsInput.MessageAttributes["CorrelationId"].StringValue)
+}
@@ -0,0 +1,112 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"reflect"
+	"time"
+)
+
+// throttlingErrorCodes are the error codes of the AWS APIs rejecting a call for its rate or the provisioned capacity.
+var throttlingErrorCodes = map[string]bool{
+	"ProvisionedThroughputExceededException": true,
+	"RequestLimitExceeded":                   true,
+	"ThrottlingException":                    true,
+	"Throttling":                             true,
+	"TooManyRequestsException":               true,
+}
+
+// IsThrottled reports whether err, or an error it wraps, is an AWS API error caused by throttling,
+// e.g. a ProvisionedThroughputExceededException of DynamoDB.
+func IsThrottled(err error) bool {
+	var apiError smithy.APIError
+	return errors.As(err, &apiError) && throttlingErrorCodes[apiError.ErrorCode()]
+}
+
+// LogQuery runs fn, a data access call like a DynamoDB or SQL query, within a span, see Trace, named after table
+// annotated with the operation and the table, and logs it with the operation, table, key, duration, the consumed
+// capacity when fn returns a DynamoDB output with one, and whether it was throttled, see IsThrottled.
+// keyDesc describes the accessed keys, e.g. "pk=BOOKING#42", it goes through the redaction like any field.
+// Calls are logged at INFO by the logger of ctx, failed ones at WARN with the fields of the error.
+func LogQuery(ctx context.Context, op, table, keyDesc string, fn func(context.Context) (interface{}, error)) (result interface{}, err error) {
+	start := time.Now()
+	queryCtx, span := tracingProvider().StartSpan(ctx, table)
+	if span != nil {
+		span.Annotate("operation", op)
+		span.Annotate("table", table)
+		mirrorContext(ctx, span)
+	}
+	defer func() {
+		recovered := recover()
+		if recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+		throttled := IsThrottled(err)
+		if span != nil {
+			span.Annotate("throttled", throttled)
+			span.End(err)
+		}
+		logQuery(ctx, op, table, keyDesc, time.Since(start), result, throttled, err)
+		if recovered != nil {
+			panic(recovered)
+		}
+	}()
+	return fn(queryCtx)
+}
+
+func logQuery(ctx context.Context, op, table, keyDesc string, duration time.Duration, result interface{}, throttled bool, err error) {
+	fields := []interface{}{
+		QueryOperation, op,
+		QueryTable, table,
+		QueryKey, keyDesc,
+		QueryDuration, duration,
+		QueryThrottled, throttled,
+	}
+	if capacity, ok := consumedCapacity(result); ok {
+		fields = append(fields, QueryConsumedCapacity, capacity)
+	}
+	logger := enrichedLogger(ctx)
+	if err != nil {
+		logger.Warnw("Query failed", append(fields, ErrorFields(err)...)...)
+		return
+	}
+	logger.Infow("Query", fields...)
+}
+
+// consumedCapacity returns the capacity units consumed by a DynamoDB call from the ConsumedCapacity of its output,
+// summed over the tables of the batch operations.
+func consumedCapacity(output interface{}) (float64, bool) {
+	value := reflect.ValueOf(output)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return 0, false
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return 0, false
+	}
+	field := value.FieldByName("ConsumedCapacity")
+	if !field.IsValid() {
+		return 0, false
+	}
+	switch capacity := field.Interface().(type) {
+	case *types.ConsumedCapacity:
+		if capacity != nil && capacity.CapacityUnits != nil {
+			return *capacity.CapacityUnits, true
+		}
+	case []types.ConsumedCapacity:
+		total, found := 0.0, false
+		for _, c := range capacity {
+			if c.CapacityUnits != nil {
+				total += *c.CapacityUnits
+				found = true
+			}
+		}
+		return total, found
+	}
+	return 0, false
+}
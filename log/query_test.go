@@ -0,0 +1,51 @@
+package log_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func TestLogQuery(t *testing.T) {
+	recorder := logtest.Capture(t)
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+
+	var annotations map[string]interface{}
+	result, err := log.LogQuery(ctx, "GetItem", "bookings", "pk=BOOKING#42", func(ctx context.Context) (interface{}, error) {
+		annotations = xray.GetSegment(ctx).Annotations
+		return &dynamodb.GetItemOutput{ConsumedCapacity: &types.ConsumedCapacity{CapacityUnits: aws.Float64(0.5)}}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.IsType(t, &dynamodb.GetItemOutput{}, result)
+	assert.Equal(t, "GetItem", annotations["operation"])
+	assert.Equal(t, "bookings", annotations["table"])
+	recorder.AssertLogged(zapcore.InfoLevel, "Query")
+	recorder.AssertField(log.QueryOperation, "GetItem")
+	recorder.AssertField(log.QueryTable, "bookings")
+	recorder.AssertField(log.QueryKey, "pk=BOOKING#42")
+	recorder.AssertField(log.QueryConsumedCapacity, 0.5)
+	recorder.AssertField(log.QueryThrottled, false)
+}
+
+func TestLogQueryThrottled(t *testing.T) {
+	recorder := logtest.Capture(t)
+	throttled := fmt.Errorf("operation error: %w", &types.ProvisionedThroughputExceededException{Message: aws.String("rate exceeded")})
+
+	_, err := log.LogQuery(context.Background(), "Query", "bookings", "pk=CUSTOMER#7", func(ctx context.Context) (interface{}, error) {
+		return nil, throttled
+	})
+
+	assert.Equal(t, throttled, err)
+	assert.True(t, log.IsThrottled(err))
+	recorder.AssertLogged(zapcore.WarnLevel, "Query failed")
+	recorder.AssertField(log.QueryThrottled, true)
+}
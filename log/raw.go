@@ -0,0 +1,129 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/aws/aws-lambda-go/events"
+	"reflect"
+	"strings"
+)
+
+// SetUpRaw detects the event held by payload from its json shape and calls the matching SetUp* helper,
+// falling back to SetupTraceIds when the shape is unknown. Arrays of records, like the batches sent by
+// EventBridge Pipes, are handled as the event wrapping them.
+func SetUpRaw(ctx context.Context, payload []byte) context.Context {
+	if event, ok := detectEvent(payload); ok {
+		return SetUp(ctx, event)
+	}
+	ctx = SetupTraceIds(ctx)
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, "unknown",
+			EventBody, string(payload))
+	}
+	return ctx
+}
+
+type rawShape map[string]json.RawMessage
+
+func (s rawShape) has(keys ...string) bool {
+	for _, key := range keys {
+		if _, ok := s[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (s rawShape) string(key string) string {
+	var value string
+	_ = json.Unmarshal(s[key], &value)
+	return value
+}
+
+func detectEvent(payload []byte) (interface{}, bool) {
+	trimmed := strings.TrimSpace(string(payload))
+	if strings.HasPrefix(trimmed, "[") {
+		return detectRecords([]byte(`{"Records":`+trimmed+`}`), []byte(trimmed))
+	}
+	var shape rawShape
+	if err := json.Unmarshal(payload, &shape); err != nil {
+		return nil, false
+	}
+	switch {
+	case shape.has("Records"):
+		return detectRecords(payload, shape["Records"])
+	case shape.has("records", "eventSource"):
+		return decodeEvent(payload, &events.KafkaEvent{})
+	case shape.has("records", "deliveryStreamArn"):
+		return decodeEvent(payload, &events.KinesisFirehoseEvent{})
+	case shape.has("detail-type", "source"):
+		return decodeEvent(payload, &events.CloudWatchEvent{})
+	case shape.has("RequestType", "ResponseURL"):
+		return decodeEvent(payload, &cfn.Event{})
+	case shape.has("triggerSource", "userPoolId"):
+		return detectCognito(payload, shape.string("triggerSource"))
+	case shape.has("requestContext"):
+		var requestContext rawShape
+		_ = json.Unmarshal(shape["requestContext"], &requestContext)
+		switch {
+		case requestContext.has("elb"):
+			return decodeEvent(payload, &events.ALBTargetGroupRequest{})
+		case shape.string("version") == "2.0":
+			return decodeEvent(payload, &events.APIGatewayV2HTTPRequest{})
+		case shape.has("httpMethod"):
+			return decodeEvent(payload, &events.APIGatewayProxyRequest{})
+		}
+	}
+	return nil, false
+}
+
+// detectRecords decodes payload as the event matching the source of the first of its records.
+func detectRecords(payload []byte, records json.RawMessage) (interface{}, bool) {
+	var shapes []rawShape
+	if err := json.Unmarshal(records, &shapes); err != nil || len(shapes) == 0 {
+		return nil, false
+	}
+	source := shapes[0].string("eventSource")
+	if source == "" {
+		source = shapes[0].string("EventSource")
+	}
+	switch source {
+	case "aws:sqs":
+		return decodeEvent(payload, &events.SQSEvent{})
+	case "aws:sns":
+		return decodeEvent(payload, &events.SNSEvent{})
+	case "aws:s3":
+		return decodeEvent(payload, &events.S3Event{})
+	case "aws:kinesis":
+		return decodeEvent(payload, &events.KinesisEvent{})
+	case "aws:dynamodb":
+		return decodeEvent(payload, &events.DynamoDBEvent{})
+	case "aws:ses":
+		return decodeEvent(payload, &events.SimpleEmailEvent{})
+	}
+	return nil, false
+}
+
+func detectCognito(payload []byte, triggerSource string) (interface{}, bool) {
+	switch {
+	case strings.HasPrefix(triggerSource, "PreSignUp_"):
+		return decodeEvent(payload, &events.CognitoEventUserPoolsPreSignup{})
+	case strings.HasPrefix(triggerSource, "PostConfirmation_"):
+		return decodeEvent(payload, &events.CognitoEventUserPoolsPostConfirmation{})
+	case strings.HasPrefix(triggerSource, "TokenGeneration_"):
+		return decodeEvent(payload, &events.CognitoEventUserPoolsPreTokenGen{})
+	case strings.HasPrefix(triggerSource, "CustomMessage_"):
+		return decodeEvent(payload, &events.CognitoEventUserPoolsCustomMessage{})
+	}
+	return nil, false
+}
+
+// decodeEvent returns the value pointed by event, as the SetUp type switch matches values.
+func decodeEvent(payload []byte, event interface{}) (interface{}, bool) {
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, false
+	}
+	return reflect.ValueOf(event).Elem().Interface(), true
+}
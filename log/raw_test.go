@@ -0,0 +1,41 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"testing"
+)
+
+func TestSetUpRaw(t *testing.T) {
+	payloads := map[string]string{
+		"sqs":           `{"Records":[{"messageId":"1","eventSource":"aws:sqs","body":"{}"}]}`,
+		"sqs via pipes": `[{"messageId":"1","eventSource":"aws:sqs","body":"{}"}]`,
+		"sns":           `{"Records":[{"EventSource":"aws:sns","Sns":{"MessageId":"1","Message":"{}"}}]}`,
+		"s3":            `{"Records":[{"eventSource":"aws:s3","eventName":"ObjectCreated:Put","s3":{"bucket":{"name":"bucket"},"object":{"key":"key"}}}]}`,
+		"eventbridge":   `{"id":"1","detail-type":"BookingCreated","source":"bookings","account":"123456789012","region":"eu-west-1","detail":{}}`,
+		"apigateway":    `{"resource":"/bookings","httpMethod":"GET","requestContext":{"requestId":"request-1","stage":"prod"}}`,
+		"apigateway v2": `{"version":"2.0","routeKey":"GET /bookings","requestContext":{"requestId":"request-1","http":{"method":"GET"}}}`,
+		"alb":           `{"httpMethod":"GET","path":"/bookings","requestContext":{"elb":{"targetGroupArn":"arn"}}}`,
+	}
+	expectations := map[string][2]interface{}{
+		"sqs":           {log.EventSource, "sqs"},
+		"sqs via pipes": {log.EventSource, "sqs"},
+		"sns":           {log.EventSource, "sns"},
+		"s3":            {log.EventSource, "s3"},
+		"eventbridge":   {log.DetailType, "BookingCreated"},
+		"apigateway":    {log.RequestStage, "prod"},
+		"apigateway v2": {log.RequestRoute, "GET /bookings"},
+		"alb":           {log.TargetGroupArn, "arn"},
+	}
+	for name, payload := range payloads {
+		t.Run(name, func(t *testing.T) {
+			recorder := logtest.Capture(t)
+			log.SetUpRaw(context.Background(), []byte(payload))
+			log.Info("Processing event")
+			log.ResetInvocation()
+
+			recorder.AssertField(expectations[name][0].(string), expectations[name][1])
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+const PanicValue = "Body.panic"
+
+// RecoverAndLog must be deferred directly: it recovers a panic, logs it with its stack trace at error level
+// through the context logger and flushes the logger. When err is given the panic is turned into an error
+// returned by the enclosing function (which must use a named result), otherwise it is re-panicked.
+//
+//	func handle(ctx context.Context, event events.SQSEvent) (err error) {
+//		defer log.RecoverAndLog(ctx, &err)
+//		...
+//	}
+func RecoverAndLog(ctx context.Context, err ...*error) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	logger := FromContext(ctx)
+	logger.Errorw("Recovered from panic",
+		PanicValue, fmt.Sprintf("%v", recovered),
+		StackTrace, string(debug.Stack()))
+	_ = logger.Sync()
+	if len(err) == 0 || err[0] == nil {
+		panic(recovered)
+	}
+	*err[0] = fmt.Errorf("panic: %v", recovered)
+}
@@ -0,0 +1,43 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRecoverAndLogReturnsError(t *testing.T) {
+	provider := &recordingProvider{}
+	initRedactingLogger(provider)
+
+	err := func() (err error) {
+		defer log.RecoverAndLog(context.Background(), &err)
+		panic("test panic")
+	}()
+
+	assert.EqualError(t, err, "panic: test panic")
+	assert.Equal(t, "ERROR", provider.records[0].SeverityText)
+	assert.Equal(t, "test panic", provider.records[0].Attributes[log.PanicValue])
+	assert.Contains(t, provider.records[0].Attributes[log.StackTrace], "TestRecoverAndLogReturnsError")
+}
+
+func TestRecoverAndLogRepanics(t *testing.T) {
+	initDebugLogger()
+
+	assert.PanicsWithValue(t, "test panic", func() {
+		defer log.RecoverAndLog(context.Background())
+		panic("test panic")
+	})
+}
+
+func TestRecoverAndLogWithoutPanic(t *testing.T) {
+	initDebugLogger()
+
+	err := func() (err error) {
+		defer log.RecoverAndLog(context.Background(), &err)
+		return nil
+	}()
+
+	assert.NoError(t, err)
+}
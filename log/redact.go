@@ -0,0 +1,227 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"regexp"
+	"strings"
+)
+
+const Redacted = "[REDACTED]"
+
+var (
+	emailPattern      = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	creditCardPattern = regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`)
+	ssnPattern        = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+)
+
+// RedactionRule describes values scrubbed from every record, including the json dumps of the SetUp* helpers.
+type RedactionRule struct {
+	fields    []string
+	paths     [][]string
+	patterns  []*regexp.Regexp
+	tokenized []string
+	tokenKey  []byte
+}
+
+// RedactFields redacts fields and json object keys with any of the given names, compared case-insensitively.
+func RedactFields(names ...string) RedactionRule {
+	return RedactionRule{fields: names}
+}
+
+// RedactJSONPaths redacts values at dotted paths inside json values, "*" matches any key or array index,
+// e.g. "Records.*.messageAttributes".
+func RedactJSONPaths(paths ...string) RedactionRule {
+	rule := RedactionRule{}
+	for _, path := range paths {
+		rule.paths = append(rule.paths, strings.Split(path, "."))
+	}
+	return rule
+}
+
+// RedactPatterns replaces every match of the given expressions in messages and string values.
+func RedactPatterns(patterns ...*regexp.Regexp) RedactionRule {
+	return RedactionRule{patterns: patterns}
+}
+
+func RedactEmails() RedactionRule {
+	return RedactPatterns(emailPattern)
+}
+
+func RedactCreditCards() RedactionRule {
+	return RedactPatterns(creditCardPattern)
+}
+
+func RedactSSNs() RedactionRule {
+	return RedactPatterns(ssnPattern)
+}
+
+func (c Configuration) WithRedaction(rules ...RedactionRule) Configuration {
+	c.redaction = append(append([]RedactionRule{}, c.redaction...), rules...)
+	return c
+}
+
+type redactor struct {
+	fields    map[string]bool
+	paths     [][]string
+	patterns  []*regexp.Regexp
+	tokenKeys map[string][]byte
+}
+
+func newRedactor(rules []RedactionRule) *redactor {
+	r := &redactor{fields: map[string]bool{}, tokenKeys: map[string][]byte{}}
+	for _, rule := range rules {
+		for _, field := range rule.fields {
+			r.fields[strings.ToLower(field)] = true
+		}
+		for _, field := range rule.tokenized {
+			r.tokenKeys[strings.ToLower(field)] = rule.tokenKey
+		}
+		r.paths = append(r.paths, rule.paths...)
+		r.patterns = append(r.patterns, rule.patterns...)
+	}
+	return r
+}
+
+func (r *redactor) redactString(value string) string {
+	for _, pattern := range r.patterns {
+		value = pattern.ReplaceAllString(value, Redacted)
+	}
+	return value
+}
+
+func (r *redactor) redactField(field zapcore.Field) zapcore.Field {
+	if r.fields[strings.ToLower(field.Key)] {
+		return zap.String(field.Key, Redacted)
+	}
+	if key, ok := r.tokenKeys[strings.ToLower(field.Key)]; ok {
+		return tokenizeField(key, field)
+	}
+	switch field.Type {
+	case zapcore.StringType:
+		return zap.String(field.Key, r.redactText(field.String))
+	case zapcore.ReflectType:
+		if value, ok := r.redactValue(field.Interface); ok {
+			return zap.Reflect(field.Key, value)
+		}
+	case zapcore.ErrorType:
+		if err, ok := field.Interface.(error); ok {
+			return zap.String(field.Key, r.redactString(err.Error()))
+		}
+	case zapcore.StringerType:
+		if stringer, ok := field.Interface.(fmt.Stringer); ok {
+			return zap.String(field.Key, r.redactText(stringer.String()))
+		}
+	}
+	return field
+}
+
+// redactText scrubs a string value, decoding it first when it holds a json document such as an EventBody dump.
+func (r *redactor) redactText(value string) string {
+	trimmed := strings.TrimSpace(value)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		var document interface{}
+		if err := json.Unmarshal([]byte(trimmed), &document); err == nil {
+			if bytes, err := json.Marshal(r.redactDocument(document, nil)); err == nil {
+				value = string(bytes)
+			}
+		}
+	}
+	return r.redactString(value)
+}
+
+func (r *redactor) redactValue(value interface{}) (interface{}, bool) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	var document interface{}
+	if err := json.Unmarshal(bytes, &document); err != nil {
+		return nil, false
+	}
+	return r.redactDocument(document, nil), true
+}
+
+func (r *redactor) redactDocument(document interface{}, path []string) interface{} {
+	if r.matchesPath(path) {
+		return Redacted
+	}
+	switch value := document.(type) {
+	case map[string]interface{}:
+		for key, nested := range value {
+			if r.fields[strings.ToLower(key)] {
+				value[key] = Redacted
+				continue
+			}
+			if tokenKey, ok := r.tokenKeys[strings.ToLower(key)]; ok {
+				value[key] = tokenizeValue(tokenKey, nested)
+				continue
+			}
+			value[key] = r.redactDocument(nested, append(path, key))
+		}
+	case []interface{}:
+		for i, nested := range value {
+			value[i] = r.redactDocument(nested, append(path, "*"))
+		}
+	case string:
+		return r.redactString(value)
+	}
+	return document
+}
+
+func (r *redactor) matchesPath(path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	for _, candidate := range r.paths {
+		if len(candidate) != len(path) {
+			continue
+		}
+		matches := true
+		for i := range candidate {
+			if candidate[i] != "*" && candidate[i] != path[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+type redactingCore struct {
+	zapcore.Core
+	redactor *redactor
+}
+
+func newRedactingCore(core zapcore.Core, redactor *redactor) zapcore.Core {
+	return &redactingCore{Core: core, redactor: redactor}
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.redactFields(fields)), redactor: c.redactor}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.redactor.redactString(entry.Message)
+	return c.Core.Write(entry, c.redactFields(fields))
+}
+
+func (c *redactingCore) redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = c.redactor.redactField(field)
+	}
+	return redacted
+}
@@ -0,0 +1,58 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func initRedactingLogger(provider *recordingProvider, rules ...log.RedactionRule) {
+	log.Init(log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithOTelBridge(provider).
+		WithRedaction(rules...))
+}
+
+func TestRedactFieldNames(t *testing.T) {
+	provider := &recordingProvider{}
+	initRedactingLogger(provider, log.RedactFields("password"))
+
+	log.InfoW("Info msg with secret", "Password", "secret-value", "user", "test-user")
+
+	assert.Equal(t, log.Redacted, provider.records[0].Attributes["Password"])
+	assert.Equal(t, "test-user", provider.records[0].Attributes["user"])
+}
+
+func TestRedactEventBody(t *testing.T) {
+	provider := &recordingProvider{}
+	initRedactingLogger(provider, log.RedactFields("email"), log.RedactJSONPaths("Records.*.body"))
+	event := events.SQSEvent{Records: []events.SQSMessage{{
+		MessageId: "test-message-id",
+		Body:      `{"email":"someone@example.com"}`,
+	}}}
+
+	log.SetUpSqs(context.Background(), event)
+
+	body := provider.records[0].Attributes[log.EventBody].(string)
+	assert.Contains(t, body, `"body":"[REDACTED]"`)
+	assert.Contains(t, body, "test-message-id")
+	assert.NotContains(t, body, "someone@example.com")
+}
+
+func TestRedactPatterns(t *testing.T) {
+	provider := &recordingProvider{}
+	initRedactingLogger(provider, log.RedactEmails(), log.RedactCreditCards(), log.RedactSSNs())
+
+	log.InfoW("Payment from someone@example.com", "card", "4111 1111 1111 1111", "ssn", "078-05-1120")
+
+	assert.Equal(t, "Payment from [REDACTED]", provider.records[0].Body)
+	assert.Equal(t, log.Redacted, provider.records[0].Attributes["card"])
+	assert.Equal(t, log.Redacted, provider.records[0].Attributes["ssn"])
+}
@@ -0,0 +1,29 @@
+package log
+
+// LogSchemaVersion is the version of the field layout of the records, written as SchemaVersion on every record.
+// It's incremented whenever this package renames, moves or retypes one of its fields, so downstream parsers
+// can branch on it. Adding fields doesn't change it.
+//
+//	1  the layout of the key constants of this package
+const LogSchemaVersion = 1
+
+// WithGitSha adds the commit the function was built from to every record as GitSha.
+func (c Configuration) WithGitSha(sha string) Configuration {
+	c.gitSha = sha
+	return c
+}
+
+// WithDeploymentId adds the id of the deployment the function belongs to, e.g. the CodeDeploy deployment id
+// or the id of the pipeline run, to every record as DeploymentId.
+func (c Configuration) WithDeploymentId(id string) Configuration {
+	c.deploymentId = id
+	return c
+}
+
+func (c Configuration) GitSha() string {
+	return c.gitSha
+}
+
+func (c Configuration) DeploymentId() string {
+	return c.deploymentId
+}
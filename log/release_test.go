@@ -0,0 +1,27 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func TestReleaseFields(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "1.0.0", "").
+		WithGitSha("3f2a9c1").
+		WithDeploymentId("d-42").
+		WithWriters(zapcore.AddSync(&buffer)))
+
+	log.Info("Released record")
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &record))
+	assert.Equal(t, "1.0.0", record[log.Version])
+	assert.Equal(t, "3f2a9c1", record[log.GitSha])
+	assert.Equal(t, "d-42", record[log.DeploymentId])
+	assert.Equal(t, float64(log.LogSchemaVersion), record[log.SchemaVersion])
+}
@@ -0,0 +1,42 @@
+package log
+
+import (
+	"context"
+	"go.uber.org/zap/zapcore"
+	"net/http"
+	"time"
+)
+
+// MaxResponseBodySize is the number of bytes of the response bodies logged by LogResponse, longer ones are truncated.
+const MaxResponseBodySize = 4096
+
+// LogResponse logs the access log "Response" record of an API handler with the status, the latency since startedAt
+// and the size of the response, on top of the route, method and request id attached by SetUpApiGateway,
+// at WARN level for server errors and INFO otherwise. The body is logged too when DEBUG is enabled, see BodyField,
+// redacted like every record and truncated to MaxResponseBodySize bytes with Truncated set.
+func LogResponse(ctx context.Context, statusCode int, body string, startedAt time.Time) {
+	logger := enrichedLogger(ctx)
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	fields := []interface{}{
+		ResponseStatusCode, statusCode,
+		BytesField(ResponseSize, int64(len(body))),
+	}
+	if !startedAt.IsZero() {
+		fields = append(fields, ResponseDuration, time.Since(startedAt))
+	}
+	if body != "" && logger.Desugar().Core().Enabled(zapcore.DebugLevel) {
+		field := BodyField(ResponseBody, body, false, "")
+		if field.Type == zapcore.StringType && len(field.String) > MaxResponseBodySize {
+			field.String = truncateString(field.String, MaxResponseBodySize)
+			fields = append(fields, Truncated, true)
+		}
+		fields = append(fields, field)
+	}
+	if statusCode >= http.StatusInternalServerError {
+		logger.Warnw("Response", fields...)
+		return
+	}
+	logger.Infow("Response", fields...)
+}
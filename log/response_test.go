@@ -0,0 +1,54 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogResponse(t *testing.T) {
+	log.Init(log.NewConfiguration("DEBUG", "TEST-APPLICATION", "", "", "", "").WithRedaction(log.RedactEmails()))
+	recorder := logtest.Capture(t)
+	defer log.ResetInvocation()
+
+	ctx := log.SetUpApiGateway(context.Background(), events.APIGatewayProxyRequest{Resource: "/bookings/{id}", HTTPMethod: "GET"})
+	log.LogResponse(ctx, 200, `{"email":"jane@example.com"}`, time.Now().Add(-50*time.Millisecond))
+
+	recorder.AssertLogged(zapcore.InfoLevel, "Response")
+	entry := recorder.Entries()[len(recorder.Entries())-1]
+	assert.Equal(t, "/bookings/{id}", entry.Fields[log.RequestRoute])
+	assert.EqualValues(t, 200, entry.Fields[log.ResponseStatusCode])
+	assert.EqualValues(t, 28, entry.Fields[log.ResponseSize])
+	assert.True(t, entry.Fields[log.ResponseDuration].(time.Duration) >= 50*time.Millisecond)
+	assert.Equal(t, `{"email":"[REDACTED]"}`, entry.Fields[log.ResponseBody])
+	assert.NotContains(t, entry.Fields, log.Truncated)
+}
+
+func TestLogResponseTruncatesTheBody(t *testing.T) {
+	recorder := logtest.Capture(t)
+
+	log.LogResponse(context.Background(), 503, strings.Repeat("a", 5000), time.Time{})
+
+	recorder.AssertLogged(zapcore.WarnLevel, "Response")
+	entry := recorder.Entries()[0]
+	assert.Len(t, entry.Fields[log.ResponseBody], log.MaxResponseBodySize+3)
+	assert.Equal(t, true, entry.Fields[log.Truncated])
+	assert.NotContains(t, entry.Fields, log.ResponseDuration)
+}
+
+func TestLogResponseWithoutDebug(t *testing.T) {
+	recorder := logtest.Capture(t)
+	assert.NoError(t, log.SetLevel("INFO"))
+
+	log.LogResponse(context.Background(), 0, "body", time.Now())
+
+	recorder.AssertLogged(zapcore.InfoLevel, "Response")
+	recorder.AssertField(log.ResponseStatusCode, 200)
+	assert.NotContains(t, recorder.Entries()[0].Fields, log.ResponseBody)
+}
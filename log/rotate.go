@@ -0,0 +1,199 @@
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	rotationTimeFormat = "20060102T150405.000"
+	compressedSuffix   = ".gz"
+)
+
+// RotationOptions configures the rotation of the log file of WithRotatingFile.
+type RotationOptions struct {
+	// MaxSize is the size in bytes after which the file is rotated, 0 disables the rotation on size.
+	MaxSize int64
+	// MaxAge is the age after which the file is rotated, 0 disables the rotation on age.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files kept, 0 keeps them all.
+	MaxBackups int
+	// Compress gzips the rotated files.
+	Compress bool
+}
+
+type rotatingFileOptions struct {
+	path    string
+	options RotationOptions
+}
+
+// WithRotatingFile adds a json lines file at path to the outputs, for deployments without the stdout pipeline
+// of Lambda such as ECS tasks. The file is rotated on size or age, the rotated files are renamed with the time
+// of the rotation, e.g. app-20210304T050607.000.log, compressed and pruned as set by options.
+// Records are never split across files.
+func (c Configuration) WithRotatingFile(path string, options RotationOptions) Configuration {
+	c.rotatingFiles = append(append([]rotatingFileOptions{}, c.rotatingFiles...), rotatingFileOptions{path: path, options: options})
+	return c
+}
+
+type rotatingFile struct {
+	sync.Mutex
+	path    string
+	options RotationOptions
+	file    *os.File
+	size    int64
+	opened  time.Time
+	rotated time.Time
+	// background serializes the compression and pruning of the rotated files.
+	background  sync.Mutex
+	compressing sync.WaitGroup
+}
+
+func openRotatingFile(path string, options RotationOptions) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, options: options}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	r.file = file
+	r.size = info.Size()
+	r.opened = time.Now()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.Lock()
+	defer r.Unlock()
+	if r.size > 0 && r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, fmt.Errorf("unable to rotate %s: %v", r.path, err)
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) shouldRotate(size int64) bool {
+	if r.options.MaxSize > 0 && r.size+size > r.options.MaxSize {
+		return true
+	}
+	return r.options.MaxAge > 0 && time.Since(r.opened) >= r.options.MaxAge
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	// rotation times are kept increasing so the rotated files of the same millisecond keep their order
+	rotatedAt := time.Now().UTC().Truncate(time.Millisecond)
+	if !rotatedAt.After(r.rotated) {
+		rotatedAt = r.rotated.Add(time.Millisecond)
+	}
+	r.rotated = rotatedAt
+	extension := filepath.Ext(r.path)
+	rotated := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(r.path, extension), rotatedAt.Format(rotationTimeFormat), extension)
+	if err := os.Rename(r.path, rotated); err != nil {
+		return err
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.compressing.Add(1)
+	go func() {
+		defer r.compressing.Done()
+		r.background.Lock()
+		defer r.background.Unlock()
+		if r.options.Compress {
+			if err := compressFile(rotated); err != nil {
+				fmt.Fprintf(os.Stderr, "unable to compress %s: %v\n", rotated, err)
+			}
+		}
+		r.prune()
+	}()
+	return nil
+}
+
+// Sync flushes the file and waits for the compression of the rotated files.
+func (r *rotatingFile) Sync() error {
+	r.Lock()
+	err := r.file.Sync()
+	r.Unlock()
+	r.compressing.Wait()
+	return err
+}
+
+func compressFile(path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	target, err := os.OpenFile(path+compressedSuffix, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(target)
+	if _, err := io.Copy(writer, source); err != nil {
+		_ = target.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		_ = target.Close()
+		return err
+	}
+	if err := target.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// prune removes the oldest rotated files beyond MaxBackups, rotated files sort by their rotation time.
+func (r *rotatingFile) prune() {
+	if r.options.MaxBackups <= 0 {
+		return
+	}
+	extension := filepath.Ext(r.path)
+	prefix := strings.TrimSuffix(r.path, extension) + "-"
+	candidates, err := filepath.Glob(prefix + "*" + extension + "*")
+	if err != nil {
+		return
+	}
+	var rotated []string
+	for _, candidate := range candidates {
+		rotatedAt := strings.TrimPrefix(candidate, prefix)
+		if len(rotatedAt) < len(rotationTimeFormat) {
+			continue
+		}
+		if _, err := time.Parse(rotationTimeFormat, rotatedAt[:len(rotationTimeFormat)]); err == nil {
+			rotated = append(rotated, candidate)
+		}
+	}
+	sort.Strings(rotated)
+	for len(rotated) > r.options.MaxBackups {
+		_ = os.Remove(rotated[0])
+		rotated = rotated[1:]
+	}
+}
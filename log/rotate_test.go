@@ -0,0 +1,108 @@
+package log_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func rotatingFileConfiguration(path string, options log.RotationOptions) log.Configuration {
+	return log.NewConfiguration(
+		"INFO",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithRotatingFile(path, options)
+}
+
+func TestRotatingFileRotatesOnSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	log.Init(rotatingFileConfiguration(path, log.RotationOptions{MaxSize: 1024}))
+	for i := 0; i < 20; i++ {
+		log.Info("Info msg written to the rotating file")
+	}
+	assert.NoError(t, log.Flush())
+
+	rotated, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, rotated)
+	records := 0
+	for _, file := range append(rotated, path) {
+		info, err := os.Stat(file)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(1024))
+		content, err := ioutil.ReadFile(file)
+		assert.NoError(t, err)
+		for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+			assert.Contains(t, line, `"Body.message":"Info msg written to the rotating file"`)
+			records++
+		}
+	}
+	assert.Equal(t, 20, records)
+}
+
+func TestRotatingFileCompressesAndPrunesRotatedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	log.Init(rotatingFileConfiguration(path, log.RotationOptions{MaxSize: 512, MaxBackups: 2, Compress: true}))
+	for i := 0; i < 20; i++ {
+		log.Info("Info msg written to the rotating file")
+	}
+	assert.NoError(t, log.Flush())
+
+	uncompressed, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	assert.NoError(t, err)
+	assert.Empty(t, uncompressed)
+	compressed, err := filepath.Glob(filepath.Join(dir, "app-*.log.gz"))
+	assert.NoError(t, err)
+	assert.Len(t, compressed, 2)
+	for _, file := range compressed {
+		f, err := os.Open(file)
+		assert.NoError(t, err)
+		reader, err := gzip.NewReader(f)
+		assert.NoError(t, err)
+		scanner := bufio.NewScanner(reader)
+		assert.True(t, scanner.Scan())
+		assert.Contains(t, scanner.Text(), `"Body.message":"Info msg written to the rotating file"`)
+		_ = f.Close()
+	}
+}
+
+func TestRotatingFileKeepsRecordsOnReopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("{}\n"), 0644))
+
+	log.Init(rotatingFileConfiguration(path, log.RotationOptions{MaxSize: 1 << 20}))
+	log.Info("Info msg appended to the rotating file")
+	assert.NoError(t, log.Flush())
+
+	content, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "{}\n"))
+	assert.Contains(t, string(content), "Info msg appended to the rotating file")
+}
+
+func TestRotationOptionsCantBeNegative(t *testing.T) {
+	err := rotatingFileConfiguration("app.log", log.RotationOptions{MaxSize: -1}).Validate()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rotation options of app.log can't be negative")
+}
@@ -0,0 +1,163 @@
+package log
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type runtimeStatsOptions struct {
+	enabled  bool
+	interval time.Duration
+	metrics  bool
+}
+
+// WithRuntimeStats logs a "Runtime stats" record with the goroutine count, heap allocation, last GC pause and RSS
+// of the process every interval or, when interval is 0, at the end of every invocation timed by StartInvocationTimer,
+// so the memory growth of long lived execution environments, e.g. with provisioned concurrency, is observable.
+func (c Configuration) WithRuntimeStats(interval time.Duration) Configuration {
+	c.runtimeStats = runtimeStatsOptions{enabled: true, interval: interval}
+	return c
+}
+
+// WithRuntimeStatsMetrics behaves like WithRuntimeStats but emits the stats as EMF metrics when the metrics package
+// is imported, see RegisterRuntimeStatsEmitter, they are logged otherwise.
+func (c Configuration) WithRuntimeStatsMetrics(interval time.Duration) Configuration {
+	c.runtimeStats = runtimeStatsOptions{enabled: true, interval: interval, metrics: true}
+	return c
+}
+
+// RuntimeStats is a snapshot of the Go runtime of the process.
+type RuntimeStats struct {
+	Goroutines int
+	HeapAlloc  uint64
+	GCPause    time.Duration
+	NumGC      uint32
+	// RSS is the resident set size in bytes, 0 where /proc isn't available.
+	RSS uint64
+}
+
+// ReadRuntimeStats returns the current RuntimeStats, it briefly stops the world like runtime.ReadMemStats.
+func ReadRuntimeStats() RuntimeStats {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	stats := RuntimeStats{
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  memStats.HeapAlloc,
+		NumGC:      memStats.NumGC,
+		RSS:        residentSetSize(),
+	}
+	if memStats.NumGC > 0 {
+		stats.GCPause = time.Duration(memStats.PauseNs[(memStats.NumGC+255)%256])
+	}
+	return stats
+}
+
+// residentSetSize reads the second field of /proc/self/statm, the resident pages.
+func residentSetSize() uint64 {
+	statm, err := ioutil.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return pages * uint64(os.Getpagesize())
+}
+
+// LogRuntimeStats logs a "Runtime stats" INFO record with the current RuntimeStats.
+func LogRuntimeStats() {
+	logRuntimeStats(ReadRuntimeStats())
+}
+
+func logRuntimeStats(stats RuntimeStats) {
+	fields := []interface{}{
+		RuntimeGoroutines, stats.Goroutines,
+		BytesField(RuntimeHeapAlloc, int64(stats.HeapAlloc)),
+		DurationField(RuntimeGCPause, stats.GCPause),
+		RuntimeNumGC, stats.NumGC,
+	}
+	if stats.RSS > 0 {
+		fields = append(fields, BytesField(RuntimeRSS, int64(stats.RSS)))
+	}
+	logger().Infow("Runtime stats", fields...)
+}
+
+// RegisterRuntimeStatsEmitter sets the function WithRuntimeStatsMetrics reports the stats with,
+// the metrics package registers its EMF emitter when imported.
+func RegisterRuntimeStatsEmitter(emit func(stats RuntimeStats)) {
+	reporter.Lock()
+	defer reporter.Unlock()
+	reporter.emit = emit
+}
+
+var reporter = &runtimeStatsReporter{}
+
+type runtimeStatsReporter struct {
+	sync.Mutex
+	options runtimeStatsOptions
+	emit    func(stats RuntimeStats)
+	stop    chan struct{}
+}
+
+func initRuntimeStats(options runtimeStatsOptions) {
+	reporter.Lock()
+	defer reporter.Unlock()
+	if reporter.stop != nil {
+		close(reporter.stop)
+		reporter.stop = nil
+	}
+	reporter.options = options
+	if options.enabled && options.interval > 0 {
+		stop := make(chan struct{})
+		reporter.stop = stop
+		go reporter.run(options.interval, stop)
+	}
+}
+
+func (r *runtimeStatsReporter) run(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.report()
+		}
+	}
+}
+
+// invocationFinished reports the stats when they are reported at the end of invocations.
+func (r *runtimeStatsReporter) invocationFinished() {
+	r.Lock()
+	atEnd := r.options.enabled && r.options.interval == 0
+	r.Unlock()
+	if atEnd {
+		r.report()
+	}
+}
+
+func (r *runtimeStatsReporter) report() {
+	r.Lock()
+	emit := r.emit
+	if !r.options.metrics {
+		emit = nil
+	}
+	r.Unlock()
+	stats := ReadRuntimeStats()
+	if emit != nil {
+		emit(stats)
+		return
+	}
+	logRuntimeStats(stats)
+}
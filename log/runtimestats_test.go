@@ -0,0 +1,50 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRuntimeStatsAtInvocationEnd(t *testing.T) {
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").WithRuntimeStats(0))
+	recorder := logtest.Capture(t)
+
+	log.StartInvocationTimer(context.Background())()
+
+	recorder.AssertLogged(zapcore.InfoLevel, "Runtime stats")
+	entries := recorder.Entries()
+	fields := entries[len(entries)-1].Fields
+	assert.Greater(t, fields[log.RuntimeGoroutines], int64(0))
+	assert.Greater(t, fields[log.RuntimeHeapAlloc], int64(0))
+	assert.Greater(t, fields[log.RuntimeRSS], int64(0))
+	assert.Contains(t, fields, log.RuntimeGCPause)
+}
+
+func TestRuntimeStatsInterval(t *testing.T) {
+	var buffer syncBuffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithRuntimeStats(10 * time.Millisecond).
+		WithWriters(zapcore.AddSync(&buffer)))
+
+	assert.Eventually(t, func() bool {
+		return strings.Count(buffer.String(), "Runtime stats") >= 2
+	}, time.Second, 5*time.Millisecond)
+
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)))
+	time.Sleep(20 * time.Millisecond)
+	count := strings.Count(buffer.String(), "Runtime stats")
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, count, strings.Count(buffer.String(), "Runtime stats"))
+}
+
+func TestRuntimeStatsNegativeInterval(t *testing.T) {
+	err := log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").WithRuntimeStats(-time.Second).Validate()
+	assert.EqualError(t, err, "invalid log configuration:\nruntime stats interval can't be negative, got -1s")
+}
@@ -0,0 +1,189 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+const (
+	defaultSamplingInitial    = 100
+	defaultSamplingThereafter = 100
+)
+
+var sampledLevels = []zapcore.Level{
+	TraceLevel,
+	zapcore.DebugLevel,
+	zapcore.InfoLevel,
+	zapcore.WarnLevel,
+	zapcore.ErrorLevel,
+	zapcore.DPanicLevel,
+	zapcore.PanicLevel,
+	zapcore.FatalLevel,
+}
+
+// sampling logs the first initial records with the same level and message every second,
+// and then every thereafter-th one. A disabled sampling keeps every record.
+type sampling struct {
+	disabled   bool
+	initial    int
+	thereafter int
+}
+
+type samplingOptions struct {
+	configured bool
+	sampling   sampling
+	levels     map[zapcore.Level]sampling
+}
+
+func (o samplingOptions) forLevel(level zapcore.Level) sampling {
+	if s, ok := o.levels[level]; ok {
+		return s
+	}
+	if o.configured {
+		return o.sampling
+	}
+	return sampling{initial: defaultSamplingInitial, thereafter: defaultSamplingThereafter}
+}
+
+func (o samplingOptions) withLevel(level zapcore.Level, s sampling) samplingOptions {
+	levels := make(map[zapcore.Level]sampling, len(o.levels)+1)
+	for l, ls := range o.levels {
+		levels[l] = ls
+	}
+	levels[level] = s
+	o.levels = levels
+	return o
+}
+
+// WithSampling replaces the default sampling (100 initial, 100 thereafter) for all levels without a specific one.
+func (c Configuration) WithSampling(initial, thereafter int) Configuration {
+	c.sampling.configured = true
+	c.sampling.sampling = sampling{initial: initial, thereafter: thereafter}
+	return c
+}
+
+func (c Configuration) WithoutSampling() Configuration {
+	c.sampling.configured = true
+	c.sampling.sampling = sampling{disabled: true}
+	return c
+}
+
+// WithLevelSampling sets the sampling of a single level, e.g. to sample DEBUG more aggressively.
+func (c Configuration) WithLevelSampling(level zapcore.Level, initial, thereafter int) Configuration {
+	c.sampling = c.sampling.withLevel(level, sampling{initial: initial, thereafter: thereafter})
+	return c
+}
+
+// WithoutLevelSampling keeps every record of a level, e.g. to never drop ERROR records.
+func (c Configuration) WithoutLevelSampling(level zapcore.Level) Configuration {
+	c.sampling = c.sampling.withLevel(level, sampling{disabled: true})
+	return c
+}
+
+// newSampledCore builds one core per distinct sampling, each one enabled only for the levels sharing it,
+// so the sampling of a level never affects the records of the others.
+func newSampledCore(config Configuration, logLevel zapcore.LevelEnabler, newCore func(zapcore.LevelEnabler) zapcore.Core) zapcore.Core {
+	if config.development {
+		return newCore(logLevel)
+	}
+	var order []sampling
+	levels := map[sampling][]zapcore.Level{}
+	for _, level := range sampledLevels {
+		s := config.sampling.forLevel(level)
+		if _, exists := levels[s]; !exists {
+			order = append(order, s)
+		}
+		levels[s] = append(levels[s], level)
+	}
+	if len(order) == 1 {
+		return wrapSampler(newCore(logLevel), order[0])
+	}
+	router := &levelRouterCore{LevelEnabler: logLevel, routes: map[zapcore.Level]int{}}
+	for i, s := range order {
+		router.cores = append(router.cores, wrapSampler(newCore(levelSetEnabler(logLevel, levels[s])), s))
+		for _, level := range levels[s] {
+			router.routes[level] = i
+		}
+	}
+	return router
+}
+
+func wrapSampler(core zapcore.Core, s sampling) zapcore.Core {
+	if s.disabled {
+		return core
+	}
+	return &traceBypassCore{Core: zapcore.NewSampler(core, time.Second, s.initial, s.thereafter), unsampled: core}
+}
+
+// traceBypassCore keeps TRACE records out of the zap sampler, which only counts the zap levels.
+// They are never sampled.
+type traceBypassCore struct {
+	zapcore.Core
+	unsampled zapcore.Core
+}
+
+func (c *traceBypassCore) With(fields []zapcore.Field) zapcore.Core {
+	return &traceBypassCore{Core: c.Core.With(fields), unsampled: c.unsampled.With(fields)}
+}
+
+func (c *traceBypassCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < zapcore.DebugLevel {
+		return c.unsampled.Check(entry, checked)
+	}
+	return c.Core.Check(entry, checked)
+}
+
+func levelSetEnabler(logLevel zapcore.LevelEnabler, levels []zapcore.Level) zap.LevelEnablerFunc {
+	return func(level zapcore.Level) bool {
+		if !logLevel.Enabled(level) {
+			return false
+		}
+		for _, l := range levels {
+			if l == level {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// levelRouterCore sends every record to the single core handling its level. Unlike a tee it is also safe
+// to Write to directly, which wrapping cores do once they have checked a record.
+type levelRouterCore struct {
+	zapcore.LevelEnabler
+	cores  []zapcore.Core
+	routes map[zapcore.Level]int
+}
+
+func (c *levelRouterCore) With(fields []zapcore.Field) zapcore.Core {
+	cores := make([]zapcore.Core, len(c.cores))
+	for i, core := range c.cores {
+		cores[i] = core.With(fields)
+	}
+	return &levelRouterCore{LevelEnabler: c.LevelEnabler, cores: cores, routes: c.routes}
+}
+
+func (c *levelRouterCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if i, ok := c.routes[entry.Level]; ok {
+		return c.cores[i].Check(entry, checked)
+	}
+	return checked
+}
+
+func (c *levelRouterCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if i, ok := c.routes[entry.Level]; ok {
+		return c.cores[i].Write(entry, fields)
+	}
+	return nil
+}
+
+func (c *levelRouterCore) Sync() error {
+	var err error
+	for _, core := range c.cores {
+		if syncErr := core.Sync(); syncErr != nil && err == nil {
+			err = syncErr
+		}
+	}
+	return err
+}
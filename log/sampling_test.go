@@ -0,0 +1,55 @@
+package log_test
+
+import (
+	"bytes"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+)
+
+func TestLevelSampling(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithSampling(5, 1000).
+		WithLevelSampling(zapcore.DebugLevel, 1, 1000).
+		WithoutLevelSampling(zapcore.ErrorLevel)
+	log.Init(config)
+	for i := 0; i < 20; i++ {
+		log.Debug("Sampled debug msg")
+		log.Info("Sampled info msg")
+		log.Error("Never dropped error msg")
+	}
+
+	output := buffer.String()
+	assert.Equal(t, 1, strings.Count(output, "Sampled debug msg"))
+	assert.Equal(t, 5, strings.Count(output, "Sampled info msg"))
+	assert.Equal(t, 20, strings.Count(output, "Never dropped error msg"))
+}
+
+func TestWithoutSampling(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithoutSampling()
+	log.Init(config)
+	for i := 0; i < 150; i++ {
+		log.Info("Unsampled info msg")
+	}
+
+	assert.Equal(t, 150, strings.Count(buffer.String(), "Unsampled info msg"))
+}
@@ -0,0 +1,211 @@
+package log
+
+import (
+	"encoding"
+	"encoding/json"
+	"go.uber.org/zap/zapcore"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SchemaType is the json type of a field declared in a Schema.
+type SchemaType string
+
+const (
+	SchemaString SchemaType = "string"
+	SchemaNumber SchemaType = "number"
+	SchemaBool   SchemaType = "boolean"
+	SchemaObject SchemaType = "object"
+	SchemaArray  SchemaType = "array"
+	// SchemaAny accepts any type, for fields whose type can't be known in advance.
+	SchemaAny SchemaType = "any"
+)
+
+// Schema maps the names of the fields the records may hold to their types. A name ending in ".*" declares
+// every field under it, e.g. "Body.testPrefix.*" for the custom attributes, the longest match wins.
+type Schema map[string]SchemaType
+
+// WithSchema checks the fields of every record written against schema and logs a "Log record schema drift" WARN record
+// the first time an undeclared field, or a field of another type, shows up, so the records a strict ingestion mapping
+// would reject are noticed before they are lost. The resource fields of the configuration are always accepted.
+func (c Configuration) WithSchema(schema Schema) Configuration {
+	c.schema = schema
+	return c
+}
+
+var schemaImplicitFields = Schema{
+	Application:    SchemaString,
+	Project:        SchemaString,
+	ProjectGroup:   SchemaString,
+	Version:        SchemaString,
+	GitSha:         SchemaString,
+	DeploymentId:   SchemaString,
+	SchemaVersion:  SchemaNumber,
+	SchemaField:    SchemaString,
+	SchemaExpected: SchemaString,
+	SchemaActual:   SchemaString,
+}
+
+var drift = &schemaDrift{}
+
+type schemaDrift struct {
+	sync.Mutex
+	schema     Schema
+	reported   map[string]bool
+	unregister func()
+}
+
+type schemaViolation struct {
+	field    string
+	expected SchemaType
+	actual   SchemaType
+}
+
+func initSchema(schema Schema) {
+	drift.Lock()
+	defer drift.Unlock()
+	if drift.unregister != nil {
+		drift.unregister()
+		drift.unregister = nil
+	}
+	drift.schema = schema
+	drift.reported = map[string]bool{}
+	if schema != nil {
+		drift.unregister = RegisterHook(func(entry zapcore.Entry, fields []zapcore.Field) error {
+			drift.check(fields)
+			return nil
+		})
+	}
+}
+
+func (d *schemaDrift) check(fields []zapcore.Field) {
+	var violations []schemaViolation
+	d.Lock()
+	for _, field := range fields {
+		actual, ok := schemaTypeOf(field)
+		if !ok {
+			continue
+		}
+		expected, declared := d.schema.lookup(field.Key)
+		if !declared {
+			expected, declared = schemaImplicitFields.lookup(field.Key)
+		}
+		if declared && (expected == SchemaAny || actual == SchemaAny || expected == actual) {
+			continue
+		}
+		key := field.Key + "\x00" + string(actual)
+		if d.reported[key] {
+			continue
+		}
+		d.reported[key] = true
+		violations = append(violations, schemaViolation{field: field.Key, expected: expected, actual: actual})
+	}
+	d.Unlock()
+
+	for _, violation := range violations {
+		expected := string(violation.expected)
+		if expected == "" {
+			expected = "undeclared"
+		}
+		logger().Warnw("Log record schema drift",
+			SchemaField, violation.field,
+			SchemaExpected, expected,
+			SchemaActual, string(violation.actual))
+	}
+}
+
+// lookup returns the type of name, declared by itself or by the longest pattern matching it.
+func (s Schema) lookup(name string) (SchemaType, bool) {
+	if schemaType, ok := s[name]; ok {
+		return schemaType, true
+	}
+	for prefix := name; ; {
+		i := strings.LastIndex(prefix, ".")
+		if i < 0 {
+			return "", false
+		}
+		prefix = prefix[:i]
+		if schemaType, ok := s[prefix+".*"]; ok {
+			return schemaType, true
+		}
+	}
+}
+
+// schemaTypeOf returns the json type field is encoded as, fields that aren't encoded are skipped.
+func schemaTypeOf(field zapcore.Field) (SchemaType, bool) {
+	switch field.Type {
+	case zapcore.SkipType:
+		return "", false
+	case zapcore.StringType, zapcore.StringerType, zapcore.ByteStringType, zapcore.TimeType, zapcore.ErrorType,
+		zapcore.BinaryType, zapcore.Complex64Type, zapcore.Complex128Type:
+		return SchemaString, true
+	case zapcore.BoolType:
+		return SchemaBool, true
+	case zapcore.ObjectMarshalerType, zapcore.NamespaceType:
+		return SchemaObject, true
+	case zapcore.ArrayMarshalerType:
+		return SchemaArray, true
+	case zapcore.ReflectType:
+		return reflectSchemaType(field.Interface), true
+	}
+	return SchemaNumber, true
+}
+
+func reflectSchemaType(value interface{}) SchemaType {
+	if value == nil {
+		return SchemaAny
+	}
+	switch marshaler := value.(type) {
+	case json.Marshaler:
+		encoded, err := marshaler.MarshalJSON()
+		if err != nil || len(encoded) == 0 {
+			return SchemaAny
+		}
+		return jsonSchemaType(encoded[0])
+	case encoding.TextMarshaler:
+		return SchemaString
+	}
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return SchemaAny
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return SchemaString
+	case reflect.Bool:
+		return SchemaBool
+	case reflect.Map, reflect.Struct:
+		return SchemaObject
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return SchemaString
+		}
+		return SchemaArray
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return SchemaNumber
+	}
+	return SchemaAny
+}
+
+// jsonSchemaType returns the type of the json value starting with first.
+func jsonSchemaType(first byte) SchemaType {
+	switch first {
+	case '"':
+		return SchemaString
+	case '{':
+		return SchemaObject
+	case '[':
+		return SchemaArray
+	case 't', 'f':
+		return SchemaBool
+	case 'n':
+		return SchemaAny
+	}
+	return SchemaNumber
+}
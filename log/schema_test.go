@@ -0,0 +1,46 @@
+package log_test
+
+import (
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWithSchema(t *testing.T) {
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "testPrefix").
+		WithSchema(log.Schema{
+			"orderId":           log.SchemaString,
+			"amount":            log.SchemaNumber,
+			"Body.testPrefix.*": log.SchemaAny,
+		}))
+	recorder := logtest.Capture(t)
+
+	log.InfoW("Order paid", "orderId", "1", "amount", 12.5, "Body.testPrefix.channel", []string{"web"})
+	log.InfoW("Order paid", "orderId", 2, "amount", 10, "coupon", "SUMMER")
+	log.InfoW("Order paid", "orderId", 3, "coupon", "WINTER")
+
+	var drifts []map[string]interface{}
+	for _, entry := range recorder.Entries() {
+		if entry.Message == "Log record schema drift" {
+			drifts = append(drifts, map[string]interface{}{
+				log.SchemaField:    entry.Fields[log.SchemaField],
+				log.SchemaExpected: entry.Fields[log.SchemaExpected],
+				log.SchemaActual:   entry.Fields[log.SchemaActual],
+			})
+		}
+	}
+	assert.Equal(t, []map[string]interface{}{
+		{log.SchemaField: "orderId", log.SchemaExpected: "string", log.SchemaActual: "number"},
+		{log.SchemaField: "coupon", log.SchemaExpected: "undeclared", log.SchemaActual: "string"},
+	}, drifts)
+}
+
+func TestWithoutSchema(t *testing.T) {
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "testPrefix"))
+	recorder := logtest.Capture(t)
+
+	log.InfoW("Order paid", "orderId", 2)
+
+	assert.Len(t, recorder.Entries(), 1)
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"encoding/json"
+)
+
+// Masked replaces the values of Secret and Sensitive in every encoding.
+const Masked = "***"
+
+// revealedRunes is the number of trailing runes Last4 reveals, secrets shorter than twice as long are fully masked.
+const revealedRunes = 4
+
+// Secret is a string that is always logged as Masked, by the loggers of this package, fmt and encoding/json,
+// so a secret passed by mistake as a field value never reaches the outputs even when no redaction rule matches it.
+type Secret string
+
+func (s Secret) String() string {
+	return Masked
+}
+
+func (s Secret) GoString() string {
+	return Masked
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Masked)
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Masked), nil
+}
+
+// Last4 returns s masked but its last 4 runes, e.g. "***4242" for a card number, so it can still be told apart.
+// Secrets shorter than 8 runes are fully masked.
+func (s Secret) Last4() PartialSecret {
+	runes := []rune(string(s))
+	if len(runes) < 2*revealedRunes {
+		return PartialSecret(Masked)
+	}
+	return PartialSecret(Masked + string(runes[len(runes)-revealedRunes:]))
+}
+
+// PartialSecret is a masked secret revealing its last runes, see Secret.Last4.
+type PartialSecret string
+
+func (s PartialSecret) String() string {
+	return string(s)
+}
+
+// Sensitive wraps value, of any type, so it's always logged as Masked, see Secret.
+func Sensitive(value interface{}) SensitiveValue {
+	return SensitiveValue{value: value}
+}
+
+// SensitiveValue is a value wrapped by Sensitive, Value returns it unmasked.
+type SensitiveValue struct {
+	value interface{}
+}
+
+func (s SensitiveValue) Value() interface{} {
+	return s.value
+}
+
+func (s SensitiveValue) String() string {
+	return Masked
+}
+
+func (s SensitiveValue) GoString() string {
+	return Masked
+}
+
+func (s SensitiveValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Masked)
+}
+
+func (s SensitiveValue) MarshalText() ([]byte, error) {
+	return []byte(Masked), nil
+}
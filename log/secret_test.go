@@ -0,0 +1,53 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+type credentials struct {
+	User     string
+	Password log.Secret
+}
+
+func TestSecret(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)))
+	password := log.Secret("hunter22")
+
+	log.InfoW("Connecting",
+		"password", password,
+		"card", log.Secret("4242424242424242").Last4(),
+		"pin", log.Secret("1234").Last4(),
+		"credentials", credentials{User: "jane", Password: password},
+		"token", log.Sensitive(map[string]string{"access": "abc"}))
+	log.Info("Connecting with %v %s %#v", password, password, password)
+
+	output := buffer.String()
+	assert.Contains(t, output, `"password":"***"`)
+	assert.Contains(t, output, `"card":"***4242"`)
+	assert.Contains(t, output, `"pin":"***"`)
+	assert.Contains(t, output, `"credentials":{"User":"jane","Password":"***"}`)
+	assert.Contains(t, output, `"token":"***"`)
+	assert.Contains(t, output, `"Connecting with *** *** ***"`)
+	assert.NotContains(t, output, "hunter22")
+	assert.NotContains(t, output, "abc")
+}
+
+func TestSensitiveValue(t *testing.T) {
+	value := log.Sensitive(42)
+
+	encoded, err := json.Marshal(map[string]interface{}{"value": value})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"value":"***"}`, string(encoded))
+	assert.Equal(t, "***", fmt.Sprint(value))
+	assert.Equal(t, 42, value.Value())
+	assert.Equal(t, "hunter22", string(log.Secret("hunter22")))
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"strings"
+)
+
+// SetUpSes attaches the fields of the mail to the invocation, SES invokes functions with a single record.
+func SetUpSes(ctx context.Context, event events.SimpleEmailEvent) context.Context {
+	if len(event.Records) == 1 {
+		return SetUpSesRecord(ctx, event.Records[0])
+	}
+	ctx = SetupTraceIds(ctx)
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, "ses")
+	}
+	return ctx
+}
+
+// SetUpSesRecord attaches the message id, source, hashed recipients and receipt verdicts of the mail to the invocation,
+// plus the bucket and key the mail was stored to by an S3 action. The event body is never logged, it holds
+// addresses and subjects.
+func SetUpSesRecord(ctx context.Context, record events.SimpleEmailRecord) context.Context {
+	ctx = SetupTraceIds(ctx)
+	mail, receipt := record.SES.Mail, record.SES.Receipt
+	recipientHashes := make([]string, len(receipt.Recipients))
+	for i, recipient := range receipt.Recipients {
+		recipientHashes[i] = hashValue(strings.ToLower(recipient))
+	}
+	ctx = withSetUpFields(ctx,
+		MessageId, mail.MessageID,
+		MailSource, mail.Source,
+		RecipientHashes, recipientHashes,
+		SpamVerdict, receipt.SpamVerdict.Status,
+		VirusVerdict, receipt.VirusVerdict.Status,
+		SpfVerdict, receipt.SPFVerdict.Status,
+		DkimVerdict, receipt.DKIMVerdict.Status,
+		DmarcVerdict, receipt.DMARCVerdict.Status)
+	if receipt.Action.Type == "S3" {
+		ctx = withSetUpFields(ctx,
+			BucketName, receipt.Action.BucketName,
+			ObjectKey, receipt.Action.ObjectKey)
+	}
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, record.EventSource)
+	}
+	return ctx
+}
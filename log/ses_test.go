@@ -0,0 +1,50 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetUpSes(t *testing.T) {
+	recorder := logtest.Capture(t)
+	log.SetUp(context.Background(), events.SimpleEmailEvent{Records: []events.SimpleEmailRecord{{
+		EventSource: "aws:ses",
+		SES: events.SimpleEmailService{
+			Mail: events.SimpleEmailMessage{
+				MessageID: "message-1",
+				Source:    "sender@example.com",
+				CommonHeaders: events.SimpleEmailCommonHeaders{
+					Subject: "Private subject",
+				},
+			},
+			Receipt: events.SimpleEmailReceipt{
+				Recipients:   []string{"Inbox@example.com"},
+				SpamVerdict:  events.SimpleEmailVerdict{Status: "PASS"},
+				VirusVerdict: events.SimpleEmailVerdict{Status: "PASS"},
+				SPFVerdict:   events.SimpleEmailVerdict{Status: "FAIL"},
+				DKIMVerdict:  events.SimpleEmailVerdict{Status: "GRAY"},
+				Action: events.SimpleEmailReceiptAction{
+					Type:       "S3",
+					BucketName: "inbound-mail",
+					ObjectKey:  "mail/message-1",
+				},
+			},
+		},
+	}}})
+	log.Info("Processing mail")
+	log.ResetInvocation()
+
+	recorder.AssertField(log.MessageId, "message-1")
+	recorder.AssertField(log.MailSource, "sender@example.com")
+	recorder.AssertField(log.SpfVerdict, "FAIL")
+	recorder.AssertField(log.DkimVerdict, "GRAY")
+	recorder.AssertField(log.BucketName, "inbound-mail")
+	fields := log.ToString(recorder.Entries()[0].Fields)
+	assert.Contains(t, fields, log.HashUserName("inbox@example.com"))
+	assert.NotContains(t, fields, "Inbox@example.com")
+	assert.NotContains(t, fields, "Private subject")
+}
@@ -0,0 +1,55 @@
+package log
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+	"strconv"
+	"sync"
+)
+
+// BatchResult collects the messages of an SQS batch that failed to be processed, so only those are retried
+// when the event source mapping reports batch item failures. It is safe to use from several goroutines.
+type BatchResult struct {
+	mutex    sync.Mutex
+	failures []events.SQSBatchItemFailure
+}
+
+func NewBatchResult() *BatchResult {
+	return &BatchResult{}
+}
+
+// Fail logs the failure of message with the logger of ctx and reports it in the response.
+func (r *BatchResult) Fail(ctx context.Context, message events.SQSMessage, err error) {
+	keysAndValues := []interface{}{MessageId, message.MessageId}
+	if receiveCount, ok := sqsReceiveCount(message); ok {
+		keysAndValues = append(keysAndValues, ReceiveCount, receiveCount)
+	}
+	FromContext(ctx).Desugar().WithOptions(zap.AddCallerSkip(1)).Sugar().
+		Errorw("Failed to process message", append(keysAndValues, ErrorFields(err)...)...)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.failures = append(r.failures, events.SQSBatchItemFailure{ItemIdentifier: message.MessageId})
+}
+
+// Failed returns the number of messages reported so far.
+func (r *BatchResult) Failed() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.failures)
+}
+
+// Response returns the partial batch response to return from the handler.
+func (r *BatchResult) Response() events.SQSEventResponse {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return events.SQSEventResponse{
+		BatchItemFailures: append([]events.SQSBatchItemFailure{}, r.failures...),
+	}
+}
+
+func sqsReceiveCount(message events.SQSMessage) (int, bool) {
+	receiveCount, err := strconv.Atoi(message.Attributes["ApproximateReceiveCount"])
+	return receiveCount, err == nil
+}
@@ -0,0 +1,30 @@
+package log_test
+
+import (
+	"context"
+	"errors"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func TestBatchResult(t *testing.T) {
+	recorder := logtest.Capture(t)
+	result := log.NewBatchResult()
+	assert.Equal(t, events.SQSEventResponse{BatchItemFailures: []events.SQSBatchItemFailure{}}, result.Response())
+
+	result.Fail(context.Background(), events.SQSMessage{
+		MessageId:  "message-1",
+		Attributes: map[string]string{"ApproximateReceiveCount": "3"},
+	}, errors.New("boom"))
+
+	assert.Equal(t, 1, result.Failed())
+	assert.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "message-1"}}, result.Response().BatchItemFailures)
+	recorder.AssertLogged(zapcore.ErrorLevel, "Failed to process message")
+	recorder.AssertField(log.MessageId, "message-1")
+	recorder.AssertField(log.ReceiveCount, 3)
+	recorder.AssertField(log.ErrorMessage, "boom")
+}
@@ -0,0 +1,86 @@
+package log
+
+import (
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// loggerState is the state of the package logger. It's never modified once published, every change publishes
+// a modified copy, so it's read without locks by the logging functions while With, the SetUp* helpers and
+// ResetInvocation run on other goroutines.
+type loggerState struct {
+	// logger is base with the invocation fields, fast is logger desugared for the typed API.
+	logger           *zap.SugaredLogger
+	fast             *zap.Logger
+	base             *zap.SugaredLogger
+	invocationFields []zap.Field
+	config           Configuration
+	level            zap.AtomicLevel
+	// emit and audit are the loggers behind Emit and Audit.
+	emit  *zap.Logger
+	audit *zap.Logger
+	// recordBase is the invocation fields set up before the first record of the invocation, nil until then.
+	recordBase []zap.Field
+}
+
+var (
+	state             atomic.Value
+	updateMutex       sync.Mutex
+	initialized       int32
+	defaultLoggerOnce sync.Once
+)
+
+// loadState returns the current state, nil until the package logger is initialized.
+func loadState() *loggerState {
+	current, _ := state.Load().(*loggerState)
+	return current
+}
+
+// packageState returns the current state, initializing the default logger when Init wasn't called yet.
+func packageState() *loggerState {
+	if current := loadState(); current != nil {
+		return current
+	}
+	defaultLoggerOnce.Do(func() {
+		if loadState() == nil {
+			config := NewConfiguration("INFO", lambdacontext.FunctionName, "", "", "", "")
+			initLogger(config, zap.NewAtomicLevelAt(zap.InfoLevel), zapcore.Lock(os.Stderr), nil)
+		}
+	})
+	return loadState()
+}
+
+func storeState(next *loggerState) {
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+	state.Store(next)
+}
+
+// updateState publishes the copy of the current state modified by update, concurrent updates are applied one
+// after the other so none of them is lost.
+func updateState(update func(next *loggerState)) {
+	packageState()
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+	next := *loadState()
+	update(&next)
+	state.Store(&next)
+}
+
+// withLogger sets the logger of s from its base and invocation fields.
+func (s *loggerState) withLogger() {
+	s.setLogger(s.base.Desugar().With(s.invocationFields...).Sugar())
+}
+
+func (s *loggerState) setLogger(logger *zap.SugaredLogger) {
+	s.logger = logger
+	s.fast = logger.Desugar()
+}
+
+func setInitialized() {
+	atomic.StoreInt32(&initialized, 1)
+}
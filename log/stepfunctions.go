@@ -0,0 +1,116 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var errNoStepFunctionsContext = errors.New("no step functions context in payload")
+
+// StepFunctionsContext is the context object of a state machine task, passed to the function
+// with a "Context.$": "$$" parameter.
+type StepFunctionsContext struct {
+	Execution    StepFunctionsExecution    `json:"Execution"`
+	State        StepFunctionsState        `json:"State"`
+	StateMachine StepFunctionsStateMachine `json:"StateMachine"`
+	Task         StepFunctionsTask         `json:"Task"`
+}
+
+type StepFunctionsExecution struct {
+	Id   string `json:"Id"`
+	Name string `json:"Name"`
+}
+
+type StepFunctionsState struct {
+	Name       string `json:"Name"`
+	RetryCount int    `json:"RetryCount"`
+}
+
+type StepFunctionsStateMachine struct {
+	Id   string `json:"Id"`
+	Name string `json:"Name"`
+}
+
+type StepFunctionsTask struct {
+	Token string `json:"Token"`
+}
+
+// stepFunctionsPayload holds the fields commonly passed along a task token,
+// e.g. "ExecutionArn.$": "$$.Execution.Id".
+type stepFunctionsPayload struct {
+	ExecutionArn     string `json:"ExecutionArn"`
+	StateMachineName string `json:"StateMachineName"`
+	StateName        string `json:"StateName"`
+	TaskToken        string `json:"TaskToken"`
+}
+
+// SetUpStepFunctions attaches the execution, state machine and state of the task to the invocation.
+// The task token is never logged, anyone holding it can complete the task.
+func SetUpStepFunctions(ctx context.Context, sfnContext StepFunctionsContext) context.Context {
+	ctx = SetupTraceIds(ctx)
+	ctx = withSetUpFields(ctx,
+		ExecutionArn, sfnContext.Execution.Id,
+		ExecutionName, sfnContext.Execution.Name,
+		StateMachineName, sfnContext.StateMachine.Name,
+		StateName, sfnContext.State.Name,
+		RetryCount, sfnContext.State.RetryCount)
+	if IsDebugEnabled() {
+		DebugW("Got event",
+			EventSource, "stepfunctions")
+	}
+	return ctx
+}
+
+// StepFunctionsContextFromPayload extracts the context object from a task input, either the whole input,
+// its "Context" field or the ExecutionArn, StateMachineName, StateName and TaskToken fields of a task token payload.
+// Names missing from the payload are taken from the execution arn.
+func StepFunctionsContextFromPayload(payload []byte) (StepFunctionsContext, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &document); err != nil {
+		return StepFunctionsContext{}, err
+	}
+	for _, key := range []string{"Context", "context"} {
+		if nested, ok := document[key]; ok {
+			if sfnContext, err := StepFunctionsContextFromPayload(nested); err == nil {
+				return sfnContext, nil
+			}
+		}
+	}
+
+	var sfnContext StepFunctionsContext
+	if _, ok := document["Execution"]; ok {
+		if err := json.Unmarshal(payload, &sfnContext); err != nil {
+			return StepFunctionsContext{}, err
+		}
+	} else {
+		var flat stepFunctionsPayload
+		if err := json.Unmarshal(payload, &flat); err != nil {
+			return StepFunctionsContext{}, err
+		}
+		if flat.ExecutionArn == "" && flat.TaskToken == "" {
+			return StepFunctionsContext{}, errNoStepFunctionsContext
+		}
+		sfnContext.Execution.Id = flat.ExecutionArn
+		sfnContext.StateMachine.Name = flat.StateMachineName
+		sfnContext.State.Name = flat.StateName
+		sfnContext.Task.Token = flat.TaskToken
+	}
+	fillStepFunctionsNames(&sfnContext)
+	return sfnContext, nil
+}
+
+// execution arns look like arn:aws:states:region:account:execution:stateMachineName:executionName
+func fillStepFunctionsNames(sfnContext *StepFunctionsContext) {
+	parts := strings.Split(sfnContext.Execution.Id, ":")
+	if len(parts) < 8 || parts[5] != "execution" {
+		return
+	}
+	if sfnContext.StateMachine.Name == "" {
+		sfnContext.StateMachine.Name = parts[6]
+	}
+	if sfnContext.Execution.Name == "" {
+		sfnContext.Execution.Name = parts[7]
+	}
+}
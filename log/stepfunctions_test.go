@@ -0,0 +1,52 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const executionArn = "arn:aws:states:eu-west-1:123456789012:execution:BookingFlow:execution-1"
+
+func TestStepFunctionsContextFromPayload(t *testing.T) {
+	sfnContext, err := log.StepFunctionsContextFromPayload([]byte(`{
+		"bookingId": "1",
+		"Context": {
+			"Execution": {"Id": "` + executionArn + `", "Name": "execution-1"},
+			"State": {"Name": "ConfirmBooking", "RetryCount": 2},
+			"StateMachine": {"Id": "arn:aws:states:eu-west-1:123456789012:stateMachine:BookingFlow", "Name": "BookingFlow"},
+			"Task": {"Token": "token"}
+		}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "ConfirmBooking", sfnContext.State.Name)
+	assert.Equal(t, 2, sfnContext.State.RetryCount)
+	assert.Equal(t, "BookingFlow", sfnContext.StateMachine.Name)
+
+	sfnContext, err = log.StepFunctionsContextFromPayload([]byte(`{"ExecutionArn": "` + executionArn + `", "StateName": "WaitForPayment", "TaskToken": "token"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "BookingFlow", sfnContext.StateMachine.Name)
+	assert.Equal(t, "execution-1", sfnContext.Execution.Name)
+	assert.Equal(t, "WaitForPayment", sfnContext.State.Name)
+	assert.Equal(t, "token", sfnContext.Task.Token)
+
+	_, err = log.StepFunctionsContextFromPayload([]byte(`{"bookingId": "1"}`))
+	assert.Error(t, err)
+}
+
+func TestSetUpStepFunctions(t *testing.T) {
+	recorder := logtest.Capture(t)
+	sfnContext, err := log.StepFunctionsContextFromPayload([]byte(`{"ExecutionArn": "` + executionArn + `", "StateName": "WaitForPayment", "TaskToken": "token"}`))
+	assert.NoError(t, err)
+	log.SetUp(context.Background(), sfnContext)
+	log.Info("Waiting for payment")
+
+	recorder.AssertField(log.ExecutionArn, executionArn)
+	recorder.AssertField(log.StateMachineName, "BookingFlow")
+	recorder.AssertField(log.StateName, "WaitForPayment")
+	for _, entry := range recorder.Entries() {
+		assert.NotContains(t, log.ToString(entry.Fields), "token")
+	}
+}
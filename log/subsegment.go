@@ -0,0 +1,52 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Trace runs fn within a new span of ctx, an X-Ray subsegment by default, see SetUpTracing, ended with the error
+// fn returns. Failures are logged by the logger of ctx with the span id and duration. Without a trace to attach to,
+// fn still runs with ctx.
+func Trace(ctx context.Context, name string, fn func(context.Context) error) error {
+	_, err := TraceCapture(ctx, name, func(ctx context.Context) (interface{}, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
+
+// TraceCapture behaves like Trace for functions returning a value. Panics are recorded and logged as failures
+// before being re-panicked.
+func TraceCapture(ctx context.Context, name string, fn func(context.Context) (interface{}, error)) (result interface{}, err error) {
+	start := time.Now()
+	spanCtx, span := tracingProvider().StartSpan(ctx, name)
+	mirrorContext(ctx, span)
+	defer func() {
+		recovered := recover()
+		if recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+		if span != nil {
+			span.End(err)
+		}
+		if err != nil {
+			logSubsegmentFailure(ctx, name, span, time.Since(start), err)
+		}
+		if recovered != nil {
+			panic(recovered)
+		}
+	}()
+	return fn(spanCtx)
+}
+
+func logSubsegmentFailure(ctx context.Context, name string, span Span, duration time.Duration, err error) {
+	keysAndValues := []interface{}{
+		SubsegmentName, name,
+		SubsegmentDuration, duration,
+	}
+	if span != nil {
+		keysAndValues = append(keysAndValues, SubsegmentId, span.Id())
+	}
+	FromContext(ctx).Errorw("Subsegment failed", append(keysAndValues, ErrorFields(err)...)...)
+}
@@ -0,0 +1,48 @@
+package log_test
+
+import (
+	"context"
+	"errors"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	recorder := logtest.Capture(t)
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+
+	var subsegmentId string
+	err := log.Trace(ctx, "load-booking", func(ctx context.Context) error {
+		subsegmentId = xray.GetSegment(ctx).ID
+		return errors.New("booking not found")
+	})
+
+	assert.EqualError(t, err, "booking not found")
+	assert.NotEmpty(t, subsegmentId)
+	recorder.AssertLogged(zapcore.ErrorLevel, "Subsegment failed")
+	recorder.AssertField(log.SubsegmentName, "load-booking")
+	recorder.AssertField(log.SubsegmentId, subsegmentId)
+	recorder.AssertField(log.ErrorMessage, "booking not found")
+}
+
+func TestTraceCaptureWithoutSegment(t *testing.T) {
+	recorder := logtest.Capture(t)
+	result, err := log.TraceCapture(context.Background(), "load-booking", func(ctx context.Context) (interface{}, error) {
+		return "booking-1", nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "booking-1", result)
+	assert.Empty(t, recorder.Entries())
+
+	assert.Panics(t, func() {
+		_, _ = log.TraceCapture(context.Background(), "load-booking", func(ctx context.Context) (interface{}, error) {
+			panic("unexpected")
+		})
+	})
+	recorder.AssertField(log.ErrorMessage, "panic: unexpected")
+}
@@ -0,0 +1,225 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyslogFacility is the facility of the records sent to syslog, as defined by RFC5424.
+type SyslogFacility int
+
+const (
+	FacilityUser   SyslogFacility = 1
+	FacilityDaemon SyslogFacility = 3
+	FacilityLocal0 SyslogFacility = 16
+	FacilityLocal1 SyslogFacility = 17
+	FacilityLocal2 SyslogFacility = 18
+	FacilityLocal3 SyslogFacility = 19
+	FacilityLocal4 SyslogFacility = 20
+	FacilityLocal5 SyslogFacility = 21
+	FacilityLocal6 SyslogFacility = 22
+	FacilityLocal7 SyslogFacility = 23
+)
+
+const (
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+	// sinkWriteTimeout bounds the connections and writes to syslog and journald, a stuck daemon must not block the callers.
+	sinkWriteTimeout = time.Second
+)
+
+// local syslog sockets, tried in order when no address is given
+var syslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogOptions configures the syslog output of WithSyslog.
+type SyslogOptions struct {
+	// Network is udp, tcp, unix or unixgram, empty for the local syslog socket.
+	Network string
+	// Address is the address of the syslog server or the path of its socket.
+	Address string
+	// Facility defaults to FacilityUser.
+	Facility SyslogFacility
+}
+
+// WithSyslog sends every record, besides writing it to the regular output, to syslog as an RFC5424 message
+// holding the json record, for the services running on EC2 and shipping through rsyslog.
+// The levels are mapped to the syslog severities, from debug for TRACE and DEBUG to emerg for FATAL.
+// The connection is opened on the first record and reopened when a write fails, so a restart of the syslog
+// daemon loses no more than the record being sent. Records sent over tcp are framed by octet counting (RFC6587).
+func (c Configuration) WithSyslog(options SyslogOptions) Configuration {
+	c.syslog = &options
+	return c
+}
+
+// recordSink receives the encoded records of a sinkCore.
+type recordSink interface {
+	writeRecord(entry zapcore.Entry, record []byte) error
+	sync() error
+}
+
+// sinkCore encodes the records in json for a recordSink, which needs their level besides the encoded record.
+type sinkCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	sink    recordSink
+}
+
+func newSinkCore(encoder zapcore.Encoder, sink recordSink, enabler zapcore.LevelEnabler) zapcore.Core {
+	return &sinkCore{LevelEnabler: enabler, encoder: encoder, sink: sink}
+}
+
+func (c *sinkCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &sinkCore{LevelEnabler: c.LevelEnabler, encoder: c.encoder.Clone(), sink: c.sink}
+	for _, field := range fields {
+		field.AddTo(clone.encoder)
+	}
+	return clone
+}
+
+func (c *sinkCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *sinkCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buffer, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	defer buffer.Free()
+	return c.sink.writeRecord(entry, bytes.TrimRight(buffer.Bytes(), "\n"))
+}
+
+func (c *sinkCore) Sync() error {
+	return c.sink.sync()
+}
+
+type syslogSink struct {
+	sync.Mutex
+	options  SyslogOptions
+	hostname string
+	appName  string
+	conn     net.Conn
+}
+
+func newSyslogSink(options SyslogOptions, application string) *syslogSink {
+	if options.Facility == 0 {
+		options.Facility = FacilityUser
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &syslogSink{options: options, hostname: syslogHeaderField(hostname, 255), appName: syslogHeaderField(application, 48)}
+}
+
+func (s *syslogSink) writeRecord(entry zapcore.Entry, record []byte) error {
+	priority := int(s.options.Facility)*8 + syslogSeverity(entry.Level)
+	message := fmt.Sprintf("<%d>1 %s %s %s %d - - %s",
+		priority, entry.Time.UTC().Format(syslogTimeFormat), s.hostname, s.appName, os.Getpid(), record)
+	if s.options.Network == "tcp" {
+		message = fmt.Sprintf("%d %s", len(message), message)
+	}
+	s.Lock()
+	defer s.Unlock()
+	// a failed write is retried once on a new connection, the daemon may have been restarted
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if s.conn, err = s.dial(); err != nil {
+				return fmt.Errorf("unable to connect to syslog: %v", err)
+			}
+		}
+		_ = s.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+		if _, err = s.conn.Write([]byte(message)); err == nil {
+			return nil
+		}
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	return fmt.Errorf("unable to write to syslog: %v", err)
+}
+
+func (s *syslogSink) dial() (net.Conn, error) {
+	if s.options.Network != "" {
+		return net.DialTimeout(s.options.Network, s.options.Address, sinkWriteTimeout)
+	}
+	sockets := syslogSockets
+	if s.options.Address != "" {
+		sockets = []string{s.options.Address}
+	}
+	var err error
+	for _, socket := range sockets {
+		for _, network := range []string{"unixgram", "unix"} {
+			var conn net.Conn
+			if conn, err = net.Dial(network, socket); err == nil {
+				return conn, nil
+			}
+		}
+	}
+	return nil, err
+}
+
+func (s *syslogSink) sync() error {
+	return nil
+}
+
+func (o SyslogOptions) validate() error {
+	switch o.Network {
+	case "", "udp", "tcp", "unix", "unixgram":
+	default:
+		return fmt.Errorf("syslog network must be one of udp, tcp, unix or unixgram, got %q", o.Network)
+	}
+	if o.Network != "" && o.Address == "" {
+		return fmt.Errorf("syslog address is required for network %s", o.Network)
+	}
+	if o.Facility < 0 || o.Facility > FacilityLocal7 {
+		return fmt.Errorf("syslog facility must be between 0 and %d, got %d", FacilityLocal7, o.Facility)
+	}
+	return nil
+}
+
+// syslogHeaderField restricts value to the printable ascii characters allowed in the header fields of RFC5424.
+func syslogHeaderField(value string, maxLength int) string {
+	field := strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+	if len(field) > maxLength {
+		field = field[:maxLength]
+	}
+	if field == "" {
+		return "-"
+	}
+	return field
+}
+
+// Severities as defined by RFC5424, shared by syslog and journald
+func syslogSeverity(level zapcore.Level) int {
+	switch level {
+	case TraceLevel, zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel:
+		return 2
+	case zapcore.PanicLevel:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,131 @@
+package log_test
+
+import (
+	"bufio"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sinkConfiguration() log.Configuration {
+	return log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithOutputPaths(os.DevNull)
+}
+
+var syslogMessage = regexp.MustCompile(`^<(\d+)>1 \S+ \S+ TEST-APPLICATION \d+ - - (\{.*\})$`)
+
+func TestSyslogUdpMapsSeverities(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	log.Init(sinkConfiguration().WithSyslog(log.SyslogOptions{Network: "udp", Address: conn.LocalAddr().String(), Facility: log.FacilityLocal0}))
+	log.Debug("Debug msg sent to syslog")
+	log.Info("Info msg sent to syslog")
+	log.Warn("Warn msg sent to syslog")
+	log.Error("Error msg sent to syslog")
+
+	buffer := make([]byte, 64*1024)
+	for _, expected := range []struct {
+		priority int
+		message  string
+	}{
+		{16*8 + 7, "Debug msg sent to syslog"},
+		{16*8 + 6, "Info msg sent to syslog"},
+		{16*8 + 4, "Warn msg sent to syslog"},
+		{16*8 + 3, "Error msg sent to syslog"},
+	} {
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buffer)
+		assert.NoError(t, err)
+		match := syslogMessage.FindStringSubmatch(string(buffer[:n]))
+		if assert.NotNil(t, match, string(buffer[:n])) {
+			assert.Equal(t, strconv.Itoa(expected.priority), match[1])
+			assert.Contains(t, match[2], `"Body.message":"`+expected.message+`"`)
+		}
+	}
+}
+
+func TestSyslogTcpFramesByOctetCounting(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		length, _ := reader.ReadString(' ')
+		n, _ := strconv.Atoi(strings.TrimSpace(length))
+		message := make([]byte, n)
+		_, _ = io.ReadFull(reader, message)
+		received <- string(message)
+	}()
+
+	log.Init(sinkConfiguration().WithSyslog(log.SyslogOptions{Network: "tcp", Address: listener.Addr().String()}))
+	log.Info("Info msg sent to syslog over tcp")
+
+	select {
+	case message := <-received:
+		match := syslogMessage.FindStringSubmatch(message)
+		if assert.NotNil(t, match, message) {
+			assert.Equal(t, "14", match[1])
+			assert.Contains(t, match[2], `"Body.message":"Info msg sent to syslog over tcp"`)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no syslog message received")
+	}
+}
+
+func TestJournaldNativeProtocol(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journald")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "socket")
+	conn, err := net.ListenPacket("unixgram", socket)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	log.Init(sinkConfiguration().WithJournald(socket))
+	log.Warn("Warn msg sent to journald")
+
+	buffer := make([]byte, 64*1024)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buffer)
+	assert.NoError(t, err)
+	fields := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSuffix(string(buffer[:n]), "\n"), "\n") {
+		parts := strings.SplitN(line, "=", 2)
+		if assert.Len(t, parts, 2, line) {
+			fields[parts[0]] = parts[1]
+		}
+	}
+	assert.Equal(t, "4", fields["PRIORITY"])
+	assert.Equal(t, "TEST-APPLICATION", fields["SYSLOG_IDENTIFIER"])
+	assert.Contains(t, fields["MESSAGE"], `"Body.message":"Warn msg sent to journald"`)
+}
+
+func TestSyslogOptionsValidation(t *testing.T) {
+	err := sinkConfiguration().WithSyslog(log.SyslogOptions{Network: "http"}).Validate()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `syslog network must be one of udp, tcp, unix or unixgram, got "http"`)
+}
@@ -0,0 +1,141 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sync"
+)
+
+// DefaultTailSize is the number of records kept by WithTailBuffering when size is not positive.
+const DefaultTailSize = 100
+
+// WithTailBuffering keeps in memory the last size records of the invocation filtered out by the configured level
+// or the namespace levels, down to DEBUG, and writes them before the first ERROR or higher record, or on FlushTail
+// when the invocation fails, with TailEscalated true, so failed invocations come with their full context while
+// successful ones stay quiet. The kept records are dropped by ResetInvocation.
+func (c Configuration) WithTailBuffering(size int) Configuration {
+	if size <= 0 {
+		size = DefaultTailSize
+	}
+	c.tailSize = size
+	return c
+}
+
+// FlushTail writes the records kept by WithTailBuffering, e.g. when the handler returns an error without logging it.
+// lambdawrap.Wrap calls it for the failed invocations.
+func FlushTail() {
+	tail.flush()
+}
+
+type tailRecord struct {
+	core   zapcore.Core
+	entry  zapcore.Entry
+	fields []zapcore.Field
+}
+
+// tailBuffer is the ring of the records kept during the invocation.
+type tailBuffer struct {
+	sync.Mutex
+	records []tailRecord
+	next    int
+	full    bool
+}
+
+var tail = &tailBuffer{}
+
+func initTail(size int) {
+	tail.configure(size)
+}
+
+func (b *tailBuffer) configure(size int) {
+	b.Lock()
+	defer b.Unlock()
+	b.records = make([]tailRecord, size)
+	b.next, b.full = 0, false
+}
+
+func (b *tailBuffer) add(record tailRecord) {
+	b.Lock()
+	defer b.Unlock()
+	if len(b.records) == 0 {
+		return
+	}
+	b.records[b.next] = record
+	b.next = (b.next + 1) % len(b.records)
+	b.full = b.full || b.next == 0
+}
+
+// take empties the ring, returning its records oldest first.
+func (b *tailBuffer) take() []tailRecord {
+	b.Lock()
+	defer b.Unlock()
+	var records []tailRecord
+	if b.full {
+		records = append(records, b.records[b.next:]...)
+	}
+	records = append(records, b.records[:b.next]...)
+	for i := range b.records {
+		b.records[i] = tailRecord{}
+	}
+	b.next, b.full = 0, false
+	return records
+}
+
+func (b *tailBuffer) resetInvocation() {
+	b.take()
+}
+
+func (b *tailBuffer) flush() {
+	escalated := zap.Bool(TailEscalated, true)
+	for _, record := range b.take() {
+		if checked := record.core.Check(record.entry, nil); checked != nil {
+			checked.Write(append(record.fields[:len(record.fields):len(record.fields)], escalated)...)
+		}
+	}
+}
+
+// tailCore keeps the records filtered out by the log level and the namespace levels in the tail, to be written
+// later to unfiltered, and flushes the tail before the ERROR and higher records.
+type tailCore struct {
+	zapcore.Core
+	unfiltered zapcore.Core
+	logLevel   zap.AtomicLevel
+	levels     *namespaceLevels
+}
+
+func (c *tailCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.DebugLevel || c.Core.Enabled(level)
+}
+
+func (c *tailCore) namespaceEnabled(name string, level zapcore.Level) bool {
+	return level >= zapcore.DebugLevel || namespaceEnabled(c.Core, name, level)
+}
+
+func (c *tailCore) With(fields []zapcore.Field) zapcore.Core {
+	return &tailCore{Core: c.Core.With(fields), unfiltered: c.unfiltered.With(fields), logLevel: c.logLevel, levels: c.levels}
+}
+
+func (c *tailCore) enabled(entry zapcore.Entry) bool {
+	if c.levels != nil {
+		return c.levels.enabled(entry.LoggerName, entry.Level)
+	}
+	return c.logLevel.Enabled(entry.Level)
+}
+
+func (c *tailCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.enabled(entry) {
+		if entry.Level >= zapcore.ErrorLevel {
+			tail.flush()
+		}
+		return c.Core.Check(entry, checked)
+	}
+	if entry.Level >= zapcore.DebugLevel {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *tailCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	tail.add(tailRecord{core: c.unfiltered, entry: entry, fields: fields})
+	return nil
+}
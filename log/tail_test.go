@@ -0,0 +1,76 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+)
+
+func TestTailBuffering(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("WARN", "TEST-APPLICATION", "", "", "", "").
+		WithTailBuffering(2).
+		WithoutSampling().
+		WithWriters(zapcore.AddSync(&buffer)))
+	defer log.ResetInvocation()
+
+	log.Debug("Dropped by the successful invocation")
+	log.ResetInvocation()
+	log.Debug("Overwritten record")
+	log.Debug("Debug record")
+	log.Info("Info record")
+	log.Warn("Warn record")
+	assert.Equal(t, 1, strings.Count(buffer.String(), "\n"))
+
+	log.Error("Error record")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Contains(t, lines[0], "Warn record")
+	assert.Contains(t, lines[1], "Debug record")
+	assert.Contains(t, lines[1], `"`+log.TailEscalated+`":true`)
+	assert.Contains(t, lines[2], "Info record")
+	assert.Contains(t, lines[3], "Error record")
+	assert.NotContains(t, lines[3], log.TailEscalated)
+	assert.NotContains(t, buffer.String(), "Dropped")
+	assert.NotContains(t, buffer.String(), "Overwritten")
+}
+
+func TestFlushTail(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithTailBuffering(0).
+		WithWriters(zapcore.AddSync(&buffer)))
+	defer log.ResetInvocation()
+
+	log.DebugW("Debug record", "orderId", "1")
+	log.FlushTail()
+	log.FlushTail()
+
+	assert.Equal(t, 1, strings.Count(buffer.String(), "Debug record"))
+	assert.Contains(t, buffer.String(), `"orderId":"1"`)
+}
+
+func TestTailBufferingAcrossRecords(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithTailBuffering(10).
+		WithoutSampling().
+		WithWriters(zapcore.AddSync(&buffer)))
+	defer log.ResetInvocation()
+	event := events.KinesisEvent{Records: []events.KinesisEventRecord{
+		{Kinesis: events.KinesisRecord{SequenceNumber: "1"}},
+		{Kinesis: events.KinesisRecord{SequenceNumber: "2"}},
+	}}
+
+	ctx := log.SetUpKinesis(context.Background(), event)
+	log.FromContext(log.SetUpKinesisRecord(ctx, event.Records[0])).Debug("Record processed")
+	log.FromContext(log.SetUpKinesisRecord(ctx, event.Records[1])).Error("Record failed")
+
+	assert.Contains(t, buffer.String(), "Record processed")
+}
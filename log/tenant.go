@@ -0,0 +1,59 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type tenantKey struct{}
+
+// Tenant identifies the tenant an invocation is processed for.
+type Tenant struct {
+	Id      string
+	Account string
+}
+
+// WithTenantAllowlist restricts WithTenant to the given tenant ids, every tenant id is accepted by default.
+func (c Configuration) WithTenantAllowlist(tenantIds ...string) Configuration {
+	allowed := map[string]bool{}
+	for tenantId := range c.tenantAllowlist {
+		allowed[tenantId] = true
+	}
+	for _, tenantId := range tenantIds {
+		allowed[tenantId] = true
+	}
+	c.tenantAllowlist = allowed
+	return c
+}
+
+// WithTenant returns a copy of ctx carrying the tenant and a logger with the TenantId and TenantAccount fields,
+// metrics emitted with the context carry them as well. An empty accountId is not logged.
+// It fails, returning ctx unchanged, when the tenant id is empty or not in the allowlist, see WithTenantAllowlist.
+func WithTenant(ctx context.Context, tenantId, accountId string) (context.Context, error) {
+	if tenantId == "" {
+		return ctx, errors.New("tenant id is required")
+	}
+	if allowlist := GetConfiguration().tenantAllowlist; allowlist != nil && !allowlist[tenantId] {
+		return ctx, fmt.Errorf("tenant %q is not allowed", tenantId)
+	}
+	tenant := Tenant{Id: tenantId, Account: accountId}
+	return NewContext(context.WithValue(ctx, tenantKey{}, tenant), tenant.fields()...), nil
+}
+
+// TenantFromContext returns the tenant stored by WithTenant.
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	if ctx == nil {
+		return Tenant{}, false
+	}
+	tenant, ok := ctx.Value(tenantKey{}).(Tenant)
+	return tenant, ok
+}
+
+func (t Tenant) fields() []interface{} {
+	fields := []interface{}{TenantId, t.Id}
+	if t.Account != "" {
+		fields = append(fields, TenantAccount, t.Account)
+	}
+	return fields
+}
@@ -0,0 +1,36 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWithTenant(t *testing.T) {
+	recorder := logtest.Capture(t)
+	ctx, err := log.WithTenant(context.Background(), "tenant-1", "123456789012")
+	assert.NoError(t, err)
+
+	log.InfoCtx(ctx, "Info msg with tenant")
+	recorder.AssertField(log.TenantId, "tenant-1")
+	recorder.AssertField(log.TenantAccount, "123456789012")
+	tenant, ok := log.TenantFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, log.Tenant{Id: "tenant-1", Account: "123456789012"}, tenant)
+}
+
+func TestWithTenantAllowlist(t *testing.T) {
+	logtest.Capture(t)
+	log.Init(log.GetConfiguration().WithTenantAllowlist("tenant-1"))
+	ctx := context.Background()
+
+	_, err := log.WithTenant(ctx, "tenant-1", "")
+	assert.NoError(t, err)
+	unchanged, err := log.WithTenant(ctx, "tenant-2", "")
+	assert.EqualError(t, err, `tenant "tenant-2" is not allowed`)
+	assert.Equal(t, ctx, unchanged)
+	_, err = log.WithTenant(ctx, "", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"context"
+	"time"
+)
+
+const defaultTimeoutWarning = 0.9
+
+// WithTimeoutWarning sets the fraction of the invocation timeout after which StartInvocationTimer logs a WARN record, 0.9 by default.
+func (c Configuration) WithTimeoutWarning(threshold float64) Configuration {
+	c.timeoutWarning = threshold
+	return c
+}
+
+// StartInvocationTimer starts timing the invocation of ctx, the returned function logs an "Invocation finished" record
+// with the InvocationDuration and must be called when the handler returns, e.g. defer log.StartInvocationTimer(ctx)().
+// When ctx has a deadline, as the contexts of Lambda invocations do, an "Invocation about to time out" WARN record
+// with the RemainingTime is logged once the timeout warning threshold is reached, see WithTimeoutWarning.
+// The runtime stats are reported when the invocation finishes with WithRuntimeStats and a 0 interval.
+func StartInvocationTimer(ctx context.Context) func() {
+	start := time.Now()
+	logger := FromContext(ctx)
+
+	var watchdog *time.Timer
+	if deadline, ok := ctx.Deadline(); ok {
+		threshold := GetConfiguration().timeoutWarning
+		if threshold == 0 {
+			threshold = defaultTimeoutWarning
+		}
+		timeout := deadline.Sub(start)
+		watchdog = time.AfterFunc(time.Duration(float64(timeout)*threshold), func() {
+			logger.Warnw("Invocation about to time out",
+				InvocationDuration, time.Since(start),
+				RemainingTime, time.Until(deadline))
+		})
+	}
+
+	return func() {
+		if watchdog != nil {
+			watchdog.Stop()
+		}
+		logger.Infow("Invocation finished", InvocationDuration, time.Since(start))
+		reporter.invocationFinished()
+	}
+}
@@ -0,0 +1,45 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+	"time"
+)
+
+func TestStartInvocationTimer(t *testing.T) {
+	recorder := logtest.Capture(t)
+	log.Init(log.GetConfiguration().WithTimeoutWarning(0.5))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	stop := log.StartInvocationTimer(ctx)
+	time.Sleep(70 * time.Millisecond)
+	stop()
+
+	entries := recorder.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	assert.Equal(t, "Invocation about to time out", entries[0].Message)
+	assert.Contains(t, entries[0].Fields, log.RemainingTime)
+	assert.Equal(t, "Invocation finished", entries[1].Message)
+	duration, ok := entries[1].Fields[log.InvocationDuration].(time.Duration)
+	assert.True(t, ok)
+	assert.True(t, duration >= 70*time.Millisecond)
+}
+
+func TestStartInvocationTimerFinishedInTime(t *testing.T) {
+	recorder := logtest.Capture(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	log.StartInvocationTimer(ctx)()
+	time.Sleep(10 * time.Millisecond)
+
+	entries := recorder.Entries()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "Invocation finished", entries[0].Message)
+}
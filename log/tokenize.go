@@ -0,0 +1,60 @@
+package log
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	tokenPrefix = "tok_"
+
+	// minTokenKeySize is a 128-bit HMAC key, shorter keys make the tokens easier to brute force.
+	minTokenKeySize = 16
+)
+
+// TokenizeFields replaces fields and json object keys with any of the given names, compared case-insensitively,
+// by a deterministic token, the HMAC-SHA256 of their value with key. Records of the same user stay correlated
+// but hold no raw identifier, so nothing has to be purged from the logs on erasure requests.
+// The key should be kept in KMS or Secrets Manager and decrypted at start up, see also TokenizationKeyEnv.
+func TokenizeFields(key []byte, names ...string) RedactionRule {
+	return RedactionRule{tokenized: names, tokenKey: key}
+}
+
+// Tokenize returns the token TokenizeFields replaces value with, e.g. to search the records of a user.
+func Tokenize(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return tokenPrefix + hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func tokenizeField(key []byte, field zapcore.Field) zapcore.Field {
+	encoder := zapcore.NewMapObjectEncoder()
+	field.AddTo(encoder)
+	value, ok := encoder.Fields[field.Key]
+	if !ok {
+		return field
+	}
+	return zap.String(field.Key, tokenizeValue(key, value))
+}
+
+// tokenizeValue tokenizes the text of a scalar value, objects and arrays are redacted, they aren't identifiers.
+func tokenizeValue(key []byte, value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}, []interface{}:
+		return Redacted
+	}
+	return Tokenize(key, fmt.Sprint(value))
+}
+
+func (r RedactionRule) validate() error {
+	if len(r.tokenized) > 0 && len(r.tokenKey) < minTokenKeySize {
+		return fmt.Errorf("tokenization key of %v must be at least %d bytes, got %d", r.tokenized, minTokenKeySize, len(r.tokenKey))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+var tokenKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestTokenizeFields(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithRedaction(log.TokenizeFields(tokenKey, "userId", "email")))
+
+	log.InfoW("Booking created", "userId", 12345, "bookingId", "B1")
+	log.InfoW("Booking updated", "USERID", "12345", log.EventBody, `{"customer":{"email":"jane@example.com"}}`)
+
+	token := log.Tokenize(tokenKey, "12345")
+	assert.Regexp(t, `^tok_[0-9a-f]{32}$`, token)
+	assert.Contains(t, buffer.String(), `"userId":"`+token+`"`)
+	assert.Contains(t, buffer.String(), `"USERID":"`+token+`"`)
+	assert.Contains(t, buffer.String(), `"bookingId":"B1"`)
+	assert.Contains(t, buffer.String(), log.Tokenize(tokenKey, "jane@example.com"))
+	assert.NotContains(t, buffer.String(), "jane@example.com")
+	assert.NotEqual(t, token, log.Tokenize([]byte("another key of at least 16 bytes"), "12345"))
+}
+
+func TestTokenizeFieldsShortKey(t *testing.T) {
+	err := log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithRedaction(log.TokenizeFields([]byte("short"), "userId")).
+		Validate()
+
+	assert.EqualError(t, err, "invalid log configuration:\ntokenization key of [userId] must be at least 16 bytes, got 5")
+}
+
+func TestTokenizeFieldsFromEnv(t *testing.T) {
+	setEnv(t, map[string]string{
+		log.ApplicationEnv:     "TEST-APPLICATION",
+		log.TokenizeFieldsEnv:  "userId, email",
+		log.TokenizationKeyEnv: base64.StdEncoding.EncodeToString(tokenKey),
+	})
+	config, err := log.NewConfigurationFromEnv()
+	assert.NoError(t, err)
+	var buffer bytes.Buffer
+	log.Init(config.WithWriters(zapcore.AddSync(&buffer)))
+
+	log.InfoW("Booking created", "email", "jane@example.com")
+
+	assert.Contains(t, buffer.String(), log.Tokenize(tokenKey, "jane@example.com"))
+}
@@ -0,0 +1,157 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-xray-sdk-go/header"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+var errMalformedTraceParent = errors.New("malformed traceparent")
+
+// TraceContext holds the identifiers reported as TraceId, SpanId and TraceFlags.
+// TraceId keeps the format of its origin, X-Ray ids are only converted to W3C when a traceparent is emitted.
+type TraceContext struct {
+	TraceId string
+	SpanId  string
+	Sampled bool
+	State   string
+}
+
+type traceContextKey struct{}
+
+func ContextWithTraceContext(ctx context.Context, traceContext TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, traceContext)
+}
+
+// TraceContextFromContext returns the trace context stored by ContextWithTraceContext, falling back to the one
+// of the tracing provider, see SetUpTracing, by default the X-Ray header of the invocation.
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	if traceContext, ok := ctx.Value(traceContextKey{}).(TraceContext); ok {
+		return traceContext, true
+	}
+	return tracingProvider().TraceContext(ctx)
+}
+
+func xRayTraceContext(traceHeader *header.Header) TraceContext {
+	return TraceContext{
+		TraceId: traceHeader.TraceID,
+		SpanId:  traceHeader.ParentID,
+		Sampled: traceHeader.SamplingDecision == header.Sampled,
+	}
+}
+
+// SetupTraceIdsFromHeaders behaves like SetupTraceIds but prefers the trace headers found in headers, see
+// TraceContextFromHeaders, over the X-Ray header, and attaches the entries of their baggage header to ctx.
+func SetupTraceIdsFromHeaders(ctx context.Context, headers map[string]string) context.Context {
+	return SetupTraceIds(headersContext(ctx, headers))
+}
+
+// headersContext returns a copy of ctx carrying the trace context and the baggage of headers.
+func headersContext(ctx context.Context, headers map[string]string) context.Context {
+	if traceContext, ok := TraceContextFromHeaders(headers); ok {
+		ctx = ContextWithTraceContext(ctx, traceContext)
+	}
+	return ContextWithBaggage(ctx, BaggageFromHeaders(headers))
+}
+
+// TraceContextFromHeaders reads the trace context of the first trace headers found in headers among the ones
+// selected by WithTracePropagation, by default traceparent, b3, X-B3-* and uber-trace-id.
+func TraceContextFromHeaders(headers map[string]string) (TraceContext, bool) {
+	for _, propagation := range extractedPropagations() {
+		if traceContext, ok := propagation.extract(headers); ok {
+			return traceContext, true
+		}
+	}
+	return TraceContext{}, false
+}
+
+// ParseTraceParent parses a "version-traceid-parentid-flags" W3C traceparent value.
+func ParseTraceParent(traceParent, traceState string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || !isHex(parts[0], 2) || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return TraceContext{}, errMalformedTraceParent
+	}
+	traceId, spanId, flags := parts[1], parts[2], parts[3]
+	if !isHex(traceId, 32) || isZero(traceId) || !isHex(spanId, 16) || isZero(spanId) || !isHex(flags, 2) {
+		return TraceContext{}, errMalformedTraceParent
+	}
+	flagBits, _ := strconv.ParseUint(flags, 16, 8)
+	return TraceContext{
+		TraceId: traceId,
+		SpanId:  spanId,
+		Sampled: flagBits&0x01 == 0x01,
+		State:   strings.TrimSpace(traceState),
+	}, nil
+}
+
+// TraceParent formats the trace context as a W3C traceparent value, converting X-Ray trace ids when needed.
+func (t TraceContext) TraceParent() string {
+	flags := "00"
+	if t.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", w3cTraceId(t.TraceId), t.SpanId, flags)
+}
+
+// InjectTraceContextHeaders sets the trace headers selected by WithTracePropagation of an outgoing request,
+// by default traceparent and tracestate.
+func InjectTraceContextHeaders(ctx context.Context, request *http.Request) {
+	traceContext, ok := TraceContextFromContext(ctx)
+	if !ok || traceContext.SpanId == "" {
+		return
+	}
+	for _, propagation := range injectedPropagations() {
+		propagation.inject(traceContext, request.Header)
+	}
+}
+
+// X-Ray trace ids have the form "1-5759e988-bd862e3fe1be46a994272793"
+func w3cTraceId(traceId string) string {
+	parts := strings.Split(traceId, "-")
+	if len(parts) == 3 && parts[0] == "1" && isHex(parts[1]+parts[2], 32) {
+		return parts[1] + parts[2]
+	}
+	return traceId
+}
+
+// xRayTraceId converts W3C trace ids to the "1-5759e988-bd862e3fe1be46a994272793" form
+func xRayTraceId(traceId string) string {
+	if isHex(traceId, 32) {
+		return "1-" + traceId[:8] + "-" + traceId[8:]
+	}
+	return traceId
+}
+
+func headerValue(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+func isHex(value string, length int) bool {
+	if len(value) != length {
+		return false
+	}
+	for _, c := range value {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(value string) bool {
+	return strings.Trim(value, "0") == ""
+}
@@ -0,0 +1,57 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	traceContext, err := log.ParseTraceParent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", "congo=t61rcWkgMzE")
+
+	assert.NoError(t, err)
+	assert.Equal(t, log.TraceContext{
+		TraceId: "0af7651916cd43dd8448eb211c80319c",
+		SpanId:  "b7ad6b7169203331",
+		Sampled: true,
+		State:   "congo=t61rcWkgMzE",
+	}, traceContext)
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", traceContext.TraceParent())
+}
+
+func TestParseMalformedTraceParent(t *testing.T) {
+	for _, traceParent := range []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01",
+	} {
+		_, err := log.ParseTraceParent(traceParent, "")
+		assert.Error(t, err, traceParent)
+	}
+}
+
+func TestTraceContextFallsBackToXRay(t *testing.T) {
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Sampled=1;Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8")
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost", nil)
+
+	log.InjectTraceContextHeaders(ctx, request)
+
+	assert.Equal(t, "00-5759e988bd862e3fe1be46a994272793-53995c3f42cd8ad8-01", request.Header.Get(log.TraceParentHeader))
+}
+
+//doesn't assert anything because we have no method output, it's only to check if log format is valid
+func TestSetupTraceIdsFromHeaders(t *testing.T) {
+	initDebugLogger()
+	headers := map[string]string{"Traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00"}
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Sampled=1;Root=TraceIdValue;Parent=ParentIdValue")
+
+	log.SetupTraceIdsFromHeaders(ctx, headers)
+	log.Debug("Debug msg with W3C trace ids")
+	log.ResetInvocation()
+}
@@ -0,0 +1,81 @@
+package log
+
+import (
+	"fmt"
+	"go.uber.org/zap/zapcore"
+	"strings"
+)
+
+// TraceLevel is below DEBUG, for high volume diagnostics like packet dumps. zap has no such level, it's written
+// as TRACE, or in the case of the level encoder of the profile, and parsed from "TRACE" wherever a level is configured.
+// TRACE records are never sampled.
+const TraceLevel = zapcore.DebugLevel - 1
+
+// parseLevel parses the text of a zap level or TRACE, case insensitive.
+func parseLevel(text string) (zapcore.Level, error) {
+	if strings.EqualFold(text, "trace") {
+		return TraceLevel, nil
+	}
+	var level zapcore.Level
+	err := level.UnmarshalText([]byte(text))
+	return level, err
+}
+
+func levelString(level zapcore.Level) string {
+	if level == TraceLevel {
+		return "TRACE"
+	}
+	return level.CapitalString()
+}
+
+// withTraceLevel makes encoder write TraceLevel like it writes DEBUG with TRACE instead, keeping its case and colors.
+func withTraceLevel(encoder zapcore.LevelEncoder) zapcore.LevelEncoder {
+	if encoder == nil {
+		return nil
+	}
+	captured := zapcore.NewMapObjectEncoder()
+	_ = captured.AddArray("level", zapcore.ArrayMarshalerFunc(func(array zapcore.ArrayEncoder) error {
+		encoder(zapcore.DebugLevel, array)
+		return nil
+	}))
+	trace := "TRACE"
+	if values, ok := captured.Fields["level"].([]interface{}); ok && len(values) == 1 {
+		debug, ok := values[0].(string)
+		if !ok {
+			// numeric levels, e.g. the syslog severities of GELF, already encode TRACE
+			return encoder
+		}
+		trace = strings.NewReplacer("DEBUG", "TRACE", "debug", "trace", "Debug", "Trace").Replace(debug)
+	}
+	return func(level zapcore.Level, array zapcore.PrimitiveArrayEncoder) {
+		if level == TraceLevel {
+			array.AppendString(trace)
+			return
+		}
+		encoder(level, array)
+	}
+}
+
+// Tracef logs at TraceLevel, the name Trace is taken by the X-Ray subsegment helper.
+func Tracef(template string, args ...interface{}) {
+	if !IsTraceEnabled() {
+		return
+	}
+	msg := template
+	if len(args) > 0 {
+		msg = fmt.Sprintf(template, args...)
+	}
+	if checked := logger().Desugar().Check(TraceLevel, msg); checked != nil {
+		checked.Write()
+	}
+}
+
+func TraceW(msg string, keysAndValues ...interface{}) {
+	if checked := logger().Desugar().Check(TraceLevel, msg); checked != nil {
+		checked.Write(appendFields(nil, keysAndValues)...)
+	}
+}
+
+func IsTraceEnabled() bool {
+	return levelEnabled(TraceLevel)
+}
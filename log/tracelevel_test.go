@@ -0,0 +1,93 @@
+package log_test
+
+import (
+	"bytes"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTraceLevel(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration(
+		"TRACE",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)))
+
+	assert.True(t, log.IsTraceEnabled())
+	assert.Equal(t, "TRACE", log.GetLevel())
+	log.Tracef("Packet %d", 1)
+	log.TraceW("Packet dump", "bytes", "0a0b")
+
+	assert.Contains(t, buffer.String(), `"SeverityText":"TRACE"`)
+	assert.Contains(t, buffer.String(), `"Resource.logger":"log/tracelevel_test.go`)
+	assert.Contains(t, buffer.String(), `"Body.message":"Packet 1"`)
+	assert.Contains(t, buffer.String(), `"bytes":"0a0b"`)
+
+	buffer.Reset()
+	assert.NoError(t, log.SetLevel("DEBUG"))
+	log.Tracef("Packet %d", 2)
+	log.Debug("Debug msg")
+	assert.False(t, log.IsTraceEnabled())
+	assert.NotContains(t, buffer.String(), "Packet 2")
+	assert.Contains(t, buffer.String(), "Debug msg")
+}
+
+func TestTraceLevelProfile(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("trace", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithProfile(log.ECSProfile))
+
+	log.Tracef("Packet")
+	log.Info("Info msg")
+
+	assert.Contains(t, buffer.String(), `"log.level":"trace"`)
+	assert.Contains(t, buffer.String(), `"log.level":"info"`)
+}
+
+func TestTraceLevelInvalid(t *testing.T) {
+	assert.Error(t, log.SetLevel("TRACING"))
+	err := log.NewConfiguration("VERBOSE", "TEST-APPLICATION", "", "", "", "").Validate()
+	assert.Contains(t, err.Error(), "expected one of TRACE, DEBUG")
+}
+
+func TestFatalFlushes(t *testing.T) {
+	path := os.Getenv("GOFRLIB_FATAL")
+	if path != "" {
+		log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+			WithOutputPaths(path).
+			WithBuffering(log.DefaultBufferSize, time.Hour).
+			WithDeduplication(time.Hour))
+		log.Info("Repeated")
+		log.Info("Repeated")
+		log.FatalW("Unrecoverable", "reason", "test")
+		return
+	}
+	dir, err := ioutil.TempDir("", "fatal")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path = filepath.Join(dir, "fatal.log")
+	command := exec.Command(os.Args[0], "-test.run=^TestFatalFlushes$")
+	command.Env = append(os.Environ(), "GOFRLIB_FATAL="+path)
+	err = command.Run()
+	assert.Error(t, err)
+
+	content, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, string(content), `"SeverityText":"FATAL"`)
+	assert.Contains(t, string(content), `"Body.repeatCount":1`)
+}
@@ -0,0 +1,203 @@
+package log
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	B3Header          = "b3"
+	B3TraceIdHeader   = "X-B3-TraceId"
+	B3SpanIdHeader    = "X-B3-SpanId"
+	B3SampledHeader   = "X-B3-Sampled"
+	B3FlagsHeader     = "X-B3-Flags"
+	JaegerTraceHeader = "uber-trace-id"
+)
+
+// TracePropagation is a format of the trace headers of the requests.
+type TracePropagation int
+
+const (
+	// W3CPropagation is the traceparent and tracestate headers of the W3C trace context.
+	W3CPropagation TracePropagation = iota
+	// B3SinglePropagation is the b3 header of Zipkin, "traceid-spanid-sampled".
+	B3SinglePropagation
+	// B3MultiPropagation is the X-B3-TraceId, X-B3-SpanId and X-B3-Sampled headers of Zipkin, the ones of Istio.
+	B3MultiPropagation
+	// JaegerPropagation is the uber-trace-id header of Jaeger, "traceid:spanid:parentid:flags".
+	JaegerPropagation
+)
+
+var tracePropagationNames = map[TracePropagation]string{
+	W3CPropagation:      "w3c",
+	B3SinglePropagation: "b3",
+	B3MultiPropagation:  "b3multi",
+	JaegerPropagation:   "jaeger",
+}
+
+func (p TracePropagation) String() string {
+	if name, ok := tracePropagationNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("TracePropagation(%d)", int(p))
+}
+
+// ParseTracePropagation parses the name of a TracePropagation: w3c, b3, b3multi or jaeger.
+func ParseTracePropagation(name string) (TracePropagation, error) {
+	for propagation, propagationName := range tracePropagationNames {
+		if strings.EqualFold(strings.TrimSpace(name), propagationName) {
+			return propagation, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown trace propagation %q, expected w3c, b3, b3multi or jaeger", name)
+}
+
+// WithTracePropagation selects the trace headers read by TraceContextFromHeaders, tried in the given order,
+// and written by InjectTraceContextHeaders. By default every format is detected, traceparent first,
+// and only traceparent and tracestate are written.
+func (c Configuration) WithTracePropagation(propagations ...TracePropagation) Configuration {
+	c.tracePropagation = append([]TracePropagation{}, propagations...)
+	return c
+}
+
+var allTracePropagations = []TracePropagation{W3CPropagation, B3SinglePropagation, B3MultiPropagation, JaegerPropagation}
+
+var tracePropagation struct {
+	sync.RWMutex
+	propagations []TracePropagation
+}
+
+func initTracePropagation(propagations []TracePropagation) {
+	tracePropagation.Lock()
+	defer tracePropagation.Unlock()
+	tracePropagation.propagations = propagations
+}
+
+// extractedPropagations returns the formats read from the headers, all of them when none were selected.
+func extractedPropagations() []TracePropagation {
+	tracePropagation.RLock()
+	defer tracePropagation.RUnlock()
+	if len(tracePropagation.propagations) == 0 {
+		return allTracePropagations
+	}
+	return tracePropagation.propagations
+}
+
+// injectedPropagations returns the formats written to the outgoing requests, W3C when none were selected.
+func injectedPropagations() []TracePropagation {
+	tracePropagation.RLock()
+	defer tracePropagation.RUnlock()
+	if len(tracePropagation.propagations) == 0 {
+		return []TracePropagation{W3CPropagation}
+	}
+	return tracePropagation.propagations
+}
+
+func (p TracePropagation) extract(headers map[string]string) (TraceContext, bool) {
+	var traceContext TraceContext
+	var err error
+	switch p {
+	case W3CPropagation:
+		traceContext, err = ParseTraceParent(headerValue(headers, TraceParentHeader), headerValue(headers, TraceStateHeader))
+	case B3SinglePropagation:
+		traceContext, err = ParseB3(headerValue(headers, B3Header))
+	case B3MultiPropagation:
+		traceContext, err = parseB3Multi(headers)
+	case JaegerPropagation:
+		traceContext, err = ParseJaeger(headerValue(headers, JaegerTraceHeader))
+	default:
+		return TraceContext{}, false
+	}
+	return traceContext, err == nil
+}
+
+func (p TracePropagation) inject(traceContext TraceContext, header http.Header) {
+	traceId := w3cTraceId(traceContext.TraceId)
+	switch p {
+	case W3CPropagation:
+		header.Set(TraceParentHeader, traceContext.TraceParent())
+		if traceContext.State != "" {
+			header.Set(TraceStateHeader, traceContext.State)
+		}
+	case B3SinglePropagation:
+		header.Set(B3Header, traceId+"-"+traceContext.SpanId+"-"+b3Sampled(traceContext.Sampled))
+	case B3MultiPropagation:
+		header.Set(B3TraceIdHeader, traceId)
+		header.Set(B3SpanIdHeader, traceContext.SpanId)
+		header.Set(B3SampledHeader, b3Sampled(traceContext.Sampled))
+	case JaegerPropagation:
+		flags := "0"
+		if traceContext.Sampled {
+			flags = "1"
+		}
+		header.Set(JaegerTraceHeader, traceId+":"+traceContext.SpanId+":0:"+flags)
+	}
+}
+
+// ParseB3 parses a "traceid-spanid-sampled-parentspanid" b3 header value, the sampling state and the parent span id
+// being optional. 64 bit trace ids are padded to 128 bits.
+func ParseB3(b3 string) (TraceContext, error) {
+	parts := strings.Split(strings.TrimSpace(b3), "-")
+	if len(parts) < 2 || len(parts) > 4 {
+		return TraceContext{}, fmt.Errorf("malformed b3 %q", b3)
+	}
+	sampled := ""
+	if len(parts) > 2 {
+		sampled = parts[2]
+	}
+	return b3TraceContext(parts[0], parts[1], sampled, "")
+}
+
+func parseB3Multi(headers map[string]string) (TraceContext, error) {
+	return b3TraceContext(
+		strings.TrimSpace(headerValue(headers, B3TraceIdHeader)),
+		strings.TrimSpace(headerValue(headers, B3SpanIdHeader)),
+		strings.TrimSpace(headerValue(headers, B3SampledHeader)),
+		strings.TrimSpace(headerValue(headers, B3FlagsHeader)))
+}
+
+func b3TraceContext(traceId, spanId, sampled, flags string) (TraceContext, error) {
+	if len(traceId) == 16 {
+		traceId = strings.Repeat("0", 16) + traceId
+	}
+	if !isHex(traceId, 32) || isZero(traceId) || !isHex(spanId, 16) || isZero(spanId) {
+		return TraceContext{}, fmt.Errorf("malformed b3 trace id %q or span id %q", traceId, spanId)
+	}
+	return TraceContext{
+		TraceId: traceId,
+		SpanId:  spanId,
+		Sampled: sampled == "1" || strings.EqualFold(sampled, "true") || sampled == "d" || flags == "1",
+	}, nil
+}
+
+func b3Sampled(sampled bool) string {
+	if sampled {
+		return "1"
+	}
+	return "0"
+}
+
+// ParseJaeger parses a "traceid:spanid:parentid:flags" uber-trace-id header value, or its url encoded form.
+// The ids, which Jaeger doesn't pad, are padded to 128 bits for the trace id and 64 for the span id.
+func ParseJaeger(uberTraceId string) (TraceContext, error) {
+	value := strings.Replace(strings.TrimSpace(uberTraceId), "%3A", ":", -1)
+	value = strings.Replace(value, "%3a", ":", -1)
+	parts := strings.Split(value, ":")
+	if len(parts) != 4 || len(parts[0]) > 32 || len(parts[1]) > 16 {
+		return TraceContext{}, fmt.Errorf("malformed uber-trace-id %q", uberTraceId)
+	}
+	traceId := strings.Repeat("0", 32-len(parts[0])) + strings.ToLower(parts[0])
+	spanId := strings.Repeat("0", 16-len(parts[1])) + strings.ToLower(parts[1])
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil || !isHex(traceId, 32) || isZero(traceId) || !isHex(spanId, 16) || isZero(spanId) {
+		return TraceContext{}, fmt.Errorf("malformed uber-trace-id %q", uberTraceId)
+	}
+	return TraceContext{
+		TraceId: traceId,
+		SpanId:  spanId,
+		Sampled: flags&0x01 == 0x01,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestTraceContextFromHeadersDetectsTheFormat(t *testing.T) {
+	logtest.Capture(t)
+	for name, headers := range map[string]map[string]string{
+		"w3c":     {"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+		"b3":      {"b3": "0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-1-05e3ac9a4f6e3b90"},
+		"b3multi": {"X-B3-TraceId": "0af7651916cd43dd8448eb211c80319c", "X-B3-SpanId": "b7ad6b7169203331", "X-B3-Sampled": "1"},
+		"jaeger":  {"uber-trace-id": "af7651916cd43dd8448eb211c80319c:b7ad6b7169203331:0:1"},
+	} {
+		traceContext, ok := log.TraceContextFromHeaders(headers)
+
+		assert.True(t, ok, name)
+		assert.Equal(t, log.TraceContext{TraceId: "0af7651916cd43dd8448eb211c80319c", SpanId: "b7ad6b7169203331", Sampled: true}, traceContext, name)
+	}
+}
+
+func TestTraceContextFromHeadersPadsShortIds(t *testing.T) {
+	logtest.Capture(t)
+	traceContext, ok := log.TraceContextFromHeaders(map[string]string{"x-b3-traceid": "8448eb211c80319c", "x-b3-spanid": "b7ad6b7169203331", "x-b3-flags": "1"})
+	assert.True(t, ok)
+	assert.Equal(t, log.TraceContext{TraceId: "00000000000000008448eb211c80319c", SpanId: "b7ad6b7169203331", Sampled: true}, traceContext)
+
+	traceContext, err := log.ParseJaeger("8448eb211c80319c%3A69203331%3A0%3A0")
+	assert.NoError(t, err)
+	assert.Equal(t, log.TraceContext{TraceId: "00000000000000008448eb211c80319c", SpanId: "0000000069203331"}, traceContext)
+}
+
+func TestParseMalformedTraceHeaders(t *testing.T) {
+	for _, b3 := range []string{"", "1", "0af7651916cd43dd8448eb211c80319c", "0af7651916cd43dd8448eb211c80319c-00000000000000000", "0af7651916cd-b7ad6b7169203331-1"} {
+		_, err := log.ParseB3(b3)
+		assert.Error(t, err, b3)
+	}
+	for _, uberTraceId := range []string{"", "0:b7ad6b7169203331:0:1", "0af7651916cd43dd8448eb211c80319c:b7ad6b7169203331:0", "0af7651916cd43dd:b7ad6b7169203331:0:x"} {
+		_, err := log.ParseJaeger(uberTraceId)
+		assert.Error(t, err, uberTraceId)
+	}
+}
+
+func TestWithTracePropagation(t *testing.T) {
+	logtest.Capture(t)
+	log.Init(log.GetConfiguration().WithTracePropagation(log.B3MultiPropagation, log.JaegerPropagation))
+	traceParent := map[string]string{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}
+
+	_, ok := log.TraceContextFromHeaders(traceParent)
+	assert.False(t, ok)
+
+	ctx := log.ContextWithTraceContext(context.Background(), log.TraceContext{TraceId: "1-5759e988-bd862e3fe1be46a994272793", SpanId: "53995c3f42cd8ad8", Sampled: true})
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	log.InjectTraceContextHeaders(ctx, request)
+
+	assert.Empty(t, request.Header.Get(log.TraceParentHeader))
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793", request.Header.Get(log.B3TraceIdHeader))
+	assert.Equal(t, "53995c3f42cd8ad8", request.Header.Get(log.B3SpanIdHeader))
+	assert.Equal(t, "1", request.Header.Get(log.B3SampledHeader))
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793:53995c3f42cd8ad8:0:1", request.Header.Get(log.JaegerTraceHeader))
+}
+
+func TestParseTracePropagation(t *testing.T) {
+	propagation, err := log.ParseTracePropagation(" B3Multi")
+	assert.NoError(t, err)
+	assert.Equal(t, log.B3MultiPropagation, propagation)
+	assert.Equal(t, "b3multi", propagation.String())
+
+	_, err = log.ParseTracePropagation("zipkin")
+	assert.EqualError(t, err, `unknown trace propagation "zipkin", expected w3c, b3, b3multi or jaeger`)
+}
@@ -0,0 +1,142 @@
+package log
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/errorUtils"
+	"sync"
+)
+
+// TracingProvider is the tracing backend the package reads the trace context of the records from, and starts
+// the spans of Trace, TraceCapture and LogQuery with, e.g. XRayTracing, OTelTracing or both with CompositeTracing.
+type TracingProvider interface {
+	// SetUp configures the backend, it's called by SetUpTracing.
+	SetUp() error
+	// TraceContext returns the trace context of the active span or trace header of ctx.
+	TraceContext(ctx context.Context) (TraceContext, bool)
+	// StartSpan starts a child span of ctx, it returns a nil Span when there is no trace to attach it to.
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+	// SpanFromContext returns the active span of ctx, nil when there is none.
+	SpanFromContext(ctx context.Context) Span
+}
+
+// Span is a span, or X-Ray subsegment, started by a TracingProvider.
+type Span interface {
+	Id() string
+	// Annotate adds an indexed attribute, the annotation of an X-Ray segment.
+	Annotate(key string, value interface{})
+	// AddMetadata adds a non indexed attribute under namespace.
+	AddMetadata(namespace, key string, value interface{})
+	// End ends the span, failed with err when it's not nil.
+	End(err error)
+}
+
+// WithTracing sets the TracingProvider set up by Init, XRayTracing by default.
+func (c Configuration) WithTracing(provider TracingProvider) Configuration {
+	c.tracing = provider
+	return c
+}
+
+var tracing struct {
+	sync.RWMutex
+	provider TracingProvider
+}
+
+// SetUpTracing makes provider the tracing backend of the package and sets it up, errors are logged.
+// Init calls it with the provider of WithTracing.
+func SetUpTracing(provider TracingProvider) {
+	if provider == nil {
+		provider = XRayTracing()
+	}
+	tracing.Lock()
+	tracing.provider = provider
+	tracing.Unlock()
+	if err := provider.SetUp(); err != nil {
+		logger().Errorf("unable to set up tracing: %+v", err)
+	}
+}
+
+// tracingProvider returns the provider of the last SetUpTracing, XRayTracing before the first one.
+func tracingProvider() TracingProvider {
+	tracing.RLock()
+	defer tracing.RUnlock()
+	if tracing.provider == nil {
+		return XRayTracing()
+	}
+	return tracing.provider
+}
+
+type compositeTracing []TracingProvider
+
+// CompositeTracing combines providers, e.g. while migrating from X-Ray to OpenTelemetry: the trace context is
+// the one of the first provider finding one, and spans are started with every provider finding a trace.
+func CompositeTracing(providers ...TracingProvider) TracingProvider {
+	return compositeTracing(providers)
+}
+
+func (c compositeTracing) SetUp() error {
+	var errs []error
+	for _, provider := range c {
+		errs = append(errs, provider.SetUp())
+	}
+	return errorUtils.MergeErrors(errs)
+}
+
+func (c compositeTracing) TraceContext(ctx context.Context) (TraceContext, bool) {
+	for _, provider := range c {
+		if traceContext, ok := provider.TraceContext(ctx); ok {
+			return traceContext, true
+		}
+	}
+	return TraceContext{}, false
+}
+
+func (c compositeTracing) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	var spans compositeSpan
+	for _, provider := range c {
+		var span Span
+		if ctx, span = provider.StartSpan(ctx, name); span != nil {
+			spans = append(spans, span)
+		}
+	}
+	if len(spans) == 0 {
+		return ctx, nil
+	}
+	return ctx, spans
+}
+
+func (c compositeTracing) SpanFromContext(ctx context.Context) Span {
+	var spans compositeSpan
+	for _, provider := range c {
+		if span := provider.SpanFromContext(ctx); span != nil {
+			spans = append(spans, span)
+		}
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+	return spans
+}
+
+type compositeSpan []Span
+
+func (s compositeSpan) Id() string {
+	return s[0].Id()
+}
+
+func (s compositeSpan) Annotate(key string, value interface{}) {
+	for _, span := range s {
+		span.Annotate(key, value)
+	}
+}
+
+func (s compositeSpan) AddMetadata(namespace, key string, value interface{}) {
+	for _, span := range s {
+		span.AddMetadata(namespace, key, value)
+	}
+}
+
+func (s compositeSpan) End(err error) {
+	for i := len(s) - 1; i >= 0; i-- {
+		s[i].End(err)
+	}
+}
@@ -0,0 +1,106 @@
+package log_test
+
+import (
+	"context"
+	"errors"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+type fakeSpanKey struct{}
+
+type fakeSpan struct {
+	spanContext log.OTelSpanContext
+	name        string
+	attributes  map[string]interface{}
+	err         error
+	ended       bool
+}
+
+func (s *fakeSpan) SpanContext() log.OTelSpanContext           { return s.spanContext }
+func (s *fakeSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *fakeSpan) RecordError(err error)                      { s.err = err }
+func (s *fakeSpan) End()                                       { s.ended = true }
+
+type fakeTracer struct {
+	started []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, spanName string) (context.Context, log.OTelSpan) {
+	parent := t.SpanFromContext(ctx).SpanContext()
+	span := &fakeSpan{
+		spanContext: log.OTelSpanContext{TraceId: parent.TraceId, SpanId: "00f067aa0ba902b7", Sampled: parent.Sampled},
+		name:        spanName,
+		attributes:  map[string]interface{}{},
+	}
+	t.started = append(t.started, span)
+	return context.WithValue(ctx, fakeSpanKey{}, span), span
+}
+
+func (t *fakeTracer) SpanFromContext(ctx context.Context) log.OTelSpan {
+	if span, ok := ctx.Value(fakeSpanKey{}).(*fakeSpan); ok {
+		return span
+	}
+	return nil
+}
+
+func otelContext() context.Context {
+	return context.WithValue(context.Background(), fakeSpanKey{}, &fakeSpan{
+		spanContext: log.OTelSpanContext{TraceId: "4bf92f3577b34da6a3ce929d0e0e4736", SpanId: "a2fb4a1d1a96d312", Sampled: true},
+		attributes:  map[string]interface{}{},
+	})
+}
+
+func TestOTelTracing(t *testing.T) {
+	tracer := &fakeTracer{}
+	recorder := logtest.Capture(t)
+	log.Init(log.GetConfiguration().WithTracing(log.OTelTracing(tracer)).WithXRayAnnotations(log.TenantId))
+	defer log.SetUpTracing(log.XRayTracing())
+	defer log.ResetInvocation()
+
+	ctx := log.SetupTraceIds(otelContext())
+	ctx, err := log.WithTenant(ctx, "tenant-1", "")
+	assert.NoError(t, err)
+	err = log.Trace(ctx, "load-booking", func(ctx context.Context) error {
+		traceContext, _ := log.TraceContextFromContext(ctx)
+		assert.Equal(t, "00f067aa0ba902b7", traceContext.SpanId)
+		return errors.New("booking not found")
+	})
+
+	assert.EqualError(t, err, "booking not found")
+	recorder.AssertField(log.TraceId, "4bf92f3577b34da6a3ce929d0e0e4736")
+	recorder.AssertLogged(zapcore.ErrorLevel, "Subsegment failed")
+	recorder.AssertField(log.SubsegmentId, "00f067aa0ba902b7")
+	assert.Len(t, tracer.started, 1)
+	span := tracer.started[0]
+	assert.Equal(t, "load-booking", span.name)
+	assert.Equal(t, "tenant-1", span.attributes[log.TenantId])
+	assert.EqualError(t, span.err, "booking not found")
+	assert.True(t, span.ended)
+}
+
+func TestCompositeTracing(t *testing.T) {
+	tracer := &fakeTracer{}
+	log.SetUpTracing(log.CompositeTracing(log.XRayTracing(), log.OTelTracing(tracer)))
+	defer log.SetUpTracing(log.XRayTracing())
+
+	traceContext, ok := log.TraceContextFromContext(otelContext())
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceContext.TraceId)
+
+	ctx := context.WithValue(otelContext(), xray.LambdaTraceHeaderKey, "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+	traceContext, _ = log.TraceContextFromContext(ctx)
+	assert.Equal(t, "1-5759e988-bd862e3fe1be46a994272793", traceContext.TraceId)
+
+	var segment *xray.Segment
+	assert.NoError(t, log.Trace(ctx, "load-booking", func(ctx context.Context) error {
+		segment = xray.GetSegment(ctx)
+		return nil
+	}))
+	assert.NotNil(t, segment)
+	assert.Len(t, tracer.started, 1)
+}
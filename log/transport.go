@@ -0,0 +1,94 @@
+package log
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+type retryCountKey struct{}
+
+// WithRetryCount returns a copy of ctx telling HTTPTransport and the other client integrations that the call is the
+// retries-th retry of the same request, retry loops should set it before every attempt.
+func WithRetryCount(ctx context.Context, retries int) context.Context {
+	return context.WithValue(ctx, retryCountKey{}, retries)
+}
+
+// RetryCountFromContext returns the retry count set by WithRetryCount, 0 when there is none.
+func RetryCountFromContext(ctx context.Context) int {
+	retries, _ := ctx.Value(retryCountKey{}).(int)
+	return retries
+}
+
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+// HTTPTransport returns a http.RoundTripper logging every outbound request sent through next, http.DefaultTransport
+// when nil, with its method, url, status, duration and retry count, see WithRetryCount. The values of the query
+// parameters are redacted and the password of the url is dropped. The trace context, correlation id and baggage of the request context
+// are injected in its headers. Failed requests and 5xx responses are logged at WARN, the rest at INFO.
+func HTTPTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &loggingTransport{next: next}
+}
+
+func (t *loggingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	outgoing := request.Clone(ctx)
+	InjectTraceContextHeaders(ctx, outgoing)
+	InjectCorrelationIdHeader(ctx, outgoing)
+	InjectBaggageHeader(ctx, outgoing)
+
+	start := time.Now()
+	response, err := t.next.RoundTrip(outgoing)
+	fields := []interface{}{
+		ClientRequestMethod, request.Method,
+		ClientRequestUrl, redactUrl(request.URL),
+		ClientRequestDuration, time.Since(start),
+		ClientRetryCount, RetryCountFromContext(ctx),
+	}
+
+	logger := enrichedLogger(ctx)
+	switch {
+	case err != nil:
+		logger.Warnw("HTTP request failed", append(fields, ErrorFields(err)...)...)
+	case response.StatusCode >= http.StatusInternalServerError:
+		logger.Warnw("HTTP request failed", append(fields, ClientResponseStatus, response.StatusCode)...)
+	default:
+		logger.Infow("HTTP request", append(fields, ClientResponseStatus, response.StatusCode)...)
+	}
+	return response, err
+}
+
+// redactUrl returns u without its password and with the values of its query parameters replaced by Redacted.
+func redactUrl(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	redacted := *u
+	if _, hasPassword := u.User.Password(); hasPassword {
+		redacted.User = url.User(u.User.Username())
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		keys := make([]string, 0, len(query))
+		for key := range query {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var params []string
+		for _, key := range keys {
+			for range query[key] {
+				params = append(params, url.QueryEscape(key)+"="+Redacted)
+			}
+		}
+		redacted.RawQuery = strings.Join(params, "&")
+	}
+	return redacted.String()
+}
@@ -0,0 +1,106 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	"unicode/utf8"
+)
+
+// CloudWatchMaxEventSize is the largest event CloudWatch Logs accepts, bigger log lines are split into several events.
+const CloudWatchMaxEventSize = 256*1024 - 26
+
+// smallest length string values are truncated to before giving up on fitting a record
+const minTruncatedLength = 64
+
+const truncatedSuffix = "..."
+
+// WithMaxEntrySize truncates records longer than maxSize bytes, e.g. CloudWatchMaxEventSize.
+// The longest string values of the record are cut, halving their length until it fits, and the Truncated field is set.
+func (c Configuration) WithMaxEntrySize(maxSize int) Configuration {
+	c.maxEntrySize = maxSize
+	return c
+}
+
+type truncatingEncoder struct {
+	zapcore.Encoder
+	maxSize int
+}
+
+func wrapTruncating(config Configuration, encoder zapcore.Encoder) zapcore.Encoder {
+	if config.maxEntrySize <= 0 {
+		return encoder
+	}
+	return &truncatingEncoder{Encoder: encoder, maxSize: config.maxEntrySize}
+}
+
+func (e *truncatingEncoder) Clone() zapcore.Encoder {
+	return &truncatingEncoder{Encoder: e.Encoder.Clone(), maxSize: e.maxSize}
+}
+
+func (e *truncatingEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	encoded, err := e.Encoder.EncodeEntry(entry, fields)
+	if err != nil || encoded.Len() <= e.maxSize {
+		return encoded, err
+	}
+	for limit := e.maxSize / 2; limit >= minTruncatedLength; limit /= 2 {
+		encoded.Free()
+		encoded, err = e.Encoder.EncodeEntry(truncateEntry(entry, limit), truncateFields(fields, limit))
+		if err != nil || encoded.Len() <= e.maxSize {
+			return encoded, err
+		}
+	}
+	// the fields added with With alone exceed the limit
+	return encoded, nil
+}
+
+func truncateEntry(entry zapcore.Entry, limit int) zapcore.Entry {
+	entry.Message = truncateString(entry.Message, limit)
+	entry.Stack = truncateString(entry.Stack, limit)
+	return entry
+}
+
+func truncateFields(fields []zapcore.Field, limit int) []zapcore.Field {
+	truncated := make([]zapcore.Field, 0, len(fields)+1)
+	for _, field := range fields {
+		truncated = append(truncated, truncateField(field, limit))
+	}
+	return append(truncated, zap.Bool(Truncated, true))
+}
+
+// truncateField cuts the value of string-like fields, values encoded as json are truncated as a json string.
+func truncateField(field zapcore.Field, limit int) zapcore.Field {
+	switch field.Type {
+	case zapcore.StringType:
+		field.String = truncateString(field.String, limit)
+	case zapcore.ByteStringType:
+		if value := string(field.Interface.([]byte)); len(value) > limit {
+			return zap.String(field.Key, truncateString(value, limit))
+		}
+	case zapcore.StringerType:
+		return zap.String(field.Key, truncateString(field.Interface.(interface{ String() string }).String(), limit))
+	case zapcore.ErrorType:
+		return zap.String(field.Key, truncateString(field.Interface.(error).Error(), limit))
+	case zapcore.ReflectType:
+		if value := ToString(field.Interface); len(value) > limit {
+			return zap.String(field.Key, truncateString(value, limit))
+		}
+	case zapcore.ArrayMarshalerType, zapcore.ObjectMarshalerType:
+		encoder := zapcore.NewMapObjectEncoder()
+		field.AddTo(encoder)
+		if value := ToString(encoder.Fields[field.Key]); len(value) > limit {
+			return zap.String(field.Key, truncateString(value, limit))
+		}
+	}
+	return field
+}
+
+func truncateString(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	for limit > 0 && !utf8.RuneStart(value[limit]) {
+		limit--
+	}
+	return value[:limit] + truncatedSuffix
+}
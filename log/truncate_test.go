@@ -0,0 +1,39 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"testing"
+)
+
+func TestMaxEntrySize(t *testing.T) {
+	var buffer bytes.Buffer
+	config := log.NewConfiguration(
+		"DEBUG",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix").
+		WithWriters(zapcore.AddSync(&buffer)).
+		WithMaxEntrySize(2048)
+	log.Init(config)
+	log.InfoW("Small msg", log.EventBody, "small")
+	log.InfoW("Large msg", log.EventBody, strings.Repeat("a", 10000), "list", []string{strings.Repeat("b", 10000)})
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.NotContains(t, lines[0], log.Truncated)
+
+	assert.True(t, len(lines[1]) <= 2048)
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, true, record[log.Truncated])
+	assert.Equal(t, "Large msg", record[log.Message])
+	assert.True(t, strings.HasSuffix(record[log.EventBody].(string), "..."))
+	assert.True(t, strings.HasSuffix(record["list"].(string), "..."))
+}
@@ -0,0 +1,75 @@
+package log_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"io/ioutil"
+	"testing"
+)
+
+func TestInfo2(t *testing.T) {
+	var buffer bytes.Buffer
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithWriters(zapcore.AddSync(&buffer)))
+	log.With("stage", "test")
+
+	log.Debug2("Debug msg", zap.String("orderId", "1"))
+	log.Info2("Record processed", zap.String("orderId", "1"), zap.Int("size", 10))
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(bytes.TrimSpace(buffer.Bytes()), &entry))
+	assert.Equal(t, "Record processed", entry[log.Message])
+	assert.Equal(t, "1", entry["orderId"])
+	assert.Equal(t, 10.0, entry["size"])
+	assert.Equal(t, "test", entry["stage"])
+	assert.Contains(t, entry[log.Caller], "typed_test.go")
+}
+
+func benchmarkConfig() log.Configuration {
+	return log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "").
+		WithoutSampling().
+		WithWriters(zapcore.AddSync(ioutil.Discard))
+}
+
+func BenchmarkInfoW(b *testing.B) {
+	log.Init(benchmarkConfig())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.InfoW("Record processed", "shardId", "shard-1", "sequenceNumber", "49590338271490256608559692538361571095921575989136588898", "size", 1024)
+	}
+}
+
+func BenchmarkInfo2(b *testing.B) {
+	log.Init(benchmarkConfig())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info2("Record processed",
+			zap.String("shardId", "shard-1"),
+			zap.String("sequenceNumber", "49590338271490256608559692538361571095921575989136588898"),
+			zap.Int("size", 1024))
+	}
+}
+
+func BenchmarkDebugWDisabled(b *testing.B) {
+	log.Init(benchmarkConfig())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.DebugW("Record processed", "shardId", "shard-1", "size", 1024)
+	}
+}
+
+func BenchmarkDebug2Disabled(b *testing.B) {
+	log.Init(benchmarkConfig())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Debug2("Record processed", zap.String("shardId", "shard-1"), zap.Int("size", 1024))
+	}
+}
@@ -0,0 +1,47 @@
+package log
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DurationField returns a field holding d in milliseconds, whatever the duration encoding of the logger,
+// so every service reports durations in the same unit.
+func DurationField(key string, d time.Duration) zap.Field {
+	return zap.Float64(key, float64(d)/float64(time.Millisecond))
+}
+
+// BytesField returns a field holding a size in bytes.
+func BytesField(key string, bytes int64) zap.Field {
+	return zap.Int64(key, bytes)
+}
+
+// MoneyField returns a field holding an object with the amount and the upper cased ISO 4217 code of its currency,
+// e.g. {"amount":12.5,"currency":"EUR"}.
+func MoneyField(key string, amount float64, currency string) zap.Field {
+	return zap.Object(key, money{amount: amount, currency: strings.ToUpper(currency)})
+}
+
+type money struct {
+	amount   float64
+	currency string
+}
+
+func (m money) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	encoder.AddFloat64("amount", m.amount)
+	encoder.AddString("currency", m.currency)
+	return nil
+}
+
+// URLField returns a field holding rawURL without its password and with the values of its query parameters redacted,
+// like the urls logged by HTTPTransport. A url that can't be parsed is replaced by Redacted, it may hold credentials.
+func URLField(key string, rawURL string) zap.Field {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return zap.String(key, Redacted)
+	}
+	return zap.String(key, redactUrl(u))
+}
@@ -0,0 +1,89 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Ryanair/gofrlib/errorUtils"
+	"sort"
+)
+
+// Validate reports every problem of the configuration, one per line.
+func (c Configuration) Validate() error {
+	var errs []error
+	if _, err := parseLevel(c.logLevel); err != nil {
+		errs = append(errs, fmt.Errorf("invalid log level %q, expected one of TRACE, DEBUG, INFO, WARN, ERROR, DPANIC, PANIC or FATAL", c.logLevel))
+	}
+	for _, path := range c.outputPaths {
+		if path == "" {
+			errs = append(errs, errors.New("output paths can't be empty"))
+		}
+	}
+	for _, path := range c.audit.paths {
+		if path == "" {
+			errs = append(errs, errors.New("audit output paths can't be empty"))
+		}
+	}
+	for _, file := range c.rotatingFiles {
+		if file.path == "" {
+			errs = append(errs, errors.New("rotating file path can't be empty"))
+		}
+		if file.options.MaxSize < 0 || file.options.MaxAge < 0 || file.options.MaxBackups < 0 {
+			errs = append(errs, fmt.Errorf("rotation options of %s can't be negative, got %+v", file.path, file.options))
+		}
+	}
+	if c.syslog != nil {
+		errs = append(errs, c.syslog.validate())
+	}
+	if c.sampling.configured {
+		errs = append(errs, c.sampling.sampling.validate("sampling"))
+	}
+	for _, l := range sampledLevels {
+		if s, ok := c.sampling.levels[l]; ok {
+			errs = append(errs, s.validate(levelString(l)+" sampling"))
+		}
+	}
+	if c.deduplication < 0 {
+		errs = append(errs, fmt.Errorf("deduplication window can't be negative, got %s", c.deduplication))
+	}
+	if c.maxEntrySize < 0 || c.maxEntrySize > 0 && c.maxEntrySize < 2*minTruncatedLength {
+		errs = append(errs, fmt.Errorf("max entry size must be at least %d bytes, got %d", 2*minTruncatedLength, c.maxEntrySize))
+	}
+	keys := make([]string, 0, len(c.fieldNames))
+	for key := range c.fieldNames {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if c.fieldNames[key] == "" {
+			errs = append(errs, fmt.Errorf("field name of %s can't be empty", key))
+		}
+	}
+	for _, rule := range c.redaction {
+		errs = append(errs, rule.validate())
+	}
+	errs = append(errs, c.validateNamespaceLevels()...)
+	errs = append(errs, c.validateCaller())
+	if c.errorBudget.threshold < 0 || c.errorBudget.window < 0 {
+		errs = append(errs, fmt.Errorf("error budget threshold and window can't be negative, got %d and %s", c.errorBudget.threshold, c.errorBudget.window))
+	}
+	if c.runtimeStats.interval < 0 {
+		errs = append(errs, fmt.Errorf("runtime stats interval can't be negative, got %s", c.runtimeStats.interval))
+	}
+	if c.invocationSampling < 0 || c.invocationSampling > 1 {
+		errs = append(errs, fmt.Errorf("invocation sampling rate must be between 0 and 1, got %v", c.invocationSampling))
+	}
+	if c.timeoutWarning < 0 || c.timeoutWarning > 1 {
+		errs = append(errs, fmt.Errorf("timeout warning threshold must be between 0 and 1, got %v", c.timeoutWarning))
+	}
+	if err := errorUtils.MergeErrors(errs); err != nil {
+		return fmt.Errorf("invalid log configuration:\n%v", err)
+	}
+	return nil
+}
+
+func (s sampling) validate(name string) error {
+	if !s.disabled && (s.initial <= 0 || s.thereafter <= 0) {
+		return fmt.Errorf("%s initial and thereafter must be positive, got %d and %d", name, s.initial, s.thereafter)
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package log
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const wideEventMessage = "Invocation summary"
+
+// WideEventMode tells whether StartWideEvent emits a wide event and what happens to the other records meanwhile.
+type WideEventMode int
+
+const (
+	// WideEventsOff disables the wide events, the default.
+	WideEventsOff WideEventMode = iota
+	// WideEventsAlongside emits the wide event besides the incremental records.
+	WideEventsAlongside
+	// WideEventsOnly emits the wide event instead of the incremental records below WARN, which are only counted.
+	WideEventsOnly
+)
+
+// WithWideEvents enables the "canonical log line" of every invocation: StartWideEvent accumulates the fields added
+// during the invocation by AddEventFields, IncrementEventCount and AddEventDuration, and emits them at
+// completion in a single "Invocation summary" record, the wide events Honeycomb recommends querying.
+func (c Configuration) WithWideEvents(mode WideEventMode) Configuration {
+	c.wideEvents = mode
+	return c
+}
+
+type wideEventKey struct{}
+
+type wideEvent struct {
+	mutex     sync.Mutex
+	start     time.Time
+	fields    []zap.Field
+	counts    map[string]int
+	durations map[string]time.Duration
+	records   map[zapcore.Level]int
+}
+
+// activeEvent is the wide event of the current invocation, counting the records of the package logger.
+var activeEvent atomic.Value
+
+func currentEvent() *wideEvent {
+	event, _ := activeEvent.Load().(*wideEvent)
+	return event
+}
+
+func eventFromContext(ctx context.Context) *wideEvent {
+	if ctx != nil {
+		if event, ok := ctx.Value(wideEventKey{}).(*wideEvent); ok {
+			return event
+		}
+	}
+	return currentEvent()
+}
+
+// StartWideEvent starts the wide event of the invocation of ctx when enabled by WithWideEvents, and returns a copy
+// of ctx carrying it, for the goroutines of the invocation, and the function emitting it, which must be called
+// with the result of the handler when it returns. The event holds the invocation fields of ctx, the
+// InvocationDuration, the WideEventStatus, ok or error, the fields of err, the number of records logged by level
+// meanwhile and the counts and durations added to it.
+func StartWideEvent(ctx context.Context) (context.Context, func(err error)) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if GetConfiguration().wideEvents == WideEventsOff {
+		return ctx, func(error) {}
+	}
+	event := &wideEvent{
+		start:     time.Now(),
+		counts:    map[string]int{},
+		durations: map[string]time.Duration{},
+		records:   map[zapcore.Level]int{},
+	}
+	activeEvent.Store(event)
+	ctx = context.WithValue(ctx, wideEventKey{}, event)
+	var once sync.Once
+	return ctx, func(err error) {
+		once.Do(func() {
+			if currentEvent() == event {
+				activeEvent.Store((*wideEvent)(nil))
+			}
+			event.emit(ctx, err)
+		})
+	}
+}
+
+// AddEventFields adds fields to the wide event of ctx, replacing the fields with the same key.
+func AddEventFields(ctx context.Context, keysAndValues ...interface{}) {
+	if event := eventFromContext(ctx); event != nil {
+		event.mutex.Lock()
+		event.fields = appendFields(event.fields, keysAndValues)
+		event.mutex.Unlock()
+	}
+}
+
+// IncrementEventCount adds delta to the count name of the wide event of ctx, e.g. the number of queries.
+func IncrementEventCount(ctx context.Context, name string, delta int) {
+	if event := eventFromContext(ctx); event != nil {
+		event.mutex.Lock()
+		event.counts[name] += delta
+		event.mutex.Unlock()
+	}
+}
+
+// AddEventDuration adds d to the duration name of the wide event of ctx, e.g. the time spent in DynamoDB.
+func AddEventDuration(ctx context.Context, name string, d time.Duration) {
+	if event := eventFromContext(ctx); event != nil {
+		event.mutex.Lock()
+		event.durations[name] += d
+		event.mutex.Unlock()
+	}
+}
+
+func (e *wideEvent) count(level zapcore.Level) {
+	e.mutex.Lock()
+	e.records[level]++
+	e.mutex.Unlock()
+}
+
+func (e *wideEvent) emit(ctx context.Context, err error) {
+	e.mutex.Lock()
+	// the maps are copied, goroutines of the invocation may still be adding to them
+	records := levelCounts{}
+	for level, count := range e.records {
+		records[level] = count
+	}
+	counts := eventCounts{}
+	for name, count := range e.counts {
+		counts[name] = count
+	}
+	durations := eventDurations{}
+	for name, d := range e.durations {
+		durations[name] = d
+	}
+	fields := append(fieldsToKeysAndValues(e.fields),
+		InvocationDuration, time.Since(e.start),
+		zap.Object(WideEventRecords, records),
+		zap.Object(WideEventCounts, counts),
+		zap.Object(WideEventDurations, durations))
+	e.mutex.Unlock()
+	logger := FromContext(ctx)
+	if err != nil {
+		logger.Errorw(wideEventMessage, append(append(fields, WideEventStatus, "error"), ErrorFields(err)...)...)
+		return
+	}
+	logger.Infow(wideEventMessage, append(fields, WideEventStatus, "ok")...)
+}
+
+type levelCounts map[zapcore.Level]int
+
+func (c levelCounts) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	for _, level := range []zapcore.Level{TraceLevel, zapcore.DebugLevel, zapcore.InfoLevel, zapcore.WarnLevel,
+		zapcore.ErrorLevel, zapcore.DPanicLevel, zapcore.PanicLevel, zapcore.FatalLevel} {
+		if count := c[level]; count > 0 {
+			encoder.AddInt(levelString(level), count)
+		}
+	}
+	return nil
+}
+
+type eventCounts map[string]int
+
+func (c eventCounts) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		encoder.AddInt(name, c[name])
+	}
+	return nil
+}
+
+// eventDurations encodes the durations in milliseconds, like DurationField.
+type eventDurations map[string]time.Duration
+
+func (d eventDurations) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	names := make([]string, 0, len(d))
+	for name := range d {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		encoder.AddFloat64(name, float64(d[name])/float64(time.Millisecond))
+	}
+	return nil
+}
+
+// wideEventCore counts the records logged during the wide event and, with WideEventsOnly, drops the ones below WARN.
+type wideEventCore struct {
+	zapcore.Core
+	mode WideEventMode
+}
+
+func (c *wideEventCore) With(fields []zapcore.Field) zapcore.Core {
+	return &wideEventCore{Core: c.Core.With(fields), mode: c.mode}
+}
+
+func (c *wideEventCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	event := currentEvent()
+	if event == nil || entry.Message == wideEventMessage {
+		return c.Core.Check(entry, checked)
+	}
+	event.count(entry.Level)
+	if c.mode == WideEventsOnly && entry.Level < zapcore.WarnLevel {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -0,0 +1,70 @@
+package log_test
+
+import (
+	"context"
+	"errors"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+	"time"
+)
+
+func TestWideEventAlongsideRecords(t *testing.T) {
+	recorder := logtest.Capture(t)
+	log.Init(log.GetConfiguration().WithWideEvents(log.WideEventsAlongside))
+
+	ctx, finish := log.StartWideEvent(context.Background())
+	log.Info("Info msg during the invocation")
+	log.Warn("Warn msg during the invocation")
+	log.AddEventFields(ctx, "customerId", "test-customer")
+	log.IncrementEventCount(ctx, "queries", 2)
+	log.IncrementEventCount(ctx, "queries", 1)
+	log.AddEventDuration(ctx, "dynamodb", 15*time.Millisecond)
+	finish(nil)
+
+	entries := recorder.Entries()
+	assert.Len(t, entries, 3)
+	event := entries[2]
+	assert.Equal(t, "Invocation summary", event.Message)
+	assert.Equal(t, zapcore.InfoLevel, event.Level)
+	assert.Equal(t, "ok", event.Fields[log.WideEventStatus])
+	assert.Equal(t, "test-customer", event.Fields["customerId"])
+	assert.Equal(t, map[string]interface{}{"INFO": 1, "WARN": 1}, event.Fields[log.WideEventRecords])
+	assert.Equal(t, map[string]interface{}{"queries": 3}, event.Fields[log.WideEventCounts])
+	assert.Equal(t, map[string]interface{}{"dynamodb": float64(15)}, event.Fields[log.WideEventDurations])
+	assert.Contains(t, event.Fields, log.InvocationDuration)
+}
+
+func TestWideEventOnlyDropsIncrementalRecords(t *testing.T) {
+	recorder := logtest.Capture(t)
+	log.Init(log.GetConfiguration().WithWideEvents(log.WideEventsOnly))
+
+	_, finish := log.StartWideEvent(context.Background())
+	log.Info("Info msg during the invocation")
+	log.Warn("Warn msg during the invocation")
+	finish(errors.New("handler error"))
+	log.Info("Info msg after the invocation")
+
+	entries := recorder.Entries()
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "Warn msg during the invocation", entries[0].Message)
+	assert.Equal(t, "Invocation summary", entries[1].Message)
+	assert.Equal(t, zapcore.ErrorLevel, entries[1].Level)
+	assert.Equal(t, "error", entries[1].Fields[log.WideEventStatus])
+	assert.Equal(t, "handler error", entries[1].Fields[log.ErrorMessage])
+	assert.Equal(t, map[string]interface{}{"INFO": 1, "WARN": 1}, entries[1].Fields[log.WideEventRecords])
+	assert.Equal(t, "Info msg after the invocation", entries[2].Message)
+}
+
+func TestWideEventOff(t *testing.T) {
+	recorder := logtest.Capture(t)
+	log.Init(log.GetConfiguration().WithWideEvents(log.WideEventsOff))
+
+	ctx, finish := log.StartWideEvent(context.Background())
+	log.AddEventFields(ctx, "customerId", "test-customer")
+	finish(nil)
+
+	assert.Empty(t, recorder.Entries())
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-xray-sdk-go/strategy/ctxmissing"
 	"github.com/aws/aws-xray-sdk-go/xray"
 	"github.com/aws/aws-xray-sdk-go/xraylog"
+	"strings"
 )
 
 type xRayLogger struct {
@@ -15,17 +16,97 @@ type xRayLogger struct {
 func (x *xRayLogger) Log(level xraylog.LogLevel, msg fmt.Stringer) {
 	switch level {
 	case xraylog.LogLevelWarn:
-		log.Warn(msg.String())
+		logger().Warn(msg.String())
 	case xraylog.LogLevelError:
-		log.Error(msg.String())
+		logger().Error(msg.String())
 	}
 }
 
-func setUpXRay() {
+type xRayTracing struct{}
+
+// XRayTracing traces with the X-Ray SDK, getting the trace context from the X-Ray header of the invocation
+// and starting subsegments of the segment of the context.
+func XRayTracing() TracingProvider {
+	return xRayTracing{}
+}
+
+func (xRayTracing) SetUp() error {
+	setupXRayLogger()
 	if err := xray.Configure(xray.Config{ContextMissingStrategy: &ctxmissing.DefaultIgnoreErrorStrategy{}}); err != nil {
-		log.Error("unable to configure xray: %+v", err)
+		return fmt.Errorf("unable to configure xray: %w", err)
 	}
-	setupXRayLogger()
+	return nil
+}
+
+func (xRayTracing) TraceContext(ctx context.Context) (TraceContext, bool) {
+	if traceHeader := getTraceHeaderFromContext(ctx); traceHeader != nil {
+		return xRayTraceContext(traceHeader), true
+	}
+	return TraceContext{}, false
+}
+
+func (xRayTracing) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	subsegmentCtx, subsegment := xray.BeginSubsegment(ctx, name)
+	if subsegment == nil {
+		return ctx, nil
+	}
+	return subsegmentCtx, xRaySpan{subsegment}
+}
+
+func (xRayTracing) SpanFromContext(ctx context.Context) Span {
+	if segment := xray.GetSegment(ctx); segment != nil {
+		return xRaySpan{segment}
+	}
+	return nil
+}
+
+type xRaySpan struct {
+	segment *xray.Segment
+}
+
+func (s xRaySpan) Id() string {
+	return s.segment.ID
+}
+
+func (s xRaySpan) Annotate(key string, value interface{}) {
+	_ = s.segment.AddAnnotation(annotationKey(key), annotationValue(value))
+}
+
+func (s xRaySpan) AddMetadata(namespace, key string, value interface{}) {
+	_ = s.segment.AddMetadataToNamespace(namespace, key, value)
+}
+
+func (s xRaySpan) End(err error) {
+	s.segment.Close(err)
+}
+
+// annotationKey replaces the characters other than letters, digits and underscores, X-Ray doesn't index them.
+func annotationKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// annotationValue converts value to one of the types of the annotations, strings, numbers and booleans.
+func annotationValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bool, string, float64, int:
+		return v
+	case int64:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case float32:
+		return float64(v)
+	}
+	return fmt.Sprint(value)
 }
 
 func setupXRayLogger() {
@@ -0,0 +1,158 @@
+package log
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sync"
+)
+
+// XRayMetadataNamespace is the namespace of the metadata written by WithXRayMetadata.
+const XRayMetadataNamespace = "gofrlib"
+
+// WithXRayAnnotations mirrors the fields with the given keys, e.g. TenantId or "Body.<prefix>.channel" for a custom
+// attribute, as annotations of the X-Ray segments, or attributes of the spans of the tracing provider, see SetUpTracing,
+// so traces are searchable by the same keys as the records.
+// The fields added by With, WithCustomAttr, PutAttr, NewContext and the helpers built on it like WithTenant and
+// the SetUp* helpers are written to the span of the context when there is one, and to the spans started by Trace,
+// TraceCapture and LogQuery. X-Ray annotation keys are written with underscores instead of the characters X-Ray
+// doesn't index, e.g. Body_tenant_id, and values other than strings, numbers and booleans as text. Values are redacted first.
+func (c Configuration) WithXRayAnnotations(keys ...string) Configuration {
+	c.xrayAnnotations = mergeKeys(c.xrayAnnotations, keys)
+	return c
+}
+
+// WithXRayMetadata mirrors the fields with the given keys like WithXRayAnnotations, as metadata of the
+// XRayMetadataNamespace, for values that don't need to be searchable, e.g. objects.
+func (c Configuration) WithXRayMetadata(keys ...string) Configuration {
+	c.xrayMetadata = mergeKeys(c.xrayMetadata, keys)
+	return c
+}
+
+func mergeKeys(keys map[string]bool, added []string) map[string]bool {
+	merged := make(map[string]bool, len(keys)+len(added))
+	for key := range keys {
+		merged[key] = true
+	}
+	for _, key := range added {
+		merged[key] = true
+	}
+	return merged
+}
+
+// xrayMirror holds the configuration of the mirrored fields and the mirrored fields added by With,
+// which outlive the invocations.
+type xrayMirror struct {
+	sync.RWMutex
+	annotations map[string]bool
+	metadata    map[string]bool
+	prefix      string
+	redactor    *redactor
+	environment []zap.Field
+}
+
+var mirror = &xrayMirror{}
+
+func initXRayMirror(config Configuration) {
+	mirror.Lock()
+	defer mirror.Unlock()
+	mirror.annotations = config.xrayAnnotations
+	mirror.metadata = config.xrayMetadata
+	mirror.prefix = config.customAttributesPrefix
+	mirror.redactor = nil
+	if len(config.redaction) > 0 {
+		mirror.redactor = newRedactor(config.redaction)
+	}
+	mirror.environment = nil
+}
+
+func (m *xrayMirror) selected(key string) bool {
+	return m.annotations[key] || m.metadata[key]
+}
+
+// selectedFields returns the fields of keysAndValues to mirror.
+func (m *xrayMirror) selectedFields(keysAndValues []interface{}) []zap.Field {
+	m.RLock()
+	defer m.RUnlock()
+	if len(m.annotations) == 0 && len(m.metadata) == 0 {
+		return nil
+	}
+	var selected []zap.Field
+	for _, field := range appendFields(nil, keysAndValues) {
+		if m.selected(field.Key) {
+			selected = append(selected, field)
+		}
+	}
+	return selected
+}
+
+// with mirrors the fields of With that are selected to the later spans.
+func (m *xrayMirror) with(keysAndValues []interface{}) {
+	selected := m.selectedFields(keysAndValues)
+	if len(selected) == 0 {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	for _, field := range selected {
+		m.environment = replaceField(m.environment, field)
+	}
+}
+
+// mirrorFields writes the selected fields of keysAndValues to the span of ctx, if any.
+func mirrorFields(ctx context.Context, keysAndValues []interface{}) {
+	if ctx == nil {
+		return
+	}
+	if span := tracingProvider().SpanFromContext(ctx); span != nil {
+		mirror.write(span, mirror.selectedFields(keysAndValues))
+	}
+}
+
+// mirrorContext writes the selected fields of the logger and the attributes of ctx, and the ones of With,
+// to span, just started.
+func mirrorContext(ctx context.Context, span Span) {
+	if span == nil {
+		return
+	}
+	mirror.RLock()
+	fields := append([]zap.Field{}, mirror.environment...)
+	prefix := mirror.prefix
+	mirror.RUnlock()
+	logger := loggerFromContext(ctx)
+	keysAndValues := make([]interface{}, 0, len(logger.fields))
+	for _, field := range logger.fields {
+		keysAndValues = append(keysAndValues, field)
+	}
+	if bag := findAttrs(logger.fields); bag != nil {
+		for _, field := range bag.fields(prefix) {
+			keysAndValues = append(keysAndValues, field)
+		}
+	}
+	for _, field := range mirror.selectedFields(keysAndValues) {
+		fields = replaceField(fields, field)
+	}
+	mirror.write(span, fields)
+}
+
+func (m *xrayMirror) write(span Span, fields []zap.Field) {
+	m.RLock()
+	defer m.RUnlock()
+	for _, field := range fields {
+		if m.redactor != nil {
+			field = m.redactor.redactField(field)
+		}
+		encoder := zapcore.NewMapObjectEncoder()
+		field.AddTo(encoder)
+		value, ok := encoder.Fields[field.Key]
+		if !ok {
+			continue
+		}
+		if m.annotations[field.Key] {
+			span.Annotate(field.Key, value)
+		}
+		if m.metadata[field.Key] {
+			span.AddMetadata(XRayMetadataNamespace, field.Key, value)
+		}
+	}
+}
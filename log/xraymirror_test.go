@@ -0,0 +1,40 @@
+package log_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestXRayMirroring(t *testing.T) {
+	log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", "testPrefix").
+		WithXRayAnnotations(log.TenantId, log.Region, "Body.testPrefix.channel", "cardNumber").
+		WithXRayMetadata("order").
+		WithRedaction(log.RedactFields("cardNumber")))
+	defer log.ResetInvocation()
+	ctx := context.WithValue(context.Background(), xray.LambdaTraceHeaderKey, "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+
+	ctx = log.SetupTraceIds(ctx)
+	log.With(log.Region, "eu-west-1")
+	ctx, err := log.WithTenant(ctx, "tenant-1", "")
+	assert.NoError(t, err)
+	log.PutAttr(ctx, "channel", "web")
+	ctx = log.NewContext(ctx, "order", map[string]interface{}{"id": "o-1"}, "cardNumber", "4111111111111111", "ignored", "value")
+
+	var subsegment *xray.Segment
+	assert.NoError(t, log.Trace(ctx, "load-booking", func(ctx context.Context) error {
+		subsegment = xray.GetSegment(ctx)
+		log.NewContext(ctx, log.TenantId, "tenant-2")
+		return nil
+	}))
+
+	assert.Equal(t, map[string]interface{}{
+		"Body_tenant_id":           "tenant-2",
+		"Body_origin_event_region": "eu-west-1",
+		"Body_testPrefix_channel":  "web",
+		"cardNumber":               log.Redacted,
+	}, subsegment.Annotations)
+	assert.Equal(t, map[string]interface{}{"id": "o-1"}, subsegment.Metadata[log.XRayMetadataNamespace]["order"])
+}
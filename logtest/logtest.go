@@ -0,0 +1,98 @@
+package logtest
+
+import (
+	"fmt"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Entry is a record written by the package logger, Fields holds every field including the ones added with With.
+type Entry struct {
+	Level   zapcore.Level
+	Time    time.Time
+	Message string
+	Fields  map[string]interface{}
+}
+
+type Recorder struct {
+	t    testing.TB
+	logs *observer.ObservedLogs
+}
+
+// Capture initializes the package logger with the current configuration, at DEBUG level and without sampling,
+// recording entries in memory instead of writing them. The previous logger is restored when the test finishes.
+func Capture(t testing.TB) *Recorder {
+	initialized := log.IsInitialized()
+	previous := log.GetConfiguration()
+	config := previous
+	if !initialized {
+		config = log.NewConfiguration("DEBUG", "logtest", "", "", "", "")
+	}
+	core, logs := observer.New(zapcore.DebugLevel)
+	log.Init(config.WithoutSampling().WithCore(core))
+	if err := log.SetLevel("DEBUG"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if initialized {
+			log.Init(previous)
+		}
+	})
+	return &Recorder{t: t, logs: logs}
+}
+
+// Entries returns the entries recorded so far, in order.
+func (r *Recorder) Entries() []Entry {
+	logged := r.logs.All()
+	entries := make([]Entry, len(logged))
+	for i, entry := range logged {
+		entries[i] = Entry{
+			Level:   entry.Level,
+			Time:    entry.Time,
+			Message: entry.Message,
+			Fields:  entry.ContextMap(),
+		}
+	}
+	return entries
+}
+
+// Reset forgets the entries recorded so far.
+func (r *Recorder) Reset() {
+	r.logs.TakeAll()
+}
+
+// AssertLogged asserts an entry with level and a message containing msgSubstr was recorded.
+func (r *Recorder) AssertLogged(level zapcore.Level, msgSubstr string) bool {
+	r.t.Helper()
+	for _, entry := range r.Entries() {
+		if entry.Level == level && strings.Contains(entry.Message, msgSubstr) {
+			return true
+		}
+	}
+	return assert.Fail(r.t, fmt.Sprintf("no %s entry containing %q", level.CapitalString(), msgSubstr), r.summary())
+}
+
+// AssertField asserts an entry with the field key set to value was recorded. Values are compared
+// after type conversion, so AssertField(key, 1) matches a field logged as an int64.
+func (r *Recorder) AssertField(key string, value interface{}) bool {
+	r.t.Helper()
+	for _, entry := range r.Entries() {
+		if actual, ok := entry.Fields[key]; ok && assert.ObjectsAreEqualValues(value, actual) {
+			return true
+		}
+	}
+	return assert.Fail(r.t, fmt.Sprintf("no entry with %s=%v", key, value), r.summary())
+}
+
+func (r *Recorder) summary() string {
+	var lines []string
+	for _, entry := range r.Entries() {
+		lines = append(lines, fmt.Sprintf("%s %s %v", entry.Level.CapitalString(), entry.Message, entry.Fields))
+	}
+	return "recorded entries:\n" + strings.Join(lines, "\n")
+}
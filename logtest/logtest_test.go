@@ -0,0 +1,55 @@
+package logtest_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+func TestCapture(t *testing.T) {
+	log.Init(log.NewConfiguration(
+		"ERROR",
+		"TEST-APPLICATION",
+		"TEST-PROJECT",
+		"TEST-PROJECT-GROUP",
+		"1.0.0",
+		"testPrefix"))
+	recorder := logtest.Capture(t)
+
+	log.DebugW("Debug msg", "attempt", 1)
+	log.FromContext(log.NewContext(context.Background(), "requestId", "abc")).Warn("Context msg")
+
+	entries := recorder.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, zapcore.DebugLevel, entries[0].Level)
+	assert.Equal(t, "TEST-APPLICATION", entries[0].Fields[log.Application])
+	recorder.AssertLogged(zapcore.DebugLevel, "Debug")
+	recorder.AssertLogged(zapcore.WarnLevel, "Context")
+	recorder.AssertField("attempt", 1)
+	recorder.AssertField("requestId", "abc")
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Entries())
+}
+
+type failureRecorder struct {
+	testing.TB
+	failed bool
+}
+
+func (f *failureRecorder) Errorf(string, ...interface{}) {
+	f.failed = true
+}
+
+func TestCaptureFailures(t *testing.T) {
+	failures := &failureRecorder{TB: t}
+	recorder := logtest.Capture(failures)
+	log.InfoW("Info msg", "attempt", 1)
+
+	assert.False(t, recorder.AssertLogged(zapcore.ErrorLevel, "Info"))
+	assert.False(t, recorder.AssertField("attempt", 2))
+	assert.True(t, failures.failed)
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sync"
+	"time"
+)
+
+const (
+	BatchRecordsMetric      = "BatchRecords"
+	BatchSucceededMetric    = "BatchSucceeded"
+	BatchRetriedMetric      = "BatchRetried"
+	BatchDeadLetteredMetric = "BatchDeadLettered"
+	BatchDurationMetric     = "BatchDuration"
+)
+
+// RecordOutcome is the outcome of processing a record of a batch.
+type RecordOutcome int
+
+const (
+	RecordSucceeded RecordOutcome = iota
+	RecordRetried
+	RecordDeadLettered
+)
+
+// durationBuckets are the upper bounds of the record duration histogram, the last bucket has none.
+var durationBuckets = []struct {
+	name  string
+	bound time.Duration
+}{
+	{"1ms", time.Millisecond},
+	{"10ms", 10 * time.Millisecond},
+	{"100ms", 100 * time.Millisecond},
+	{"1s", time.Second},
+	{"10s", 10 * time.Second},
+}
+
+// BatchSummary aggregates the outcomes and durations of the records of an SQS or Kinesis batch, so a single
+// summary record and EMF record are written for the whole batch instead of a record per message.
+// It is safe to use from several goroutines.
+type BatchSummary struct {
+	mutex      sync.Mutex
+	dimensions []Dimension
+	start      time.Time
+	outcomes   [3]int
+	histogram  []int
+	total      time.Duration
+	max        time.Duration
+}
+
+// NewBatchSummary starts summarizing a batch, dims are the dimensions of its metrics.
+func NewBatchSummary(dims ...Dimension) *BatchSummary {
+	return &BatchSummary{dimensions: dims, start: time.Now(), histogram: make([]int, len(durationBuckets)+1)}
+}
+
+// Record adds a record processed in duration with outcome.
+func (s *BatchSummary) Record(outcome RecordOutcome, duration time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if outcome >= RecordSucceeded && outcome <= RecordDeadLettered {
+		s.outcomes[outcome]++
+	}
+	bucket := len(durationBuckets)
+	for i, b := range durationBuckets {
+		if duration <= b.bound {
+			bucket = i
+			break
+		}
+	}
+	s.histogram[bucket]++
+	s.total += duration
+	if duration > s.max {
+		s.max = duration
+	}
+}
+
+func (s *BatchSummary) Succeeded(duration time.Duration) {
+	s.Record(RecordSucceeded, duration)
+}
+
+func (s *BatchSummary) Retried(duration time.Duration) {
+	s.Record(RecordRetried, duration)
+}
+
+func (s *BatchSummary) DeadLettered(duration time.Duration) {
+	s.Record(RecordDeadLettered, duration)
+}
+
+// Emit logs a "Batch processed" record with the outcome counts, the record duration histogram, average and maximum
+// and the batch duration, at WARN level when any record was retried or dead lettered and INFO otherwise,
+// and emits the Batch* metrics with the tenant of ctx, see NewRecordFromContext.
+func (s *BatchSummary) Emit(ctx context.Context) {
+	s.mutex.Lock()
+	outcomes := s.outcomes
+	histogram := append([]int{}, s.histogram...)
+	total, max := s.total, s.max
+	s.mutex.Unlock()
+	elapsed := time.Since(s.start)
+
+	records := outcomes[RecordSucceeded] + outcomes[RecordRetried] + outcomes[RecordDeadLettered]
+	var average time.Duration
+	if records > 0 {
+		average = total / time.Duration(records)
+	}
+	fields := []interface{}{
+		log.BatchRecords, records,
+		log.BatchSucceeded, outcomes[RecordSucceeded],
+		log.BatchRetried, outcomes[RecordRetried],
+		log.BatchDeadLettered, outcomes[RecordDeadLettered],
+		log.DurationField(log.BatchDuration, elapsed),
+		log.DurationField(log.BatchRecordAverage, average),
+		log.DurationField(log.BatchRecordMax, max),
+		zap.Object(log.BatchRecordHistogram, durationHistogram(histogram)),
+	}
+	logger := log.FromContext(ctx).Desugar().WithOptions(zap.AddCallerSkip(1)).Sugar()
+	if outcomes[RecordRetried] > 0 || outcomes[RecordDeadLettered] > 0 {
+		logger.Warnw("Batch processed", fields...)
+	} else {
+		logger.Infow("Batch processed", fields...)
+	}
+
+	NewRecordFromContext(ctx, s.dimensions...).
+		Count(BatchRecordsMetric, float64(records)).
+		Count(BatchSucceededMetric, float64(outcomes[RecordSucceeded])).
+		Count(BatchRetriedMetric, float64(outcomes[RecordRetried])).
+		Count(BatchDeadLetteredMetric, float64(outcomes[RecordDeadLettered])).
+		Duration(BatchDurationMetric, elapsed).
+		Emit()
+}
+
+// durationHistogram encodes the counts of every bucket keyed by its upper bound, e.g. {"1ms":3,"10ms":1,"inf":0}.
+type durationHistogram []int
+
+func (h durationHistogram) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	for i, b := range durationBuckets {
+		encoder.AddInt(b.name, h[i])
+	}
+	encoder.AddInt("inf", h[len(durationBuckets)])
+	return nil
+}
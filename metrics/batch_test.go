@@ -0,0 +1,60 @@
+package metrics_test
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/metrics"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBatchSummary(t *testing.T) {
+	records := captureStderr(t, func() {
+		log.Init(log.NewConfiguration(
+			"INFO",
+			"TEST-APPLICATION",
+			"TEST-PROJECT",
+			"TEST-PROJECT-GROUP",
+			"1.0.0",
+			"testPrefix"))
+		summary := metrics.NewBatchSummary(metrics.Dim("Queue", "orders"))
+		summary.Succeeded(500 * time.Microsecond)
+		summary.Succeeded(5 * time.Millisecond)
+		summary.Retried(50 * time.Millisecond)
+		summary.DeadLettered(20 * time.Second)
+		summary.Emit(context.Background())
+	})
+
+	assert.Len(t, records, 2)
+	summary := records[0]
+	assert.Equal(t, "Batch processed", summary[log.Message])
+	assert.Equal(t, "WARN", summary[log.Level])
+	assert.Equal(t, 4.0, summary[log.BatchRecords])
+	assert.Equal(t, 2.0, summary[log.BatchSucceeded])
+	assert.Equal(t, 1.0, summary[log.BatchRetried])
+	assert.Equal(t, 1.0, summary[log.BatchDeadLettered])
+	assert.Equal(t, 20000.0, summary[log.BatchRecordMax])
+	assert.Equal(t, map[string]interface{}{"1ms": 1.0, "10ms": 1.0, "100ms": 1.0, "1s": 0.0, "10s": 0.0, "inf": 1.0},
+		summary[log.BatchRecordHistogram])
+
+	emf := records[1]
+	assert.Equal(t, "orders", emf["Queue"])
+	assert.Equal(t, 4.0, emf[metrics.BatchRecordsMetric])
+	assert.Equal(t, 2.0, emf[metrics.BatchSucceededMetric])
+	assert.Equal(t, 1.0, emf[metrics.BatchRetriedMetric])
+	assert.Equal(t, 1.0, emf[metrics.BatchDeadLetteredMetric])
+	assert.Contains(t, emf, metrics.BatchDurationMetric)
+}
+
+func TestBatchSummaryAllSucceeded(t *testing.T) {
+	records := captureStderr(t, func() {
+		log.Init(log.NewConfiguration("INFO", "TEST-APPLICATION", "", "", "", ""))
+		summary := metrics.NewBatchSummary()
+		summary.Succeeded(time.Millisecond)
+		summary.Emit(context.Background())
+	})
+
+	assert.Equal(t, "INFO", records[0][log.Level])
+	assert.Equal(t, 1.0, records[0][log.BatchRecordAverage])
+}
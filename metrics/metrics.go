@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"context"
+	"github.com/Ryanair/gofrlib/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+const (
+	UnitCount        = "Count"
+	UnitMilliseconds = "Milliseconds"
+	UnitBytes        = "Bytes"
+	UnitNone         = "None"
+
+	emfMessage = "metrics"
+
+	ErrorBudgetExceededMetric = "ErrorBudgetExceeded"
+
+	GoroutinesMetric = "Goroutines"
+	HeapAllocMetric  = "HeapAlloc"
+	GCPauseMetric    = "GCPause"
+	RSSMetric        = "RSS"
+)
+
+var namespace string
+
+// init counts the exhaustions of the error budget of the logger, see log.WithErrorBudget,
+// and emits the runtime stats of log.WithRuntimeStatsMetrics.
+func init() {
+	log.OnErrorBudgetExceeded(func(int) {
+		Count(ErrorBudgetExceededMetric, 1)
+	})
+	log.RegisterRuntimeStatsEmitter(emitRuntimeStats)
+}
+
+func emitRuntimeStats(stats log.RuntimeStats) {
+	record := NewRecord().
+		Gauge(GoroutinesMetric, float64(stats.Goroutines)).
+		Put(HeapAllocMetric, float64(stats.HeapAlloc), UnitBytes).
+		Duration(GCPauseMetric, stats.GCPause)
+	if stats.RSS > 0 {
+		record.Put(RSSMetric, float64(stats.RSS), UnitBytes)
+	}
+	record.Emit()
+}
+
+type Dimension struct {
+	Name  string
+	Value string
+}
+
+func Dim(name, value string) Dimension {
+	return Dimension{Name: name, Value: value}
+}
+
+// SetNamespace overrides the namespace derived from the log configuration ("project/application").
+func SetNamespace(ns string) {
+	namespace = ns
+}
+
+func Namespace() string {
+	if namespace != "" {
+		return namespace
+	}
+	config := log.GetConfiguration()
+	if config.Project() == "" {
+		return config.Application()
+	}
+	return config.Project() + "/" + config.Application()
+}
+
+func Count(name string, value float64, dims ...Dimension) {
+	NewRecord(dims...).Count(name, value).Emit()
+}
+
+func Duration(name string, value time.Duration, dims ...Dimension) {
+	NewRecord(dims...).Duration(name, value).Emit()
+}
+
+func Gauge(name string, value float64, dims ...Dimension) {
+	NewRecord(dims...).Gauge(name, value).Emit()
+}
+
+// Record groups several metrics sharing the same dimensions into a single EMF record.
+type Record struct {
+	dimensions []Dimension
+	properties []zap.Field
+	metrics    []metric
+}
+
+type metric struct {
+	name  string
+	unit  string
+	value float64
+}
+
+func NewRecord(dims ...Dimension) *Record {
+	return &Record{dimensions: dims}
+}
+
+// NewRecordFromContext behaves like NewRecord but the record also carries the tenant of ctx, see log.WithTenant,
+// as properties rather than dimensions so the number of metrics doesn't grow with the number of tenants.
+func NewRecordFromContext(ctx context.Context, dims ...Dimension) *Record {
+	record := NewRecord(dims...)
+	if tenant, ok := log.TenantFromContext(ctx); ok {
+		record.properties = append(record.properties, zap.String(log.TenantId, tenant.Id))
+		if tenant.Account != "" {
+			record.properties = append(record.properties, zap.String(log.TenantAccount, tenant.Account))
+		}
+	}
+	return record
+}
+
+func (r *Record) Count(name string, value float64) *Record {
+	return r.Put(name, value, UnitCount)
+}
+
+func (r *Record) Duration(name string, value time.Duration) *Record {
+	return r.Put(name, float64(value)/float64(time.Millisecond), UnitMilliseconds)
+}
+
+func (r *Record) Gauge(name string, value float64) *Record {
+	return r.Put(name, value, UnitNone)
+}
+
+func (r *Record) Put(name string, value float64, unit string) *Record {
+	r.metrics = append(r.metrics, metric{name: name, unit: unit, value: value})
+	return r
+}
+
+// Emit writes the record in CloudWatch Embedded Metric Format through log.Emit, so it is never sampled or filtered.
+func (r *Record) Emit() {
+	if len(r.metrics) == 0 {
+		return
+	}
+	fields := []zap.Field{zap.Object("_aws", emfMetadata{
+		timestamp: log.Now(),
+		namespace: Namespace(),
+		record:    r,
+	})}
+	for _, dimension := range r.dimensions {
+		fields = append(fields, zap.String(dimension.Name, dimension.Value))
+	}
+	fields = append(fields, r.properties...)
+	for _, m := range r.metrics {
+		fields = append(fields, zap.Float64(m.name, m.value))
+	}
+	log.Emit(emfMessage, fields...)
+}
+
+type emfMetadata struct {
+	timestamp time.Time
+	namespace string
+	record    *Record
+}
+
+func (m emfMetadata) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	encoder.AddInt64("Timestamp", m.timestamp.UnixNano()/int64(time.Millisecond))
+	return encoder.AddArray("CloudWatchMetrics", zapcore.ArrayMarshalerFunc(func(array zapcore.ArrayEncoder) error {
+		return array.AppendObject(zapcore.ObjectMarshalerFunc(func(directive zapcore.ObjectEncoder) error {
+			directive.AddString("Namespace", m.namespace)
+			if err := directive.AddArray("Dimensions", zapcore.ArrayMarshalerFunc(func(dimensionSets zapcore.ArrayEncoder) error {
+				return dimensionSets.AppendArray(zapcore.ArrayMarshalerFunc(func(names zapcore.ArrayEncoder) error {
+					for _, dimension := range m.record.dimensions {
+						names.AppendString(dimension.Name)
+					}
+					return nil
+				}))
+			})); err != nil {
+				return err
+			}
+			return directive.AddArray("Metrics", zapcore.ArrayMarshalerFunc(func(definitions zapcore.ArrayEncoder) error {
+				for _, metric := range m.record.metrics {
+					unit := metric.unit
+					name := metric.name
+					if err := definitions.AppendObject(zapcore.ObjectMarshalerFunc(func(definition zapcore.ObjectEncoder) error {
+						definition.AddString("Name", name)
+						definition.AddString("Unit", unit)
+						return nil
+					})); err != nil {
+						return err
+					}
+				}
+				return nil
+			}))
+		}))
+	}))
+}
@@ -0,0 +1,121 @@
+package metrics_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/metrics"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureStderr runs fn with stderr redirected to a temporary file and returns the logged json records
+func captureStderr(t *testing.T, fn func()) []map[string]interface{} {
+	file, err := ioutil.TempFile("", "metrics")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	stderr := os.Stderr
+	os.Stderr = file
+	fn()
+	os.Stderr = stderr
+
+	content, err := ioutil.ReadFile(file.Name())
+	assert.NoError(t, err)
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestCountEmitsEmfRecord(t *testing.T) {
+	records := captureStderr(t, func() {
+		log.Init(log.NewConfiguration(
+			"ERROR",
+			"TEST-APPLICATION",
+			"TEST-PROJECT",
+			"TEST-PROJECT-GROUP",
+			"1.0.0",
+			"testPrefix"))
+		metrics.Count("OrdersProcessed", 3, metrics.Dim("Queue", "orders"))
+		metrics.NewRecord().
+			Duration("Latency", 1500*time.Microsecond).
+			Gauge("QueueDepth", 42).
+			Emit()
+	})
+
+	assert.Len(t, records, 2)
+	assert.Equal(t, 3.0, records[0]["OrdersProcessed"])
+	assert.Equal(t, "orders", records[0]["Queue"])
+	directive := records[0]["_aws"].(map[string]interface{})["CloudWatchMetrics"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "TEST-PROJECT/TEST-APPLICATION", directive["Namespace"])
+	assert.Equal(t, []interface{}{[]interface{}{"Queue"}}, directive["Dimensions"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"Name": "OrdersProcessed", "Unit": "Count"}}, directive["Metrics"])
+	assert.Equal(t, 1.5, records[1]["Latency"])
+	assert.Equal(t, 42.0, records[1]["QueueDepth"])
+}
+
+func TestNewRecordFromContextCarriesTenant(t *testing.T) {
+	records := captureStderr(t, func() {
+		log.Init(log.NewConfiguration(
+			"ERROR",
+			"TEST-APPLICATION",
+			"TEST-PROJECT",
+			"TEST-PROJECT-GROUP",
+			"1.0.0",
+			"testPrefix"))
+		ctx, err := log.WithTenant(context.Background(), "tenant-1", "123456789012")
+		assert.NoError(t, err)
+		metrics.NewRecordFromContext(ctx, metrics.Dim("Queue", "orders")).Count("OrdersProcessed", 1).Emit()
+	})
+
+	assert.Len(t, records, 1)
+	assert.Equal(t, "tenant-1", records[0][log.TenantId])
+	assert.Equal(t, "123456789012", records[0][log.TenantAccount])
+	directive := records[0]["_aws"].(map[string]interface{})["CloudWatchMetrics"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{[]interface{}{"Queue"}}, directive["Dimensions"])
+}
+
+func TestErrorBudgetExceededMetric(t *testing.T) {
+	records := captureStderr(t, func() {
+		log.Init(log.NewConfiguration(
+			"ERROR",
+			"TEST-APPLICATION",
+			"TEST-PROJECT",
+			"TEST-PROJECT-GROUP",
+			"1.0.0",
+			"testPrefix").
+			WithErrorBudget(1, time.Minute))
+		log.Error("Dependency down")
+	})
+
+	assert.Len(t, records, 2)
+	assert.Equal(t, "Dependency down", records[0][log.Message])
+	assert.Equal(t, 1.0, records[1][metrics.ErrorBudgetExceededMetric])
+}
+
+func TestRuntimeStatsMetrics(t *testing.T) {
+	records := captureStderr(t, func() {
+		log.Init(log.NewConfiguration(
+			"INFO",
+			"TEST-APPLICATION",
+			"TEST-PROJECT",
+			"TEST-PROJECT-GROUP",
+			"1.0.0",
+			"testPrefix").
+			WithRuntimeStatsMetrics(0))
+		log.StartInvocationTimer(context.Background())()
+	})
+
+	assert.Len(t, records, 2)
+	assert.Equal(t, "Invocation finished", records[0][log.Message])
+	assert.Greater(t, records[1][metrics.GoroutinesMetric], 0.0)
+	assert.Greater(t, records[1][metrics.HeapAllocMetric], 0.0)
+	assert.Contains(t, records[1], metrics.GCPauseMetric)
+}
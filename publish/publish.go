@@ -0,0 +1,176 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"go.uber.org/zap"
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBackoff     = 100 * time.Millisecond
+	maxBackoff         = 5 * time.Second
+)
+
+type SNSAPI interface {
+	Publish(ctx context.Context, input *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+type SQSAPI interface {
+	SendMessage(ctx context.Context, input *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+// SendFunc sends a message and returns its id, e.g. a call to PutEvents of EventBridge returning the event id.
+type SendFunc func(ctx context.Context) (messageId string, err error)
+
+// Publisher publishes messages and logs every publish with the logger of its context: a "Message published" record
+// at INFO with the PublishDestination, PublishMessageId, PublishPayload, redacted like every record, PublishDuration
+// and PublishAttempt, or an "Unable to publish message" record at ERROR with the error once the attempts are exhausted.
+// Failed attempts are retried with an exponential backoff and full jitter, on top of the retries of the SDK clients.
+type Publisher struct {
+	sns         SNSAPI
+	sqs         SQSAPI
+	maxAttempts int
+	backoff     time.Duration
+}
+
+func NewPublisher() *Publisher {
+	return &Publisher{maxAttempts: defaultMaxAttempts, backoff: defaultBackoff}
+}
+
+func (p *Publisher) WithSNS(client SNSAPI) *Publisher {
+	p.sns = client
+	return p
+}
+
+func (p *Publisher) WithSQS(client SQSAPI) *Publisher {
+	p.sqs = client
+	return p
+}
+
+// WithRetries sets the number of attempts of every publish, at least 1, and the base of the backoff between them.
+func (p *Publisher) WithRetries(maxAttempts int, backoff time.Duration) *Publisher {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	p.maxAttempts = maxAttempts
+	p.backoff = backoff
+	return p
+}
+
+// PublishSns publishes input with the client of WithSNS, injecting the trace and correlation ids of ctx in its
+// message attributes, see log.InjectTraceSns.
+func (p *Publisher) PublishSns(ctx context.Context, input *sns.PublishInput) (*sns.PublishOutput, error) {
+	if p.sns == nil {
+		return nil, errors.New("unable to publish message: the SNS client is not set, see WithSNS")
+	}
+	log.InjectTraceSns(ctx, input)
+	destination := aws.ToString(input.TopicArn)
+	if destination == "" {
+		destination = aws.ToString(input.TargetArn)
+	}
+	if destination == "" {
+		destination = aws.ToString(input.PhoneNumber)
+	}
+	var output *sns.PublishOutput
+	err := p.publish(ctx, destination, aws.ToString(input.Message), func(ctx context.Context) (string, error) {
+		var err error
+		if output, err = p.sns.Publish(ctx, input); err != nil {
+			return "", err
+		}
+		return aws.ToString(output.MessageId), nil
+	})
+	return output, err
+}
+
+// SendSqs sends input with the client of WithSQS, injecting the trace and correlation ids of ctx in its
+// attributes, see log.InjectTraceSqs.
+func (p *Publisher) SendSqs(ctx context.Context, input *sqs.SendMessageInput) (*sqs.SendMessageOutput, error) {
+	if p.sqs == nil {
+		return nil, errors.New("unable to send message: the SQS client is not set, see WithSQS")
+	}
+	log.InjectTraceSqs(ctx, input)
+	var output *sqs.SendMessageOutput
+	err := p.publish(ctx, aws.ToString(input.QueueUrl), aws.ToString(input.MessageBody), func(ctx context.Context) (string, error) {
+		var err error
+		if output, err = p.sqs.SendMessage(ctx, input); err != nil {
+			return "", err
+		}
+		return aws.ToString(output.MessageId), nil
+	})
+	return output, err
+}
+
+// Send publishes payload to destination with send, retried and logged like PublishSns. It's meant for the clients
+// this module doesn't depend on, like EventBridge: send is responsible for carrying the correlation id of ctx,
+// see log.CorrelationIdFromContext, e.g. in the detail of the event.
+func (p *Publisher) Send(ctx context.Context, destination string, payload string, send SendFunc) (string, error) {
+	var messageId string
+	err := p.publish(ctx, destination, payload, func(ctx context.Context) (string, error) {
+		var err error
+		messageId, err = send(ctx)
+		return messageId, err
+	})
+	return messageId, err
+}
+
+// publish is called by the exported methods, the records are logged on behalf of their caller.
+func (p *Publisher) publish(ctx context.Context, destination string, payload string, send SendFunc) error {
+	logger := log.FromContext(ctx).Desugar().WithOptions(zap.AddCallerSkip(2)).Sugar()
+	start := time.Now()
+	var err error
+	for attempt := 1; attempt <= p.maxAttempts; attempt++ {
+		if attempt > 1 {
+			if waitErr := p.wait(ctx, attempt); waitErr != nil {
+				break
+			}
+		}
+		var messageId string
+		if messageId, err = send(log.WithRetryCount(ctx, attempt-1)); err == nil {
+			logger.Infow("Message published",
+				log.PublishDestination, destination,
+				log.PublishMessageId, messageId,
+				log.PublishPayload, payload,
+				log.PublishDuration, time.Since(start),
+				log.PublishAttempt, attempt)
+			return nil
+		}
+		if attempt < p.maxAttempts {
+			logger.Warnw("Publish attempt failed", append([]interface{}{
+				log.PublishDestination, destination,
+				log.PublishAttempt, attempt,
+			}, log.ErrorFields(err)...)...)
+		}
+	}
+	logger.Errorw("Unable to publish message", append([]interface{}{
+		log.PublishDestination, destination,
+		log.PublishPayload, payload,
+		log.PublishDuration, time.Since(start),
+	}, log.ErrorFields(err)...)...)
+	return err
+}
+
+// wait sleeps a random duration up to the exponential backoff of attempt, it returns early when ctx is done.
+func (p *Publisher) wait(ctx context.Context, attempt int) error {
+	if p.backoff <= 0 {
+		return ctx.Err()
+	}
+	backoff := p.backoff << uint(attempt-2)
+	if backoff <= 0 || backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	timer := time.NewTimer(time.Duration(rand.Int63n(int64(backoff)) + 1))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,102 @@
+package publish_test
+
+import (
+	"context"
+	"errors"
+	"github.com/Ryanair/gofrlib/log"
+	"github.com/Ryanair/gofrlib/logtest"
+	"github.com/Ryanair/gofrlib/publish"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+	"testing"
+)
+
+type snsClient struct {
+	inputs   []*sns.PublishInput
+	failures int
+}
+
+func (c *snsClient) Publish(ctx context.Context, input *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	c.inputs = append(c.inputs, input)
+	if len(c.inputs) <= c.failures {
+		return nil, errors.New("throttled")
+	}
+	return &sns.PublishOutput{MessageId: aws.String("sns-message-1")}, nil
+}
+
+type sqsClient struct {
+	inputs []*sqs.SendMessageInput
+	err    error
+}
+
+func (c *sqsClient) SendMessage(ctx context.Context, input *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	c.inputs = append(c.inputs, input)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &sqs.SendMessageOutput{MessageId: aws.String("sqs-message-1")}, nil
+}
+
+func TestPublishSns(t *testing.T) {
+	recorder := logtest.Capture(t)
+	client := &snsClient{failures: 1}
+	publisher := publish.NewPublisher().WithSNS(client).WithRetries(3, 0)
+	ctx := log.ContextWithCorrelationId(context.Background(), "correlation-1")
+
+	output, err := publisher.PublishSns(ctx, &sns.PublishInput{
+		TopicArn: aws.String("arn:aws:sns:eu-west-1:123456789012:bookings"),
+		Message:  aws.String(`{"id":"booking-1"}`),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sns-message-1", aws.ToString(output.MessageId))
+	assert.Len(t, client.inputs, 2)
+	assert.Equal(t, "correlation-1", aws.ToString(client.inputs[0].MessageAttributes[log.CorrelationIdAttribute].StringValue))
+	recorder.AssertLogged(zapcore.WarnLevel, "Publish attempt failed")
+	recorder.AssertLogged(zapcore.InfoLevel, "Message published")
+	recorder.AssertField(log.PublishDestination, "arn:aws:sns:eu-west-1:123456789012:bookings")
+	recorder.AssertField(log.PublishMessageId, "sns-message-1")
+	recorder.AssertField(log.PublishPayload, `{"id":"booking-1"}`)
+	recorder.AssertField(log.PublishAttempt, 2)
+	recorder.AssertField(log.CorrelationId, "correlation-1")
+}
+
+func TestSendSqsFails(t *testing.T) {
+	recorder := logtest.Capture(t)
+	client := &sqsClient{err: errors.New("access denied")}
+	publisher := publish.NewPublisher().WithSQS(client).WithRetries(2, 0)
+
+	_, err := publisher.SendSqs(context.Background(), &sqs.SendMessageInput{
+		QueueUrl:    aws.String("https://sqs.eu-west-1.amazonaws.com/123456789012/bookings"),
+		MessageBody: aws.String(`{"id":"booking-1"}`),
+	})
+
+	assert.EqualError(t, err, "access denied")
+	assert.Len(t, client.inputs, 2)
+	recorder.AssertLogged(zapcore.ErrorLevel, "Unable to publish message")
+	recorder.AssertField(log.PublishDestination, "https://sqs.eu-west-1.amazonaws.com/123456789012/bookings")
+}
+
+func TestSend(t *testing.T) {
+	recorder := logtest.Capture(t)
+	publisher := publish.NewPublisher()
+
+	messageId, err := publisher.Send(context.Background(), "bookings-bus", `{"id":"booking-1"}`, func(ctx context.Context) (string, error) {
+		return "event-1", nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "event-1", messageId)
+	recorder.AssertField(log.PublishDestination, "bookings-bus")
+	recorder.AssertField(log.PublishMessageId, "event-1")
+	recorder.AssertField(log.PublishAttempt, 1)
+}
+
+func TestPublishWithoutClient(t *testing.T) {
+	_, err := publish.NewPublisher().PublishSns(context.Background(), &sns.PublishInput{})
+
+	assert.Error(t, err)
+}
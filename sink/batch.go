@@ -0,0 +1,269 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBatchSize     = 500
+	defaultFlushInterval = time.Second
+	defaultMaxBuffered   = 10000
+	defaultMaxRetries    = 3
+	defaultBackoff       = 100 * time.Millisecond
+)
+
+// Record is a single encoded log entry waiting to be shipped.
+type Record struct {
+	Data      []byte
+	Timestamp time.Time
+}
+
+// Shipper delivers a batch of records to a remote destination. It returns the records that were
+// not accepted so they can be retried, together with the error that caused the failure.
+type Shipper interface {
+	Ship(ctx context.Context, records []Record) (failed []Record, err error)
+	MaxBatchSize() int
+}
+
+// ByteLimits are the size limits of a destination, in bytes.
+type ByteLimits struct {
+	// MaxBatch is the largest size of a batch, the sum of the sizes of its records.
+	MaxBatch int
+	// MaxRecord is the largest data of a single record, larger records are dropped.
+	MaxRecord int
+	// RecordOverhead is added to the size of the data of every record, e.g. the 26 bytes CloudWatch Logs counts per event.
+	RecordOverhead int
+}
+
+// ByteLimitedShipper is a Shipper reporting the size limits of its destination, so the BatchWriter splits the batches
+// by size too and drops the records that would be rejected anyway.
+type ByteLimitedShipper interface {
+	Shipper
+	ByteLimits() ByteLimits
+}
+
+// BatchWriter is a zapcore.WriteSyncer buffering records in memory and shipping them in the background
+// once a batch is full or the flush interval elapses. Sync ships everything synchronously, so calling
+// log.Flush at the end of every invocation guarantees nothing is lost when the Lambda environment is frozen.
+type BatchWriter struct {
+	shipper       Shipper
+	batchSize     int
+	flushInterval time.Duration
+	maxBuffered   int
+	maxRetries    int
+	backoff       time.Duration
+	backpressure  bool
+	limits        ByteLimits
+
+	mutex    sync.Mutex
+	buffer   []Record
+	dropped  int
+	oversize int
+	shipMu   sync.Mutex
+	trigger  chan struct{}
+	done     chan struct{}
+	started  sync.Once
+	closed   sync.Once
+	stopped  sync.WaitGroup
+}
+
+func NewBatchWriter(shipper Shipper) *BatchWriter {
+	batchSize := defaultBatchSize
+	if max := shipper.MaxBatchSize(); max > 0 && max < batchSize {
+		batchSize = max
+	}
+	var limits ByteLimits
+	if limited, ok := shipper.(ByteLimitedShipper); ok {
+		limits = limited.ByteLimits()
+	}
+	return &BatchWriter{
+		shipper:       shipper,
+		limits:        limits,
+		batchSize:     batchSize,
+		flushInterval: defaultFlushInterval,
+		maxBuffered:   defaultMaxBuffered,
+		maxRetries:    defaultMaxRetries,
+		backoff:       defaultBackoff,
+		trigger:       make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+}
+
+func (w *BatchWriter) WithFlushInterval(interval time.Duration) *BatchWriter {
+	w.flushInterval = interval
+	return w
+}
+
+func (w *BatchWriter) WithMaxBuffered(maxBuffered int) *BatchWriter {
+	w.maxBuffered = maxBuffered
+	return w
+}
+
+// WithRetries sets how many times a failed batch is retried, waiting backoff, then twice as long, and so on.
+func (w *BatchWriter) WithRetries(maxRetries int, backoff time.Duration) *BatchWriter {
+	w.maxRetries = maxRetries
+	w.backoff = backoff
+	return w
+}
+
+// WithBackpressure makes Write ship the buffer and wait for it when the buffer limit is reached, instead of
+// dropping the record, so a slow destination slows down the callers rather than losing records.
+// Records are still dropped when the buffer can't be shipped.
+func (w *BatchWriter) WithBackpressure() *BatchWriter {
+	w.backpressure = true
+	return w
+}
+
+// Write buffers a copy of p, records beyond the buffer limit are dropped and reported on the next ship,
+// see WithBackpressure. So are the records larger than the ByteLimits of the shipper, see log.WithMaxEntrySize
+// to truncate them instead.
+func (w *BatchWriter) Write(p []byte) (int, error) {
+	w.started.Do(w.start)
+	if w.limits.MaxRecord > 0 && len(p) > w.limits.MaxRecord {
+		w.mutex.Lock()
+		w.oversize++
+		w.mutex.Unlock()
+		return len(p), nil
+	}
+	record := Record{Data: append([]byte{}, p...), Timestamp: time.Now()}
+	w.mutex.Lock()
+	if w.backpressure && len(w.buffer) >= w.maxBuffered {
+		w.mutex.Unlock()
+		if err := w.ship(); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to ship log records: %+v\n", err)
+		}
+		w.mutex.Lock()
+	}
+	if len(w.buffer) >= w.maxBuffered {
+		w.dropped++
+	} else {
+		w.buffer = append(w.buffer, record)
+	}
+	full := len(w.buffer) >= w.batchSize
+	w.mutex.Unlock()
+	if full {
+		select {
+		case w.trigger <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Sync ships all buffered records and waits for the result.
+func (w *BatchWriter) Sync() error {
+	return w.ship()
+}
+
+// Close stops the background shipping, flushes the buffer and closes the shipper when it holds a connection.
+// Later calls do nothing.
+func (w *BatchWriter) Close() error {
+	var err error
+	w.closed.Do(func() {
+		w.started.Do(func() {})
+		close(w.done)
+		w.stopped.Wait()
+		err = w.ship()
+		if closer, ok := w.shipper.(io.Closer); ok {
+			if closeErr := closer.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
+}
+
+// start launches the background shipping on the first write, once the writer is fully configured
+func (w *BatchWriter) start() {
+	w.stopped.Add(1)
+	go w.run()
+}
+
+func (w *BatchWriter) run() {
+	defer w.stopped.Done()
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+		case <-w.trigger:
+		}
+		if err := w.ship(); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to ship log records: %+v\n", err)
+		}
+	}
+}
+
+func (w *BatchWriter) ship() error {
+	w.shipMu.Lock()
+	defer w.shipMu.Unlock()
+	w.mutex.Lock()
+	records := w.buffer
+	dropped := w.dropped
+	oversize := w.oversize
+	w.buffer = nil
+	w.dropped = 0
+	w.oversize = 0
+	w.mutex.Unlock()
+
+	if dropped > 0 {
+		fmt.Fprintf(os.Stderr, "dropped %d log records, buffer limit of %d reached\n", dropped, w.maxBuffered)
+	}
+	if oversize > 0 {
+		fmt.Fprintf(os.Stderr, "dropped %d log records larger than %d bytes\n", oversize, w.limits.MaxRecord)
+	}
+	var lastErr error
+	for _, batch := range w.batches(records) {
+		if err := w.shipWithRetries(batch); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// batches splits records in batches of at most batchSize records and MaxBatch bytes.
+func (w *BatchWriter) batches(records []Record) [][]Record {
+	var batches [][]Record
+	start, size := 0, 0
+	for i, record := range records {
+		recordSize := len(record.Data) + w.limits.RecordOverhead
+		full := i-start == w.batchSize || w.limits.MaxBatch > 0 && size+recordSize > w.limits.MaxBatch
+		if full && i > start {
+			batches = append(batches, records[start:i])
+			start, size = i, 0
+		}
+		size += recordSize
+	}
+	if start < len(records) {
+		batches = append(batches, records[start:])
+	}
+	return batches
+}
+
+func (w *BatchWriter) shipWithRetries(batch []Record) error {
+	backoff := w.backoff
+	for attempt := 0; ; attempt++ {
+		failed, err := w.shipper.Ship(context.Background(), batch)
+		if err == nil && len(failed) == 0 {
+			return nil
+		}
+		if len(failed) > 0 {
+			batch = failed
+		}
+		if attempt >= w.maxRetries {
+			if err == nil {
+				err = fmt.Errorf("%d records rejected", len(failed))
+			}
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,134 @@
+package sink_test
+
+import (
+	"context"
+	"errors"
+	"github.com/Ryanair/gofrlib/sink"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeShipper struct {
+	mutex    sync.Mutex
+	batches  [][]sink.Record
+	failures int
+}
+
+func (s *fakeShipper) MaxBatchSize() int {
+	return 2
+}
+
+func (s *fakeShipper) Ship(_ context.Context, records []sink.Record) ([]sink.Record, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return records, errors.New("ship error")
+	}
+	s.batches = append(s.batches, records)
+	return nil, nil
+}
+
+func TestBatchWriterShipsOnSync(t *testing.T) {
+	shipper := &fakeShipper{}
+	writer := sink.NewBatchWriter(shipper).WithFlushInterval(time.Hour)
+	defer writer.Close()
+
+	for _, record := range []string{"1", "2", "3"} {
+		_, err := writer.Write([]byte(record))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Sync())
+
+	var shipped []string
+	for _, batch := range shipper.batches {
+		assert.True(t, len(batch) <= 2)
+		for _, record := range batch {
+			shipped = append(shipped, string(record.Data))
+		}
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, shipped)
+}
+
+func TestBatchWriterRetriesFailedBatches(t *testing.T) {
+	shipper := &fakeShipper{failures: 2}
+	writer := sink.NewBatchWriter(shipper).WithFlushInterval(time.Hour).WithRetries(2, time.Millisecond)
+	defer writer.Close()
+
+	_, _ = writer.Write([]byte("1"))
+
+	assert.NoError(t, writer.Sync())
+	assert.Len(t, shipper.batches, 1)
+}
+
+func TestBatchWriterGivesUpAfterRetries(t *testing.T) {
+	shipper := &fakeShipper{failures: 5}
+	writer := sink.NewBatchWriter(shipper).WithFlushInterval(time.Hour).WithRetries(1, time.Millisecond)
+	defer writer.Close()
+
+	_, _ = writer.Write([]byte("1"))
+
+	assert.EqualError(t, writer.Sync(), "ship error")
+}
+
+func TestBatchWriterBackpressure(t *testing.T) {
+	shipper := &fakeShipper{}
+	writer := sink.NewBatchWriter(shipper).WithFlushInterval(time.Hour).WithMaxBuffered(2).WithBackpressure()
+	defer writer.Close()
+
+	for _, record := range []string{"1", "2", "3", "4", "5"} {
+		_, _ = writer.Write([]byte(record))
+	}
+	assert.NoError(t, writer.Sync())
+
+	var shipped []string
+	for _, batch := range shipper.batches {
+		for _, record := range batch {
+			shipped = append(shipped, string(record.Data))
+		}
+	}
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, shipped)
+}
+
+type byteLimitedShipper struct {
+	fakeShipper
+}
+
+func (s *byteLimitedShipper) MaxBatchSize() int {
+	return 10
+}
+
+func (s *byteLimitedShipper) ByteLimits() sink.ByteLimits {
+	return sink.ByteLimits{MaxBatch: 10, MaxRecord: 6, RecordOverhead: 1}
+}
+
+func TestBatchWriterSplitsBatchesBySize(t *testing.T) {
+	shipper := &byteLimitedShipper{}
+	writer := sink.NewBatchWriter(shipper).WithFlushInterval(time.Hour)
+	defer writer.Close()
+
+	for _, record := range []string{"1234", "1234", "123", "1234567", "1", "123456"} {
+		_, _ = writer.Write([]byte(record))
+	}
+	assert.NoError(t, writer.Sync())
+
+	var batches [][]string
+	for _, batch := range shipper.batches {
+		var records []string
+		for _, record := range batch {
+			records = append(records, string(record.Data))
+		}
+		batches = append(batches, records)
+	}
+	assert.Equal(t, [][]string{{"1234", "1234"}, {"123", "1"}, {"123456"}}, batches)
+}
+
+func TestBatchWriterCloseTwice(t *testing.T) {
+	writer := sink.NewBatchWriter(&fakeShipper{})
+	_, _ = writer.Write([]byte("1"))
+
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, writer.Close())
+}
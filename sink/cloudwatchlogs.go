@@ -0,0 +1,83 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	cloudWatchLogsMaxBatchSize = 10000
+	// the limits of PutLogEvents, every event counts 26 bytes on top of its message
+	cloudWatchLogsMaxBatchBytes = 1048576
+	cloudWatchLogsEventOverhead = 26
+	cloudWatchLogsMaxEventBytes = 256*1024 - cloudWatchLogsEventOverhead
+)
+
+type CloudWatchLogsAPI interface {
+	PutLogEvents(ctx context.Context, input *cloudwatchlogs.PutLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error)
+}
+
+type cloudWatchLogsShipper struct {
+	client        CloudWatchLogsAPI
+	logGroup      string
+	logStream     string
+	mutex         sync.Mutex
+	sequenceToken *string
+}
+
+// NewCloudWatchLogsShipper ships records to an existing log stream of a CloudWatch Logs log group,
+// a ByteLimitedShipper with the limits of PutLogEvents.
+func NewCloudWatchLogsShipper(client CloudWatchLogsAPI, logGroup, logStream string) ByteLimitedShipper {
+	return &cloudWatchLogsShipper{client: client, logGroup: logGroup, logStream: logStream}
+}
+
+func (s *cloudWatchLogsShipper) MaxBatchSize() int {
+	return cloudWatchLogsMaxBatchSize
+}
+
+func (s *cloudWatchLogsShipper) ByteLimits() ByteLimits {
+	return ByteLimits{
+		MaxBatch:       cloudWatchLogsMaxBatchBytes,
+		MaxRecord:      cloudWatchLogsMaxEventBytes,
+		RecordOverhead: cloudWatchLogsEventOverhead,
+	}
+}
+
+func (s *cloudWatchLogsShipper) Ship(ctx context.Context, records []Record) ([]Record, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// CloudWatch Logs rejects batches that are not in chronological order
+	sorted := append([]Record{}, records...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+	input := &cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(s.logGroup),
+		LogStreamName: aws.String(s.logStream),
+		LogEvents:     make([]types.InputLogEvent, len(sorted)),
+		SequenceToken: s.sequenceToken,
+	}
+	for i, record := range sorted {
+		input.LogEvents[i] = types.InputLogEvent{
+			Message:   aws.String(strings.TrimRight(string(record.Data), "\n")),
+			Timestamp: aws.Int64(record.Timestamp.UnixNano() / 1e6),
+		}
+	}
+	output, err := s.client.PutLogEvents(ctx, input)
+	if err != nil {
+		var invalidToken *types.InvalidSequenceTokenException
+		if errors.As(err, &invalidToken) {
+			s.sequenceToken = invalidToken.ExpectedSequenceToken
+		}
+		return records, err
+	}
+	s.sequenceToken = output.NextSequenceToken
+	return nil, nil
+}
@@ -0,0 +1,62 @@
+package sink
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/firehose"
+	"github.com/aws/aws-sdk-go-v2/service/firehose/types"
+)
+
+const (
+	firehoseMaxBatchSize = 500
+	// the limits of PutRecordBatch
+	firehoseMaxBatchBytes  = 4 * 1024 * 1024
+	firehoseMaxRecordBytes = 1000 * 1024
+)
+
+type FirehoseAPI interface {
+	PutRecordBatch(ctx context.Context, input *firehose.PutRecordBatchInput, optFns ...func(*firehose.Options)) (*firehose.PutRecordBatchOutput, error)
+}
+
+type firehoseShipper struct {
+	client         FirehoseAPI
+	deliveryStream string
+}
+
+// NewFirehoseShipper ships records to a Kinesis Firehose delivery stream, one json line per Firehose record,
+// a ByteLimitedShipper with the limits of PutRecordBatch.
+func NewFirehoseShipper(client FirehoseAPI, deliveryStream string) ByteLimitedShipper {
+	return &firehoseShipper{client: client, deliveryStream: deliveryStream}
+}
+
+func (s *firehoseShipper) MaxBatchSize() int {
+	return firehoseMaxBatchSize
+}
+
+func (s *firehoseShipper) ByteLimits() ByteLimits {
+	return ByteLimits{MaxBatch: firehoseMaxBatchBytes, MaxRecord: firehoseMaxRecordBytes}
+}
+
+func (s *firehoseShipper) Ship(ctx context.Context, records []Record) ([]Record, error) {
+	input := &firehose.PutRecordBatchInput{
+		DeliveryStreamName: aws.String(s.deliveryStream),
+		Records:            make([]types.Record, len(records)),
+	}
+	for i, record := range records {
+		input.Records[i] = types.Record{Data: record.Data}
+	}
+	output, err := s.client.PutRecordBatch(ctx, input)
+	if err != nil {
+		return records, err
+	}
+	if output.FailedPutCount == nil || *output.FailedPutCount == 0 {
+		return nil, nil
+	}
+	var failed []Record
+	for i, response := range output.RequestResponses {
+		if response.ErrorCode != nil && i < len(records) {
+			failed = append(failed, records[i])
+		}
+	}
+	return failed, nil
+}
@@ -0,0 +1,106 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	tcpMaxBatchSize = 1000
+	tcpTimeout      = 5 * time.Second
+)
+
+type tcpShipper struct {
+	address   string
+	tlsConfig *tls.Config
+	mutex     sync.Mutex
+	conn      net.Conn
+}
+
+// NewTCPShipper ships records as json lines over a TCP connection to address, e.g. the tcp input of Logstash with
+// the json_lines codec or the socket source of Vector, for VPCs without HTTP egress. The connection is encrypted
+// when tlsConfig isn't nil. It's opened on the first batch and reopened on the next attempt when a write fails.
+// Every batch is written at once and retried as a whole by the BatchWriter when the write fails: a line cut by the
+// failure can only end its connection and is sent whole on the next one, the lines before it can be received twice.
+func NewTCPShipper(address string, tlsConfig *tls.Config) Shipper {
+	return &tcpShipper{address: address, tlsConfig: tlsConfig}
+}
+
+func (s *tcpShipper) MaxBatchSize() int {
+	return tcpMaxBatchSize
+}
+
+func (s *tcpShipper) Ship(ctx context.Context, records []Record) ([]Record, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return records, err
+		}
+		s.conn = conn
+	}
+	deadline := time.Now().Add(tcpTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = s.conn.SetWriteDeadline(deadline)
+	if _, err := s.conn.Write(jsonLines(records)); err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		return records, err
+	}
+	return nil, nil
+}
+
+// jsonLines is the payload of a batch, one json line per record.
+func jsonLines(records []Record) []byte {
+	var payload bytes.Buffer
+	for _, record := range records {
+		payload.Write(record.Data)
+		if !bytes.HasSuffix(record.Data, []byte("\n")) {
+			payload.WriteByte('\n')
+		}
+	}
+	return payload.Bytes()
+}
+
+func (s *tcpShipper) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: tcpTimeout, KeepAlive: 30 * time.Second}
+	if s.tlsConfig == nil {
+		return dialer.DialContext(ctx, "tcp", s.address)
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		return nil, err
+	}
+	config := s.tlsConfig
+	if config.ServerName == "" && !config.InsecureSkipVerify {
+		config = config.Clone()
+		config.ServerName, _, _ = net.SplitHostPort(s.address)
+	}
+	tlsConn := tls.Client(conn, config)
+	_ = tlsConn.SetDeadline(time.Now().Add(tcpTimeout))
+	if err := tlsConn.Handshake(); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = tlsConn.SetDeadline(time.Time{})
+	return tlsConn, nil
+}
+
+// Close closes the connection, BatchWriter.Close calls it once the buffer is flushed.
+func (s *tcpShipper) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
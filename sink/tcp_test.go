@@ -0,0 +1,112 @@
+package sink_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/Ryanair/gofrlib/sink"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// acceptLines sends the lines received by listener, one connection after the other.
+func acceptLines(listener net.Listener, connections int) <-chan string {
+	lines := make(chan string, 10)
+	go func() {
+		for i := 0; i < connections; i++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			scanner := bufio.NewScanner(conn)
+			if i < connections-1 {
+				// the first connections are closed after a line, the shipper must reconnect
+				if scanner.Scan() {
+					lines <- scanner.Text()
+				}
+				_ = conn.Close()
+				continue
+			}
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+			_ = conn.Close()
+		}
+	}()
+	return lines
+}
+
+func receive(t *testing.T, lines <-chan string) string {
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(time.Second):
+		t.Fatal("no line received")
+		return ""
+	}
+}
+
+func TestTCPShipperShipsJsonLines(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	lines := acceptLines(listener, 1)
+
+	writer := sink.NewBatchWriter(sink.NewTCPShipper(listener.Addr().String(), nil)).WithFlushInterval(time.Hour)
+	_, _ = writer.Write([]byte(`{"message":"first"}` + "\n"))
+	_, _ = writer.Write([]byte(`{"message":"second"}`))
+	assert.NoError(t, writer.Sync())
+	assert.NoError(t, writer.Close())
+
+	assert.Equal(t, `{"message":"first"}`, receive(t, lines))
+	assert.Equal(t, `{"message":"second"}`, receive(t, lines))
+}
+
+func TestTCPShipperReconnects(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	lines := acceptLines(listener, 2)
+
+	writer := sink.NewBatchWriter(sink.NewTCPShipper(listener.Addr().String(), nil)).
+		WithFlushInterval(time.Hour).
+		WithRetries(5, 10*time.Millisecond)
+	defer writer.Close()
+	_, _ = writer.Write([]byte(`{"message":"before"}` + "\n"))
+	assert.NoError(t, writer.Sync())
+	assert.Equal(t, `{"message":"before"}`, receive(t, lines))
+
+	// writes to the closed connection fail once its reset is received
+	assert.Eventually(t, func() bool {
+		_, _ = writer.Write([]byte(`{"message":"after"}` + "\n"))
+		assert.NoError(t, writer.Sync())
+		select {
+		case line := <-lines:
+			return line == `{"message":"after"}`
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestTCPShipperWithTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: server.TLS.Certificates})
+	assert.NoError(t, err)
+	defer listener.Close()
+	lines := acceptLines(listener, 1)
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	writer := sink.NewBatchWriter(sink.NewTCPShipper(listener.Addr().String(), &tls.Config{RootCAs: roots})).WithFlushInterval(time.Hour)
+	defer writer.Close()
+	_, _ = writer.Write([]byte(`{"message":"encrypted"}` + "\n"))
+	assert.NoError(t, writer.Sync())
+
+	assert.Equal(t, `{"message":"encrypted"}`, receive(t, lines))
+}